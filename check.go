@@ -0,0 +1,184 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DiffKind kind of generated file change
+type DiffKind string
+
+const (
+	// DiffAdded file would be created
+	DiffAdded DiffKind = "A"
+	// DiffChanged file would be rewritten with different content
+	DiffChanged DiffKind = "M"
+	// DiffRemoved generated file is not generated anymore
+	DiffRemoved DiffKind = "D"
+)
+
+// FileDiff difference between generated code and file on disk
+type FileDiff struct {
+	Path          string
+	Kind          DiffKind
+	Model         string   // model struct name, only set for model file
+	AddedFields   []string // model fields not on disk
+	RemovedFields []string // model fields on disk not generated anymore
+}
+
+// String diff in "M path (+Field -Field)" format
+func (d FileDiff) String() string {
+	var fields []string
+	for _, f := range d.AddedFields {
+		fields = append(fields, "+"+f)
+	}
+	for _, f := range d.RemovedFields {
+		fields = append(fields, "-"+f)
+	}
+	if len(fields) == 0 {
+		return fmt.Sprintf("%s %s", d.Kind, d.Path)
+	}
+	return fmt.Sprintf("%s %s (%s)", d.Kind, d.Path, strings.Join(fields, " "))
+}
+
+// renderedFiles files rendered in memory by check mode
+type renderedFiles struct {
+	sync.Mutex
+	files map[string][]byte
+}
+
+// ExecuteCheck render code in memory and compare with files on disk, nothing is written.
+// Empty result means generated code is up to date.
+func (g *Generator) ExecuteCheck() ([]FileDiff, error) {
+	g.rendered = &renderedFiles{files: make(map[string][]byte)}
+	defer func() { g.rendered = nil }()
+
+	if err := g.generate(); err != nil {
+		return nil, err
+	}
+	return g.diff()
+}
+
+// diff compare rendered files with files on disk
+func (g *Generator) diff() (diffs []FileDiff, err error) {
+	models, dirs := make(map[string]string), make(map[string]bool)
+	if err = g.collectOutputs(models, dirs); err != nil {
+		return nil, err
+	}
+
+	for path, content := range g.rendered.files {
+		dirs[filepath.Dir(path)] = true
+		existing, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+			diffs = append(diffs, fileDiff(path, DiffAdded, models[path], nil, content))
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(existing, content):
+			diffs = append(diffs, fileDiff(path, DiffChanged, models[path], existing, content))
+		}
+	}
+
+	for dir := range dirs {
+		for _, pattern := range []string{"*.gen.go", "*.gen_test.go"} {
+			files, err := filepath.Glob(filepath.Join(dir, pattern))
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range files {
+				if _, ok := g.rendered.files[path]; !ok {
+					diffs = append(diffs, FileDiff{Path: path, Kind: DiffRemoved})
+				}
+			}
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs, nil
+}
+
+// collectOutputs model files with model names, and output dirs of generator and schema generators
+func (g *Generator) collectOutputs(models map[string]string, dirs map[string]bool) error {
+	dirs[g.OutPath] = true
+	if len(g.models) > 0 {
+		modelOutPath, err := g.getModelOutputPath()
+		if err != nil {
+			return err
+		}
+		dirs[filepath.Clean(modelOutPath)] = true
+		for _, data := range g.models {
+			if data != nil && data.Generated {
+				models[modelOutPath+data.FileName+".gen.go"] = data.ModelStructName
+			}
+		}
+	}
+	for _, child := range g.schemaGens {
+		if err := child.collectOutputs(models, dirs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileDiff diff of file, fields of model struct are compared
+func fileDiff(path string, kind DiffKind, modelName string, existing, content []byte) FileDiff {
+	d := FileDiff{Path: path, Kind: kind, Model: modelName}
+	if modelName == "" {
+		return d
+	}
+	oldFields, newFields := structFields(existing, modelName), structFields(content, modelName)
+	d.AddedFields, d.RemovedFields = subtract(newFields, oldFields), subtract(oldFields, newFields)
+	return d
+}
+
+// structFields field names of struct type declared in go source
+func structFields(src []byte, typeName string) (fields []string) {
+	if len(src) == 0 {
+		return nil
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != typeName {
+			return true
+		}
+		if st, ok := spec.Type.(*ast.StructType); ok {
+			for _, field := range st.Fields.List {
+				for _, name := range field.Names {
+					fields = append(fields, name.Name)
+				}
+				if len(field.Names) == 0 { // embedded
+					fields = append(fields, strings.TrimPrefix(types.ExprString(field.Type), "*"))
+				}
+			}
+		}
+		return false
+	})
+	return fields
+}
+
+// subtract elements of a not in b, in order of a
+func subtract(a, b []string) (result []string) {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	for _, s := range a {
+		if !in[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
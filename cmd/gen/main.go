@@ -0,0 +1,158 @@
+// Command gen generates models and query code from PostgreSQL with yaml config.
+//
+//	gen generate -dsn "host=localhost user=postgres dbname=app" -c gen.yaml
+//	GEN_DSN="..." gen diff -c gen.yaml
+//	gen list-tables -c gen.yaml
+//	gen generate -ddl schema.sql -c gen.yaml
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"go.ipao.vip/gen"
+)
+
+// errStale generated code differs from database
+var errStale = errors.New("generated code is stale, run gen generate")
+
+// dsnEnv environment variable of dsn, used when -dsn is not specified
+const dsnEnv = "GEN_DSN"
+
+var commands = map[string]func(db *gorm.DB, opt *gen.ConfigOpt) error{
+	"generate":    generate,
+	"diff":        diff,
+	"list-tables": listTables,
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: gen <command> [flags]
+
+Commands:
+  generate     generate models and query code
+  diff         list files changed by generate without writing them, exit with 1 if any
+  list-tables  list tables to generate
+
+Flags:
+  -dsn string  PostgreSQL DSN, default $%s
+  -ddl string  comma separated SQL DDL files or migration directories, used instead of database
+  -c string    yaml config file
+`, dsnEnv)
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		usage()
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.Usage = usage
+	dsn := flags.String("dsn", os.Getenv(dsnEnv), "PostgreSQL DSN")
+	ddlPaths := flags.String("ddl", "", "comma separated SQL DDL files or migration directories")
+	configFile := flags.String("c", "", "yaml config file")
+	_ = flags.Parse(os.Args[2:])
+
+	if *dsn == "" && *ddlPaths == "" {
+		fmt.Fprintf(os.Stderr, "dsn is required, use -dsn or $%s, or -ddl to generate from DDL\n", dsnEnv)
+		os.Exit(2)
+	}
+
+	opt := &gen.ConfigOpt{}
+	if *configFile != "" {
+		var err error
+		if opt, err = gen.LoadConfigOpt(*configFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	db, err := openDB(*dsn, *ddlPaths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err = commands[command](db, opt); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// openDB open DDL files if specified, otherwise connect database
+func openDB(dsn, ddlPaths string) (*gorm.DB, error) {
+	if ddlPaths != "" {
+		db, err := gen.OpenDDL(strings.Split(ddlPaths, ",")...)
+		if err != nil {
+			return nil, fmt.Errorf("open DDL fail: %w", err)
+		}
+		db.Logger = logger.Default.LogMode(logger.Warn)
+		return db, nil
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+	if err != nil {
+		return nil, fmt.Errorf("connect database fail: %w", err)
+	}
+	return db, nil
+}
+
+func generate(db *gorm.DB, opt *gen.ConfigOpt) error {
+	g, err := opt.NewGenerator(db)
+	if err != nil {
+		return err
+	}
+	models, err := opt.GenerateModels(g)
+	if err != nil {
+		return err
+	}
+	g.GenerateModels(models...)
+	g.Execute()
+	return nil
+}
+
+func listTables(db *gorm.DB, opt *gen.ConfigOpt) error {
+	if _, err := opt.Config(); err != nil { // compile ignore patterns
+		return err
+	}
+	tables, err := opt.ListTables(db)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		fmt.Println(table)
+	}
+	return nil
+}
+
+// diff render code in memory and compare with generated files
+func diff(db *gorm.DB, opt *gen.ConfigOpt) error {
+	g, err := opt.NewGenerator(db)
+	if err != nil {
+		return err
+	}
+	models, err := opt.GenerateModels(g)
+	if err != nil {
+		return err
+	}
+	g.GenerateModels(models...)
+
+	diffs, err := g.ExecuteCheck()
+	if err != nil {
+		return err
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	return errStale
+}
@@ -26,6 +26,17 @@ const (
 	WithQueryInterface
 )
 
+// SchemaLayout layout of code generated for specified schemas
+type SchemaLayout uint
+
+const (
+	// SchemaPrefix generate into the same package, model and file names are prefixed with schema name
+	SchemaPrefix SchemaLayout = iota
+
+	// SchemaSubPackage generate into sub package named after schema, e.g. models/billing
+	SchemaSubPackage
+)
+
 // Config generator's basic configuration
 type Config struct {
 	db *gorm.DB // db connection
@@ -41,11 +52,14 @@ type Config struct {
 	FieldSignable     bool // detect integer field's unsigned type, adjust generated data type
 	FieldWithIndexTag bool // generate with gorm index tag
 	FieldWithTypeTag  bool // generate with gorm column type tag
+	FieldWithRelation bool // generate relation fields inferred from foreign keys, only work when generating all tables
 
-	Mode GenerateMode // generate mode
+	Mode         GenerateMode // generate mode
+	SchemaLayout SchemaLayout // layout of code generated by GenerateModelIn/GenerateAllTableIn
 
 	queryPkgName   string // generated query code's package name
 	modelPkgPath   string // model pkg path in target project
+	queryPkgPath   string // query pkg path in target project, only filled for schema sub package
 	dbNameOpts     []model.SchemaNameOpt
 	importPkgPaths []string
 
@@ -53,6 +67,8 @@ type Config struct {
 	tableNameNS func(tableName string) (targetTableName string)
 	modelNameNS func(tableName string) (modelName string)
 	fileNameNS  func(tableName string) (fileName string)
+	enumNameNS  func(enumName string) (typeName string)
+	relationNS  func(rel RelationInfo) (fieldName string)
 
 	dataTypeMap    map[string]func(columnType gorm.ColumnType) (dataType string)
 	fieldJSONTagNS func(columnName string) (tagContent string)
@@ -93,6 +109,17 @@ func (cfg *Config) WithFileNameStrategy(ns func(tableName string) (fileName stri
 	cfg.fileNameNS = ns
 }
 
+// WithEnumNameStrategy specify generated enum type naming strategy, only work when syncing table from db
+func (cfg *Config) WithEnumNameStrategy(ns func(enumName string) (typeName string)) {
+	cfg.enumNameNS = ns
+}
+
+// WithRelationNameStrategy specify name of relation field inferred from foreign key, return "" to skip the relation,
+// only work when FieldWithRelation is enabled
+func (cfg *Config) WithRelationNameStrategy(ns func(rel RelationInfo) (fieldName string)) {
+	cfg.relationNS = ns
+}
+
 // WithDataTypeMap specify data type mapping relationship, only work when syncing table from db
 func (cfg *Config) WithDataTypeMap(newMap map[string]func(columnType gorm.ColumnType) (dataType string)) {
 	cfg.dataTypeMap = newMap
@@ -0,0 +1,166 @@
+package gen
+
+import (
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/field"
+)
+
+// withClauseName key of WITH clause in statement clauses, it's not built by itself
+// but before the first clause of SELECT, INSERT, UPDATE and DELETE statements
+const withClauseName = "WITH"
+
+// CTETable reference of common table expression declared by With or WithRecursive
+//
+//	recent := gen.CTE("recent")
+//	o := query.Order
+//	orders, err := o.With(recent.TableName(), o.Where(o.CreatedAt.Gt(since))).
+//		Join(recent, o.ID.EqCol(field.NewInt64(recent.TableName(), "id"))).
+//		Find()
+type CTETable struct {
+	name string
+}
+
+var _ SubQuery = CTETable{}
+
+// CTE reference common table expression by name, used as table in Join and Table,
+// fields of it are created by field.NewField(name, column) and typed field.NewXxx(name, column)
+func CTE(name string) CTETable { return CTETable{name: name} }
+
+// TableName return name of common table expression
+func (t CTETable) TableName() string { return t.name }
+
+func (t CTETable) underlyingDB() *gorm.DB { return nil }
+
+func (t CTETable) underlyingDO() *DO { return &DO{tableName: t.name} }
+
+func (t CTETable) fromExpr() clause.Expression {
+	return clause.Expr{SQL: "?", Vars: []interface{}{clause.Table{Name: t.name}}}
+}
+
+// BeCond implements Condition
+func (t CTETable) BeCond() interface{} { return nil }
+
+// CondError implements Condition, a CTE reference is not a condition
+func (t CTETable) CondError() error {
+	return errors.New("CTE " + t.name + " cannot be used as condition")
+}
+
+// With add common table expression named name to WITH clause, e.g.
+// WITH name AS (q) SELECT ...
+func (d *DO) With(name string, q SubQuery) Dao {
+	return d.with(withClause{ctes: []commonTable{{name: name, queries: []*gorm.DB{q.underlyingDB()}}}}, q)
+}
+
+// WithRecursive add recursive common table expression named name to WITH clause, e.g.
+// WITH RECURSIVE name AS (anchor UNION ALL recursive) SELECT ...
+func (d *DO) WithRecursive(name string, anchor, recursive SubQuery) Dao {
+	return d.with(withClause{
+		recursive: true,
+		ctes:      []commonTable{{name: name, queries: []*gorm.DB{anchor.underlyingDB(), recursive.underlyingDB()}}},
+	}, anchor)
+}
+
+func (d *DO) with(w withClause, q SubQuery) Dao {
+	if d.fromItems != nil { // Table of CTE references only, use connection of CTE query
+		if stmt := d.db.Statement; len(stmt.Clauses) > 0 || len(stmt.Selects) > 0 || len(stmt.Omits) > 0 {
+			return d.getInstance(detachedDB(d.fromItems, ErrWithAfterQuery)) // conditions added before With would be lost
+		}
+		d = d.getInstance(tableDB(q.underlyingDB(), d.fromItems))
+		d.fromItems = nil
+	}
+	return d.getInstance(d.db.Clauses(w))
+}
+
+// DeleteQuery DELETE statement of current conditions as sub query, used as data-modifying CTE:
+//
+//	o.With("moved", o.Where(o.CreatedAt.Lt(before)).DeleteQuery())
+//
+// deleted rows are returned by RETURNING *, or by columns specified by Returning
+func (d *DO) DeleteQuery() SubQuery {
+	if d.modelType == nil {
+		return d.withError(errors.New("DeleteQuery requires model"))
+	}
+	tx := d.modifyingTx().Delete(reflect.New(reflect.SliceOf(reflect.PointerTo(d.modelType))).Interface())
+	return d.getInstance(tx)
+}
+
+// UpdateQuery UPDATE statement of current conditions as sub query, used as data-modifying CTE:
+//
+//	o.With("paid", o.Where(o.Status.Eq("pending")).UpdateQuery(o.Status.Value("paid")))
+//
+// updated rows are returned by RETURNING *, or by columns specified by Returning
+func (d *DO) UpdateQuery(columns ...field.AssignExpr) SubQuery {
+	if len(columns) == 0 {
+		return d.withError(errors.New("UpdateQuery requires columns"))
+	}
+	tx := d.modifyingTx().Clauses(d.assignSet(columns)).Omit("*").Updates(map[string]interface{}{})
+	return d.getInstance(tx)
+}
+
+// modifyingTx dry run session building data-modifying statement with RETURNING clause
+func (d *DO) modifyingTx() *gorm.DB {
+	tx := d.prepareTx().Session(&gorm.Session{DryRun: true, SkipHooks: true})
+	if _, ok := tx.Statement.Clauses[clause.Returning{}.Name()]; !ok {
+		tx = tx.Clauses(clause.Returning{})
+	}
+	return tx
+}
+
+// commonTable common table expression, queries are joined by UNION ALL
+type commonTable struct {
+	name    string
+	queries []*gorm.DB
+}
+
+// withClause WITH clause of statement
+type withClause struct {
+	recursive bool
+	ctes      []commonTable
+}
+
+// Build implements clause.Expression
+func (w withClause) Build(builder clause.Builder) {
+	builder.WriteString("WITH ")
+	if w.recursive {
+		builder.WriteString("RECURSIVE ")
+	}
+	for i, cte := range w.ctes {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteQuoted(cte.name)
+		builder.WriteString(" AS (")
+		for j, q := range cte.queries {
+			if j > 0 {
+				builder.WriteString(" UNION ALL ")
+			}
+			builder.AddVar(builder, q)
+		}
+		builder.WriteByte(')')
+	}
+}
+
+// ModifyStatement implements gorm.StatementModifier,
+// merge CTEs into WITH clause and place it before statement
+func (w withClause) ModifyStatement(stmt *gorm.Statement) {
+	if c, ok := stmt.Clauses[withClauseName]; ok {
+		if prev, ok := c.Expression.(withClause); ok {
+			w = withClause{
+				recursive: prev.recursive || w.recursive,
+				ctes:      append(append([]commonTable{}, prev.ctes...), w.ctes...),
+			}
+		}
+	}
+	stmt.Clauses[withClauseName] = clause.Clause{Name: withClauseName, Expression: w}
+
+	for _, name := range []string{"SELECT", "INSERT", "UPDATE", "DELETE"} {
+		c := stmt.Clauses[name]
+		c.BeforeExpression = w
+		stmt.Clauses[name] = c
+	}
+}
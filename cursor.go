@@ -0,0 +1,322 @@
+package gen
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"go.ipao.vip/gen/field"
+)
+
+// ErrInvalidCursor cursor is malformed, tampered or does not match order columns
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorPage cursors of rows around the page returned by FindByCursor, empty when there are no more rows
+type CursorPage struct {
+	Next string
+	Prev string
+}
+
+// cursorSecret key signing cursors, random per process unless set by SetCursorSecret
+var cursorSecret atomic.Value
+
+func init() {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Errorf("generate cursor secret fail: %w", err))
+	}
+	cursorSecret.Store(secret)
+}
+
+// SetCursorSecret set key signing cursors of FindByCursor, secret must not be empty.
+// Cursors are only valid in the current process by default, set the same secret on every instance sharing cursors,
+// cursors signed by the previous secret become invalid.
+func SetCursorSecret(secret []byte) error {
+	if len(secret) == 0 {
+		return errors.New("cursor secret must not be empty")
+	}
+	cursorSecret.Store(append([]byte(nil), secret...))
+	return nil
+}
+
+// cursorColumn order column of keyset pagination
+type cursorColumn struct {
+	column clause.Column
+	field  *schema.Field
+	desc   bool
+}
+
+// key column with direction, cursor is only valid for the same order
+func (c cursorColumn) key() string {
+	if c.desc {
+		return c.field.DBName + " desc"
+	}
+	return c.field.DBName
+}
+
+// cursorPayload position of cursor
+type cursorPayload struct {
+	Columns []string          `json:"c"`
+	Values  []json.RawMessage `json:"v"`
+	Before  bool              `json:"b,omitempty"` // rows before position, for previous page
+}
+
+// FindByCursor find rows after cursor with keyset pagination, empty cursor for the first page.
+// Rows are ordered by order columns, primary key is appended as tiebreaker.
+func (d *DO) FindByCursor(cursor string, limit int, orders ...field.Expr) (results interface{}, page CursorPage, err error) {
+	tx, columns, before, err := d.cursorQuery(cursor, limit, orders)
+	if err != nil {
+		return nil, page, err
+	}
+	if results, err = tx.Find(); err != nil {
+		return results, page, err
+	}
+
+	rows := reflect.ValueOf(results)
+	hasMore := rows.Len() > limit
+	if hasMore {
+		rows = rows.Slice(0, limit)
+	}
+	if before { // rows before cursor are queried in reverse order
+		swap := reflect.Swapper(rows.Interface())
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	if rows.Len() == 0 {
+		return rows.Interface(), page, nil
+	}
+
+	if hasMore || before {
+		if page.Next, err = d.encodeCursor(columns, rows.Index(rows.Len()-1), false); err != nil {
+			return nil, page, err
+		}
+	}
+	if (before && hasMore) || (!before && cursor != "") {
+		if page.Prev, err = d.encodeCursor(columns, rows.Index(0), true); err != nil {
+			return nil, page, err
+		}
+	}
+	return rows.Interface(), page, nil
+}
+
+// cursorQuery query of rows after or before cursor, one more row is queried to know whether there are more rows
+func (d *DO) cursorQuery(cursor string, limit int, orders []field.Expr) (tx *DO, columns []cursorColumn, before bool, err error) {
+	if limit <= 0 {
+		return nil, nil, false, errors.New("limit of cursor must be positive")
+	}
+	if columns, err = d.cursorColumns(orders); err != nil {
+		return nil, nil, false, err
+	}
+
+	tx = d
+	if cursor != "" {
+		payload, err := decodeCursor(cursor, columns)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		values := make([]interface{}, len(columns))
+		for i, c := range columns {
+			value := reflect.New(c.field.FieldType)
+			if err := json.Unmarshal(payload.Values[i], value.Interface()); err != nil {
+				return nil, nil, false, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+			}
+			values[i] = value.Elem().Interface()
+		}
+		before = payload.Before
+		tx = tx.getInstance(tx.db.Where(cursorCondition(columns, values, before)))
+	}
+
+	for _, c := range columns {
+		tx = tx.getInstance(tx.db.Order(clause.OrderByColumn{Column: c.column, Desc: c.desc != before}))
+	}
+	return tx.getInstance(tx.db.Limit(limit + 1)), columns, before, nil
+}
+
+// cursorColumns columns of order expressions with primary key as tiebreaker
+func (d *DO) cursorColumns(orders []field.Expr) (columns []cursorColumn, err error) {
+	if d.modelType == nil || d.db.Statement.Schema == nil {
+		return nil, errors.New("FindByCursor requires model")
+	}
+	sch := d.db.Statement.Schema
+
+	used := make(map[string]bool)
+	for _, order := range orders {
+		c, ok := orderColumn(order)
+		if !ok {
+			return nil, fmt.Errorf("unsupported cursor order expression: %s", order.ColumnName())
+		}
+		if !d.ownsColumn(c.column) {
+			return nil, fmt.Errorf("cursor order column %s.%s is not a column of %s", c.column.Table, c.column.Name, d.TableName())
+		}
+		if c.field = sch.LookUpField(c.column.Name); c.field == nil {
+			return nil, fmt.Errorf("cursor order column %s is not a field of %s", c.column.Name, sch.Name)
+		}
+		if nullable(c.field.FieldType) {
+			// (a, b) > (NULL, ?) is NULL, rows after NULL values would never be returned
+			return nil, fmt.Errorf("cursor order column %s is nullable, keyset pagination requires NOT NULL columns", c.column.Name)
+		}
+		used[c.field.DBName] = true
+		columns = append(columns, c)
+	}
+
+	if len(sch.PrimaryFields) == 0 && len(columns) == 0 {
+		return nil, fmt.Errorf("cursor of %s requires order columns or primary key", sch.Name)
+	}
+	desc := len(columns) > 0 && columns[len(columns)-1].desc
+	for _, pk := range sch.PrimaryFields {
+		if !used[pk.DBName] {
+			columns = append(columns, cursorColumn{
+				column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName},
+				field:  pk,
+				desc:   desc,
+			})
+		}
+	}
+	return columns, nil
+}
+
+// ownsColumn column belongs to table of d, by its name or alias, or is not qualified
+func (d *DO) ownsColumn(column clause.Column) bool {
+	_, table := splitTableName(d.TableName())
+	switch column.Table {
+	case "", clause.CurrentTable, d.TableName(), table, d.db.Statement.Schema.Table:
+		return true
+	}
+	return d.alias != "" && column.Table == d.alias
+}
+
+// nullable field type holding NULL, e.g. *int64, sql.NullString, types.Null[T]
+func nullable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return true
+	case reflect.Struct:
+		valid, ok := typ.FieldByName("Valid")
+		return ok && valid.Type.Kind() == reflect.Bool
+	}
+	return false
+}
+
+// orderColumn column and direction of order expression, e.g. u.CreatedAt.Desc()
+func orderColumn(order field.Expr) (c cursorColumn, ok bool) {
+	switch raw := order.RawExpr().(type) {
+	case clause.Column:
+		return cursorColumn{column: raw}, true
+	case clause.Expr:
+		if len(raw.Vars) != 1 {
+			return c, false
+		}
+		column, ok := raw.Vars[0].(clause.Column)
+		if !ok {
+			return c, false
+		}
+		switch strings.ToUpper(raw.SQL) {
+		case "? DESC":
+			return cursorColumn{column: column, desc: true}, true
+		case "? ASC":
+			return cursorColumn{column: column}, true
+		}
+	}
+	return c, false
+}
+
+// cursorCondition rows after values in order of columns, e.g. (a, b) > (?, ?),
+// expanded as (a > ?) OR (a = ? AND b < ?) when directions are mixed
+func cursorCondition(columns []cursorColumn, values []interface{}, before bool) clause.Expression {
+	op := func(c cursorColumn) string {
+		if c.desc != before {
+			return "<"
+		}
+		return ">"
+	}
+
+	sameDirection := true
+	for _, c := range columns[1:] {
+		sameDirection = sameDirection && c.desc == columns[0].desc
+	}
+	if sameDirection {
+		vars := make([]interface{}, 0, len(columns)*2)
+		for _, c := range columns {
+			vars = append(vars, c.column)
+		}
+		vars = append(vars, values...)
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		return clause.Expr{SQL: "(" + placeholders + ") " + op(columns[0]) + " (" + placeholders + ")", Vars: vars}
+	}
+
+	var ors []clause.Expression
+	for i, c := range columns {
+		ands := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: columns[j].column, Value: values[j]})
+		}
+		ands = append(ands, clause.Expr{SQL: "? " + op(c) + " ?", Vars: []interface{}{c.column, values[i]}})
+		ors = append(ors, clause.And(ands...))
+	}
+	return clause.Or(ors...)
+}
+
+// encodeCursor signed cursor of row position
+func (d *DO) encodeCursor(columns []cursorColumn, row reflect.Value, before bool) (string, error) {
+	payload := cursorPayload{Columns: make([]string, len(columns)), Values: make([]json.RawMessage, len(columns)), Before: before}
+	for i, c := range columns {
+		value, _ := c.field.ValueOf(d.db.Statement.Context, reflect.Indirect(row))
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("encode cursor value of %s fail: %w", c.field.DBName, err)
+		}
+		payload.Columns[i], payload.Values[i] = c.key(), data
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(signCursor(data)), nil
+}
+
+// decodeCursor verify signature and order columns of cursor
+func decodeCursor(cursor string, columns []cursorColumn) (payload cursorPayload, err error) {
+	encoded, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return payload, ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return payload, ErrInvalidCursor
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, signCursor(data)) {
+		return payload, ErrInvalidCursor
+	}
+	if err = json.Unmarshal(data, &payload); err != nil {
+		return payload, ErrInvalidCursor
+	}
+
+	if len(payload.Columns) != len(columns) || len(payload.Values) != len(columns) {
+		return payload, fmt.Errorf("%w: order columns changed", ErrInvalidCursor)
+	}
+	for i, c := range columns {
+		if payload.Columns[i] != c.key() {
+			return payload, fmt.Errorf("%w: order columns changed", ErrInvalidCursor)
+		}
+	}
+	return payload, nil
+}
+
+func signCursor(data []byte) []byte {
+	mac := hmac.New(sha256.New, cursorSecret.Load().([]byte))
+	mac.Write(data)
+	return mac.Sum(nil)
+}
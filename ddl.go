@@ -0,0 +1,82 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"go.ipao.vip/gen/internal/ddl"
+)
+
+// OpenDDL open SQL DDL files as db, so code can be generated without database connection
+//
+// paths are schema dump files, e.g. output of pg_dump --schema-only, or migration directories.
+// *.up.sql files of directory are applied in version order, or all *.sql files if there are no *.up.sql files.
+//
+//	db, err := gen.OpenDDL("migrations")
+//	g.UseDB(db)
+func OpenDDL(paths ...string) (*gorm.DB, error) {
+	schema := ddl.NewSchema()
+	for _, path := range paths {
+		files, err := ddlFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if err = schema.Parse(ddl.UpSection(string(content))); err != nil {
+				return nil, fmt.Errorf("parse DDL %s fail: %w", file, err)
+			}
+		}
+	}
+	return gorm.Open(ddl.Open(schema), &gorm.Config{})
+}
+
+// ddlFiles sql files of path, migration files in directory are sorted by version
+func ddlFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.up.sql"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		all, err := filepath.Glob(filepath.Join(path, "*.sql"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range all {
+			if !strings.HasSuffix(file, ".down.sql") {
+				files = append(files, file)
+			}
+		}
+	}
+
+	version := func(file string) uint64 {
+		name := filepath.Base(file)
+		end := strings.IndexFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+		v, _ := strconv.ParseUint(name[:end], 10, 64)
+		return v
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if vi, vj := version(files[i]), version(files[j]); vi != vj {
+			return vi < vj
+		}
+		return files[i] < files[j]
+	})
+	return files, nil
+}
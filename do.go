@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/callbacks"
@@ -14,6 +15,7 @@ import (
 
 	"go.ipao.vip/gen/field"
 	"go.ipao.vip/gen/helper"
+	"go.ipao.vip/gen/internal/ddl"
 )
 
 // ResultInfo query/execute info
@@ -32,6 +34,7 @@ type DO struct {
 	alias     string // for subquery
 	modelType reflect.Type
 	tableName string
+	fromItems []SubQuery // CTE references and set-returning functions of Table without sub query, connected by With
 
 	backfillData interface{}
 }
@@ -222,6 +225,9 @@ func (d *DO) Select(columns ...field.Expr) Dao {
 	if len(columns) == 0 {
 		return d.getInstance(d.db.Clauses(clause.Select{}))
 	}
+	if err := exprsError(columns); err != nil {
+		return d.withError(err)
+	}
 	query, args := buildExpr4Select(d.db.Statement, columns...)
 	return d.getInstance(d.db.Select(query, args...))
 }
@@ -253,7 +259,21 @@ func (d *DO) Order(columns ...field.Expr) Dao {
 	if len(columns) == 0 {
 		return d
 	}
-	return d.getInstance(d.db.Order(d.toOrderValue(columns...)))
+	if err := exprsError(columns); err != nil {
+		return d.withError(err)
+	}
+	tx := d.getInstance(d.db.Order(d.toOrderValue(columns...)))
+	return tx.withError(tx.checkDistinctOn())
+}
+
+// exprsError error of columns, e.g. cast to invalid type
+func exprsError(columns []field.Expr) error {
+	for _, column := range columns {
+		if err := column.CondError(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (d *DO) toOrderValue(columns ...field.Expr) string {
@@ -275,6 +295,118 @@ func (d *DO) Distinct(columns ...field.Expr) Dao {
 	return d.getInstance(d.db.Distinct(toInterfaceSlice(toColExprFullName(d.db.Statement, columns...))...))
 }
 
+// DistinctOn SELECT DISTINCT ON (columns), the first row of each group of columns is kept.
+// Leading Order columns must match columns, or an error is added.
+func (d *DO) DistinctOn(columns ...field.Expr) Dao {
+	if len(columns) == 0 {
+		return d
+	}
+	on := distinctOn{exprs: toExpression(columns...), keys: make([]string, len(columns))}
+	for i, column := range columns {
+		on.keys[i] = d.toOrderValue(column)
+	}
+	tx := d.getInstance(d.db.Clauses(on))
+	return tx.withError(tx.checkDistinctOn())
+}
+
+// checkDistinctOn check leading ORDER BY expressions are DISTINCT ON expressions
+func (d *DO) checkDistinctOn() error {
+	on, ok := d.db.Statement.Clauses["SELECT"].AfterNameExpression.(distinctOn)
+	if !ok {
+		return nil
+	}
+	orders := orderKeys(d.db.Statement)
+	if len(orders) > len(on.keys) {
+		orders = orders[:len(on.keys)]
+	}
+	for _, order := range orders {
+		matched := false
+		for _, key := range on.keys {
+			matched = matched || key == order
+		}
+		if !matched {
+			return fmt.Errorf("DISTINCT ON expressions must match leading ORDER BY expressions, got %s", order)
+		}
+	}
+	return nil
+}
+
+// orderKeys ORDER BY expressions without direction
+func orderKeys(stmt *gorm.Statement) (keys []string) {
+	orderBy, ok := stmt.Clauses["ORDER BY"].Expression.(clause.OrderBy)
+	if !ok {
+		return nil
+	}
+	for _, column := range orderBy.Columns {
+		if !column.Column.Raw {
+			keys = append(keys, stmt.Quote(column.Column))
+			continue
+		}
+		for _, item := range splitTopLevel(column.Column.Name) {
+			keys = append(keys, trimOrderDirection(item))
+		}
+	}
+	return keys
+}
+
+// splitTopLevel split comma separated items outside of parentheses
+func splitTopLevel(s string) (items []string) {
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(s[start:]))
+}
+
+func trimOrderDirection(order string) string {
+	for _, suffix := range []string{" NULLS FIRST", " NULLS LAST", " DESC", " ASC"} {
+		if strings.HasSuffix(strings.ToUpper(order), suffix) {
+			order = strings.TrimSpace(order[:len(order)-len(suffix)])
+		}
+	}
+	return order
+}
+
+// distinctOn DISTINCT ON (...) placed after SELECT
+type distinctOn struct {
+	exprs []clause.Expression
+	keys  []string // expressions as ORDER BY value
+}
+
+// Build implements clause.Expression
+func (on distinctOn) Build(builder clause.Builder) {
+	builder.WriteString("DISTINCT ON (")
+	for i, e := range on.exprs {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		e.Build(builder)
+	}
+	builder.WriteByte(')')
+}
+
+// ModifyStatement implements gorm.StatementModifier
+func (on distinctOn) ModifyStatement(stmt *gorm.Statement) {
+	stmt.Distinct = false
+	c := stmt.Clauses["SELECT"]
+	if sel, ok := c.Expression.(clause.Select); ok {
+		sel.Distinct = false
+		c.Expression = sel
+	}
+	c.AfterNameExpression = on
+	stmt.Clauses["SELECT"] = c
+}
+
 // Omit ...
 func (d *DO) Omit(columns ...field.Expr) Dao {
 	if len(columns) == 0 {
@@ -288,6 +420,9 @@ func (d *DO) Group(columns ...field.Expr) Dao {
 	if len(columns) == 0 {
 		return d
 	}
+	if err := exprsError(columns); err != nil {
+		return d.withError(err)
+	}
 
 	stmt := &gorm.Statement{DB: d.db.Statement.DB, Table: d.db.Statement.Table, Schema: d.db.Statement.Schema}
 
@@ -357,6 +492,9 @@ func (d *DO) join(table schema.Tabler, joinType clause.JoinType, conds []field.E
 	if len(conds) == 0 {
 		return d.withError(ErrEmptyCondition)
 	}
+	if src, ok := table.(funcSource); ok && src.funcTable().err != nil {
+		return d.withError(src.funcTable().err)
+	}
 	from := getFromClause(d.db)
 	from.Joins = append(
 		from.Joins,
@@ -572,6 +710,50 @@ func (d *DO) UpdateFrom(q SubQuery) Dao {
 	return d.getInstance(d.db.Clauses(clause.Update{Table: clause.Table{Name: tableName.String(), Raw: true}}))
 }
 
+// DeleteUsing specify tables of DELETE ... USING, join conditions are given by Where,
+// columns of the deleted table must be qualified by its name as generated fields are
+//
+//	o.DeleteUsing(u).Where(o.UserID.EqCol(u.ID), u.Deleted.Is(true)).Delete()
+func (d *DO) DeleteUsing(tables ...schema.Tabler) Dao {
+	if len(tables) == 0 {
+		return d
+	}
+	using := deleteUsing{tables: make([]clause.Table, len(tables))}
+	for i, table := range tables {
+		using.tables[i] = clause.Table{Name: table.TableName()}
+		if al, ok := table.(interface{ Alias() string }); ok {
+			using.tables[i].Alias = al.Alias()
+		}
+	}
+	return d.getInstance(d.db.Clauses(using))
+}
+
+// deleteUsing USING tables placed after FROM of DELETE
+type deleteUsing struct {
+	tables []clause.Table
+}
+
+// Build implements clause.Expression
+func (u deleteUsing) Build(builder clause.Builder) {
+	builder.WriteString("USING ")
+	for i, table := range u.tables {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(table)
+	}
+}
+
+// ModifyStatement implements gorm.StatementModifier
+func (u deleteUsing) ModifyStatement(stmt *gorm.Statement) {
+	c := stmt.Clauses["FROM"]
+	if c.Expression == nil {
+		c.Name, c.Expression = "FROM", clause.From{}
+	}
+	c.AfterExpression = u
+	stmt.Clauses["FROM"] = c
+}
+
 func getFromClause(db *gorm.DB) *clause.From {
 	if db == nil || db.Statement == nil {
 		return &clause.From{}
@@ -973,6 +1155,8 @@ func toClauseJoins(joins ...field.RelationJoin) []clause.Join {
 		}
 		if do, ok := j.Table.(Dao); ok {
 			join.Expression = helper.NewJoinTblExpr(join, Table(do).underlyingDB().Statement.TableExpr)
+		} else if item, ok := j.Table.(fromItem); ok {
+			join.Expression = helper.NewJoinTblExpr(join, item.fromExpr())
 		}
 		if al, ok := j.Table.(interface{ Alias() string }); ok {
 			join.Table.Alias = al.Alias()
@@ -992,14 +1176,65 @@ func toClauseJoins(joins ...field.RelationJoin) []clause.Join {
 // the above usage is equivalent to SQL statement:
 //
 //	SELECT * FROM (SELECT `id`, `name` FROM `users_info` WHERE `age` > ?)"
+//
+// CTE references are used as table directly, e.g. Table(CTE("tree")).WithRecursive("tree", anchor, recursive),
+// Table of CTE references or functions only takes connection from With, called before other methods,
+// queries fail with ErrDetachedTable without it
 func Table(subQueries ...SubQuery) Dao {
 	if len(subQueries) == 0 {
 		return &DO{}
 	}
 
+	db := firstDB(subQueries)
+	for _, query := range subQueries {
+		if src, ok := query.(funcSource); ok && src.funcTable().err != nil {
+			if db == nil {
+				return &DO{db: detachedDB(subQueries, src.funcTable().err)}
+			}
+			return (&DO{db: db}).withError(src.funcTable().err)
+		}
+	}
+	if db != nil {
+		return &DO{db: tableDB(db, subQueries)}
+	}
+	// CTE references or functions only, connection is taken from CTE query of With
+	return &DO{db: detachedDB(subQueries, ErrDetachedTable), fromItems: subQueries}
+}
+
+var (
+	detachedOnce sync.Once
+	detachedBase *gorm.DB
+)
+
+// detachedDB session of Table without connection, queries fail with err until With replaces it
+func detachedDB(subQueries []SubQuery, err error) *gorm.DB {
+	detachedOnce.Do(func() {
+		detachedBase, _ = gorm.Open(ddl.Open(ddl.NewSchema()), &gorm.Config{})
+	})
+	db := tableDB(detachedBase, subQueries)
+	_ = db.AddError(err)
+	return db
+}
+
+// firstDB connection of the first sub query, nil when there are CTE references or functions only
+func firstDB(subQueries []SubQuery) *gorm.DB {
+	for _, query := range subQueries {
+		if db := query.underlyingDB(); db != nil {
+			return db
+		}
+	}
+	return nil
+}
+
+func tableDB(db *gorm.DB, subQueries []SubQuery) *gorm.DB {
 	tablePlaceholder := make([]string, len(subQueries))
 	tableExprs := make([]interface{}, len(subQueries))
 	for i, query := range subQueries {
+		if item, ok := query.(fromItem); ok {
+			tablePlaceholder[i], tableExprs[i] = "?", item.fromExpr()
+			continue
+		}
+
 		tablePlaceholder[i] = "(?)"
 
 		do := query.underlyingDO()
@@ -1010,10 +1245,7 @@ func Table(subQueries ...SubQuery) Dao {
 		}
 	}
 
-	return &DO{
-		db: subQueries[0].underlyingDO().db.Session(&gorm.Session{NewDB: true}).
-			Table(strings.Join(tablePlaceholder, ", "), tableExprs...),
-	}
+	return db.Session(&gorm.Session{NewDB: true}).Table(strings.Join(tablePlaceholder, ", "), tableExprs...)
 }
 
 // Exists EXISTS expression
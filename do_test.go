@@ -1,11 +1,16 @@
 package gen
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
 	"gorm.io/gorm/clause"
 	"gorm.io/hints"
 
@@ -54,7 +59,7 @@ func build(stmt *gorm.Statement, opts ...stmtOpt) *gorm.Statement {
 		stmt = opt(stmt)
 	}
 
-	if _, ok := stmt.Clauses["SELECT"]; !ok && len(stmt.Selects) > 0 {
+	if c, ok := stmt.Clauses["SELECT"]; (!ok || c.Expression == nil) && len(stmt.Selects) > 0 {
 		stmt.AddClause(
 			clause.Select{Distinct: stmt.Distinct, Expression: clause.Expr{SQL: strings.Join(stmt.Selects, ",")}},
 		)
@@ -108,6 +113,14 @@ func TestDO_methods(t *testing.T) {
 			Expr:   teacher.Select(teacher.Name.As("n")).Distinct(),
 			Result: "SELECT DISTINCT `teacher`.`name` AS `n`",
 		},
+		{
+			Expr:   u.DistinctOn(u.Age).Select(u.ID, u.Age).Order(u.Age, u.ID.Desc()),
+			Result: "SELECT DISTINCT ON (`age`) `id`,`age` ORDER BY `age`,`id` DESC",
+		},
+		{
+			Expr:   teacher.Select(teacher.ID).Order(teacher.Name.Desc(), teacher.ID).DistinctOn(teacher.ID, teacher.Name),
+			Result: "SELECT DISTINCT ON (`teacher`.`id`,`teacher`.`name`) `teacher`.`id` ORDER BY `teacher`.`name` DESC,`teacher`.`id`",
+		},
 		{
 			Expr:   teacher.Select(field.ALL),
 			Result: "SELECT *",
@@ -368,6 +381,18 @@ func TestDO_methods(t *testing.T) {
 			Expr:   student.Select().LeftJoin(teacher, teacher.ID.EqCol(student.Instructor)).Group(student.ID),
 			Result: "SELECT * FROM `student` LEFT JOIN `teacher` ON `teacher`.`id` = `student`.`instructor` GROUP BY `student`.`id`",
 		},
+		{
+			Expr:   u.Select(u.Name, u.Age, field.Grouping(u.Name, u.Age).As("g"), u.Score.Sum()).Group(Rollup(u.Name, u.Age)),
+			Result: "SELECT `name`,`age`,GROUPING(`name`,`age`) AS `g`,SUM(`score`) GROUP BY ROLLUP (`name`,`age`)",
+		},
+		{
+			Expr:   u.Select(u.Name, u.Age).DO.Group(u.ID, Cube(u.Name, u.Age)),
+			Result: "SELECT `name`,`age` GROUP BY `id`,CUBE (`name`,`age`)",
+		},
+		{
+			Expr:   u.Select(u.Name, u.Age).Group(GroupingSets([]field.Expr{u.Name}, []field.Expr{u.Name, u.Age}, nil)),
+			Result: "SELECT `name`,`age` GROUP BY GROUPING SETS ((`name`),(`name`,`age`),())",
+		},
 		// ======================== from subquery ========================
 		{
 			Expr:         Table(u.Select(u.ID, u.Name).Where(u.Age.Gt(18))).Select(),
@@ -393,8 +418,56 @@ func TestDO_methods(t *testing.T) {
 			ExpectedVars: []interface{}{18, 100.0},
 			Result:       "SELECT * FROM (SELECT * FROM `users_info` WHERE `age` > ?) AS `a`, (SELECT * FROM `users_info` WHERE `score` >= ?) AS `b`",
 		},
+		{
+			Expr: Table(u.Select(u.ID, field.RowNumber().Over(field.PartitionBy(u.Age).OrderBy(u.Score.Desc())).As("rn")).As("t")).
+				Select().Where(field.NewInt("t", "rn").Lte(3)),
+			Opts:         []stmtOpt{withFROM},
+			ExpectedVars: []interface{}{3},
+			Result:       "SELECT * FROM (SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `age` ORDER BY `score` DESC) AS `rn` FROM `users_info`) AS `t` WHERE `t`.`rn` <= ?",
+		},
+		{
+			Expr:         Table(Union(u.Select(u.ID).Where(u.Age.Gt(18)), u.Select(u.ID).Where(u.Score.Gte(100)))).Select(),
+			Opts:         []stmtOpt{withFROM},
+			ExpectedVars: []interface{}{18, 100.0},
+			Result:       "SELECT * FROM ((SELECT `id` FROM `users_info` WHERE `age` > ?) UNION (SELECT `id` FROM `users_info` WHERE `score` >= ?))",
+		},
+		{
+			Expr: Table(UnionAll(u.Select(u.ID, u.Name), u.Select(u.ID, u.Name), u.Select(u.ID, u.Name)).
+				Order(field.NewUint("users_info", "id").Desc()).Limit(10).Offset(20).As("t")).Select(),
+			Opts:         []stmtOpt{withFROM},
+			ExpectedVars: []interface{}{10, 20},
+			Result:       "SELECT * FROM ((SELECT `id`,`name` FROM `users_info`) UNION ALL (SELECT `id`,`name` FROM `users_info`) UNION ALL (SELECT `id`,`name` FROM `users_info`) ORDER BY `id` DESC LIMIT ? OFFSET ?) AS `t`",
+		},
+		{
+			Expr:         Table(Except(u.Select(u.ID), Intersect(u.Select(u.ID), u.Select(u.Age.Max())))).Select(),
+			Opts:         []stmtOpt{withFROM},
+			ExpectedVars: []interface{}{},
+			Result:       "SELECT * FROM ((SELECT `id` FROM `users_info`) EXCEPT ((SELECT `id` FROM `users_info`) INTERSECT (SELECT MAX(`age`) FROM `users_info`)))",
+		},
 
 		// ======================== join subquery ========================
+		{
+			Expr: teacher.LeftJoinLateral(student.Select(student.Name).Where(student.Instructor.EqCol(teacher.ID)).
+				Order(student.Age.Desc()).Limit(3), "top").Select(teacher.Name, field.NewString("top", "name")),
+			Result:       "SELECT `teacher`.`name`,`top`.`name` FROM `teacher` LEFT JOIN LATERAL (SELECT `student`.`name` FROM `student` WHERE `student`.`instructor` = `teacher`.`id` ORDER BY `student`.`age` DESC LIMIT ?) AS `top` ON true",
+			ExpectedVars: []interface{}{3},
+		},
+		{
+			Expr:   teacher.JoinLateral(JSONBArrayElements("tag", field.NewJSONB("teacher", "tags")), "").Select(teacher.Name),
+			Result: "SELECT `teacher`.`name` FROM `teacher` INNER JOIN LATERAL jsonb_array_elements(`teacher`.`tags`) AS `tag`(`value`) ON true",
+		},
+		{
+			Expr:         teacher.Join(Unnest("n", field.NewArray("teacher", "nicknames")), field.NewString("n", "value").Like("a%")).Select(teacher.Name),
+			Result:       "SELECT `teacher`.`name` FROM `teacher` INNER JOIN unnest(`teacher`.`nicknames`) AS `n`(`value`) ON `n`.`value` LIKE ?",
+			ExpectedVars: []interface{}{"a%"},
+		},
+		{
+			Expr: Table(GenerateSeries("s", 1, 10, 2), JSONBToRecordset("r", field.NewJSONB("", "items"),
+				RecordColumn{Name: "sku", Type: "text"}, RecordColumn{Name: "qty", Type: "int"}), teacher.Select(teacher.ID)).Select(),
+			Opts:         []stmtOpt{withFROM},
+			ExpectedVars: []interface{}{int64(1), int64(10), int64(2)},
+			Result:       "SELECT * FROM generate_series(?::bigint, ?::bigint, ?::bigint) AS `s`(`value`), jsonb_to_recordset(`items`) AS `r`(`sku` text, `qty` int), (SELECT `teacher`.`id` FROM `teacher`)",
+		},
 		{
 			Expr:   student.Join(teacher, student.Instructor.EqCol(teacher.ID)).Select(),
 			Result: "SELECT * FROM `student` INNER JOIN `teacher` ON `student`.`instructor` = `teacher`.`id`",
@@ -430,3 +503,415 @@ func TestDO_methods(t *testing.T) {
 		checkBuildExpr(t, testcase.Expr, testcase.Opts, testcase.Result, testcase.ExpectedVars)
 	}
 }
+
+func TestDO_FindByCursor(t *testing.T) {
+	order := []field.Expr{u.Age.Desc()}
+	tx, columns, _, err := u.cursorQuery("", 10, order)
+	if err != nil {
+		t.Fatalf("build cursor query fail: %s", err)
+	}
+	checkBuildExpr(t, tx, []stmtOpt{withFROM}, "FROM `users_info` ORDER BY `age` DESC,`users_info`.`id` DESC LIMIT ?", []interface{}{11})
+
+	next, err := u.encodeCursor(columns, reflect.ValueOf(&User{ID: 5, Age: 20}), false)
+	if err != nil {
+		t.Fatalf("encode cursor fail: %s", err)
+	}
+	tx, _, before, err := u.cursorQuery(next, 10, order)
+	if err != nil || before {
+		t.Fatalf("expect cursor of next page, got %v %s", before, err)
+	}
+	checkBuildExpr(t, tx, []stmtOpt{withFROM},
+		"FROM `users_info` WHERE (`age`, `users_info`.`id`) < (?, ?) ORDER BY `age` DESC,`users_info`.`id` DESC LIMIT ?",
+		[]interface{}{20, uint(5), 11})
+
+	prev, _ := u.encodeCursor(columns, reflect.ValueOf(&User{ID: 5, Age: 20}), true)
+	tx, _, before, err = u.cursorQuery(prev, 10, order)
+	if err != nil || !before {
+		t.Fatalf("expect cursor of previous page, got %v %s", before, err)
+	}
+	checkBuildExpr(t, tx, []stmtOpt{withFROM},
+		"FROM `users_info` WHERE (`age`, `users_info`.`id`) > (?, ?) ORDER BY `age`,`users_info`.`id` LIMIT ?",
+		[]interface{}{20, uint(5), 11})
+
+	mixed := []field.Expr{u.Age.Desc(), u.Name}
+	columns, _ = u.cursorColumns(mixed)
+	cursor, _ := u.encodeCursor(columns, reflect.ValueOf(&User{ID: 5, Age: 20, Name: "a"}), false)
+	tx, _, _, err = u.cursorQuery(cursor, 2, mixed)
+	if err != nil {
+		t.Fatalf("build cursor query fail: %s", err)
+	}
+	checkBuildExpr(t, tx, []stmtOpt{withFROM},
+		"FROM `users_info` WHERE (`age` < ? OR (`age` = ? AND `name` > ?) OR (`age` = ? AND `name` = ? AND `users_info`.`id` > ?)) ORDER BY `age` DESC,`name`,`users_info`.`id` LIMIT ?",
+		[]interface{}{20, 20, "a", 20, "a", uint(5), 3})
+
+	for name, c := range map[string]string{
+		"tampered":      prev[:strings.Index(prev, ".")] + next[strings.Index(next, "."):],
+		"malformed":     "abc",
+		"order changed": cursor,
+	} {
+		if _, _, _, err = u.cursorQuery(c, 10, order); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s cursor: expect ErrInvalidCursor, got %v", name, err)
+		}
+	}
+
+	if _, page, err := u.FindByCursor("", 10, order...); err != nil || page.Next != "" || page.Prev != "" {
+		t.Errorf("expect empty page, got %+v %v", page, err)
+	}
+
+	// order columns must be NOT NULL columns of the paginated table
+	var notes DO
+	notes.UseDB(db.Session(&gorm.Session{Context: context.Background(), DryRun: true}))
+	notes.UseModel(noteRaw{})
+	for name, orders := range map[string][]field.Expr{
+		"pointer":         {field.NewInt64("notes", "reviewer_id")},
+		"sql.NullString":  {field.NewString("notes", "title").Desc()},
+		"types.Null":      {field.NewTime("notes", "archived_at")},
+		"joined table":    {field.NewInt64("teacher", "id")},
+		"same name other": {teacher.Name},
+	} {
+		if _, err := notes.cursorColumns(orders); err == nil {
+			t.Errorf("%s: expect error of cursor order column", name)
+		}
+	}
+	if _, err := notes.cursorColumns([]field.Expr{field.NewString("notes", "name"), field.NewInt64("", "id")}); err != nil {
+		t.Errorf("unexpected error of cursor order columns: %s", err)
+	}
+	if _, err := student.cursorColumns([]field.Expr{student.Name}); err != nil {
+		t.Errorf("unexpected error of cursor order column of generated field: %s", err)
+	}
+	if err := SetCursorSecret(nil); err == nil {
+		t.Errorf("expect error of empty cursor secret")
+	}
+}
+
+// noteRaw model with nullable columns
+type noteRaw struct {
+	ID         int64 `gorm:"primary_key"`
+	Name       string
+	ReviewerID *int64
+	Title      sql.NullString
+	ArchivedAt types.Null[time.Time]
+}
+
+func (noteRaw) TableName() string { return "notes" }
+
+func TestDO_Upsert(t *testing.T) {
+	values := []*User{{Name: "a"}}
+	testcases := []struct {
+		Upsert       *Upsert
+		Result       string
+		ExpectedVars []interface{}
+	}{
+		{
+			Upsert: u.Upsert(values).OnConflict(u.Name),
+			Result: "ON CONFLICT (`name`) DO NOTHING",
+		},
+		{
+			Upsert: u.Upsert(values).OnConstraint("users_name_key").DoNothing(),
+			Result: "ON CONFLICT ON CONSTRAINT `users_name_key` DO NOTHING",
+		},
+		{
+			Upsert:       u.Upsert(values).OnConflict(u.Name).DoUpdate(u.Age, u.Famous.Value(true)).Where(u.Famous.Is(false)),
+			Result:       "ON CONFLICT (`name`) DO UPDATE SET `age`=`excluded`.`age`,`famous`=? WHERE `famous` = ?",
+			ExpectedVars: []interface{}{true, false},
+		},
+		{
+			Upsert:       u.Upsert(values).OnConflict(u.Name).TargetWhere(u.Age.Gt(0)).DoUpdate(u.Age.SetCol(u.Age.AddCol(field.Excluded(u.Age)))),
+			Result:       "ON CONFLICT (`name`)  WHERE `age` > ? DO UPDATE SET `age`=`age` + `excluded`.`age`",
+			ExpectedVars: []interface{}{0},
+		},
+	}
+
+	for i, tt := range testcases {
+		stmt := u.db.Session(&gorm.Session{DryRun: true}).Clauses(tt.Upsert.clauses()...).Create(values).Statement
+		sql := stmt.SQL.String()
+		if idx := strings.Index(sql, "ON CONFLICT"); idx < 0 || strings.TrimSpace(sql[idx:]) != tt.Result {
+			t.Errorf("case %d: SQL expects ...%s got %s", i, tt.Result, sql)
+		}
+		if vars := stmt.Vars[len(stmt.Vars)-len(tt.ExpectedVars):]; len(tt.ExpectedVars) > 0 && !reflect.DeepEqual(vars, tt.ExpectedVars) {
+			t.Errorf("case %d: Vars expects %v got %v", i, tt.ExpectedVars, vars)
+		}
+	}
+
+	if _, ok := u.Upsert(values).Returning(u.ID).clauses()[1].(clause.Returning); !ok {
+		t.Errorf("expect RETURNING clause")
+	}
+	if _, err := u.Upsert([]*User{}).Exec(); err != nil {
+		t.Errorf("expect upsert of empty values to be skipped, got %s", err)
+	}
+}
+
+func TestDO_With(t *testing.T) {
+	recent := CTE("recent")
+	pg := *u // DELETE and UPDATE build RETURNING clause as postgres dialector does
+	returningDB, _ := gorm.Open(mysqlDialectors{}, nil)
+	callbacks.RegisterDefaultCallbacks(returningDB, &callbacks.Config{
+		UpdateClauses: []string{"UPDATE", "SET", "WHERE", "RETURNING"},
+		DeleteClauses: []string{"DELETE", "FROM", "WHERE", "RETURNING"},
+	})
+	pg.UseDB(returningDB.Session(&gorm.Session{Context: context.Background(), DryRun: true}))
+	pg.UseModel(User{})
+	testcases := []struct {
+		Query        func(tx *gorm.DB) *gorm.DB
+		Result       string
+		ExpectedVars []interface{}
+	}{
+		{
+			Query: func(tx *gorm.DB) *gorm.DB {
+				return u.With(recent.TableName(), u.Select(u.ID).Where(u.Age.Gt(18))).
+					Select(u.Name).Join(recent, u.ID.EqCol(field.NewUint(recent.TableName(), "id"))).(*DO).db.Find(&[]User{})
+			},
+			Result:       "WITH `recent` AS (SELECT `id` FROM `users_info` WHERE `age` > ?) SELECT `name` FROM `users_info` INNER JOIN `recent` ON `id` = `recent`.`id`",
+			ExpectedVars: []interface{}{18},
+		},
+		{
+			Query: func(tx *gorm.DB) *gorm.DB {
+				return Table(CTE("tree")).WithRecursive("tree",
+					u.Select(u.ID).Where(u.ID.Eq(1)),
+					u.Select(u.ID).Join(CTE("tree"), u.Age.EqCol(field.NewUint("tree", "id"))),
+				).(*DO).db.Find(&[]map[string]interface{}{})
+			},
+			Result:       "WITH RECURSIVE `tree` AS (SELECT `id` FROM `users_info` WHERE `id` = ? UNION ALL SELECT `id` FROM `users_info` INNER JOIN `tree` ON `age` = `tree`.`id`) SELECT * FROM `tree`",
+			ExpectedVars: []interface{}{uint(1)},
+		},
+		{
+			Query: func(tx *gorm.DB) *gorm.DB {
+				moved := pg.Unscoped().Where(pg.Age.Lt(10)).DeleteQuery()
+				return pg.With("moved", moved).(*DO).db.Model(&User{}).Where("`id` IN (SELECT `id` FROM `moved`)").Update("famous", true)
+			},
+			Result:       "WITH `moved` AS (DELETE FROM `users_info` WHERE `age` < ? RETURNING *) UPDATE `users_info` SET `famous`=? WHERE `id` IN (SELECT `id` FROM `moved`)",
+			ExpectedVars: []interface{}{10, true},
+		},
+		{
+			Query: func(tx *gorm.DB) *gorm.DB {
+				paid := pg.Where(pg.Famous.Is(false)).Returning(nil, "id").(*DO).UpdateQuery(pg.Famous.Value(true))
+				return Table(CTE("paid")).With("paid", paid).(*DO).db.Find(&[]map[string]interface{}{})
+			},
+			Result:       "WITH `paid` AS (UPDATE `users_info` SET `famous`=? WHERE `famous` = ? RETURNING `id`) SELECT * FROM `paid`",
+			ExpectedVars: []interface{}{true, false},
+		},
+	}
+
+	for i, tt := range testcases {
+		stmt := tt.Query(nil).Statement
+		if sql := stmt.SQL.String(); !strings.HasPrefix(sql, tt.Result) {
+			t.Errorf("case %d: SQL expects %s got %s", i, tt.Result, sql)
+		}
+		if tt.ExpectedVars != nil && !reflect.DeepEqual(stmt.Vars, tt.ExpectedVars) {
+			t.Errorf("case %d: Vars expects %v got %v", i, tt.ExpectedVars, stmt.Vars)
+		}
+	}
+
+	if err := u.Where(CTE("tree")).db.Error; err == nil {
+		t.Errorf("expect CTE used as condition to fail")
+	}
+
+	// Table of CTE references only has no connection until With
+	if err := Table(CTE("t")).Where(u.ID.Gt(1)).(*DO).db.Error; !errors.Is(err, ErrDetachedTable) {
+		t.Errorf("expect ErrDetachedTable, got %v", err)
+	}
+	if err := Table(CTE("t")).Where(u.ID.Gt(1)).With("t", u.Select(u.ID)).(*DO).db.Error; !errors.Is(err, ErrWithAfterQuery) || errors.Is(err, ErrDetachedTable) {
+		t.Errorf("expect ErrWithAfterQuery of With after Where, got %v", err)
+	}
+}
+
+func TestDO_DistinctOn(t *testing.T) {
+	if err := u.DistinctOn(u.Age).Order(u.ID, u.Age).(*DO).db.Error; err == nil {
+		t.Errorf("expect error of ORDER BY not matching DISTINCT ON")
+	}
+	if err := u.Order(u.ID.Desc()).DistinctOn(u.Age).(*DO).db.Error; err == nil {
+		t.Errorf("expect error of ORDER BY before DISTINCT ON not matching")
+	}
+	if err := u.DistinctOn(u.Age, u.ID).Order(u.ID, u.Age.Desc(), u.Name).(*DO).db.Error; err != nil {
+		t.Errorf("expect ORDER BY starting with DISTINCT ON expressions, got %s", err)
+	}
+}
+
+// txConnPool connection pool of transaction used to test row locking
+type txConnPool struct{ gorm.ConnPool }
+
+func (txConnPool) Commit() error   { return nil }
+func (txConnPool) Rollback() error { return nil }
+
+// invoiceRaw model of table in other schema
+type invoiceRaw struct {
+	ID int64 `gorm:"primary_key"`
+}
+
+func (invoiceRaw) TableName() string { return "billing.invoices" }
+
+func TestDO_lock(t *testing.T) {
+	inTx := func(do DO) *DO {
+		do.db = do.db.WithContext(context.Background()) // clone statement
+		do.db.Statement.ConnPool = txConnPool{}
+		return &do
+	}
+	tx := inTx(u.DO)
+
+	testcases := []struct {
+		Expr   Dao
+		Result string
+	}{
+		{
+			Expr:   tx.Select(u.ID).ForUpdate(),
+			Result: "SELECT `id` FOR UPDATE",
+		},
+		{
+			Expr:   tx.Select(u.ID).ForNoKeyUpdate().NoWait(),
+			Result: "SELECT `id` FOR NO KEY UPDATE NOWAIT",
+		},
+		{
+			Expr:   tx.Select(u.ID).ForShare().Of(u),
+			Result: "SELECT `id` FOR SHARE OF `users_info`",
+		},
+		{
+			Expr:   inTx(student.DO).Select(student.ID).Join(teacher, teacher.ID.EqCol(student.Instructor)).ForUpdate().Of(student, teacher).SkipLocked(),
+			Result: "SELECT `student`.`id` FROM `student` INNER JOIN `teacher` ON `teacher`.`id` = `student`.`instructor` FOR UPDATE OF `student`,`teacher` SKIP LOCKED",
+		},
+		{
+			Expr:   tx.Select(u.ID).ForUpdate().ForKeyShare(),
+			Result: "SELECT `id` FOR KEY SHARE",
+		},
+	}
+	for _, tt := range testcases {
+		checkBuildExpr(t, tt.Expr.(*DO), nil, tt.Result, nil)
+	}
+
+	// OF references table of other schema by its name only
+	var invoice DO
+	invoice.UseDB(db.Session(&gorm.Session{Context: context.Background(), DryRun: true}))
+	invoice.UseModel(invoiceRaw{})
+	checkBuildExpr(t, inTx(invoice).Select(field.NewInt64("invoices", "id")).ForUpdate().Of(&invoice).(*DO), nil,
+		"SELECT `invoices`.`id` FOR UPDATE OF `invoices`", nil)
+
+	if err := u.ForUpdate().(*DO).db.Error; !errors.Is(err, ErrLockWithoutTransaction) {
+		t.Errorf("expect ErrLockWithoutTransaction, got %v", err)
+	}
+	if err := tx.SkipLocked().(*DO).db.Error; err == nil {
+		t.Errorf("expect error of SkipLocked without row locking")
+	}
+}
+
+func TestDO_InsertFrom(t *testing.T) {
+	testcases := []struct {
+		Insert       *InsertSelect
+		ExpectedVars []interface{}
+		Result       string
+	}{
+		{
+			Insert:       teacher.InsertFrom([]field.Expr{teacher.ID, teacher.Name}, student.Select(student.ID, student.Name).Where(student.Age.Gt(30))),
+			ExpectedVars: []interface{}{30},
+			Result:       "INSERT INTO `teacher` (`id`,`name`) SELECT `student`.`id`,`student`.`name` FROM `student` WHERE `student`.`age` > ?",
+		},
+		{
+			Insert: teacher.InsertFrom([]field.Expr{teacher.ID, teacher.Name}, student.Select(student.ID, student.Name)).
+				OnConflict(teacher.ID).DoUpdate(teacher.Name).Returning(teacher.ID),
+			Result: "INSERT INTO `teacher` (`id`,`name`) SELECT `student`.`id`,`student`.`name` FROM `student` ON CONFLICT (`id`) DO UPDATE SET `name`=`excluded`.`name` RETURNING `id`",
+		},
+		{
+			Insert: teacher.InsertFrom(nil, Union(student.Select(student.ID, student.Name), teacher.Select(teacher.ID, teacher.Name))).
+				DoNothing().Returning(),
+			Result: "INSERT INTO `teacher` (SELECT `student`.`id`,`student`.`name` FROM `student`) UNION (SELECT `teacher`.`id`,`teacher`.`name` FROM `teacher`) ON CONFLICT DO NOTHING RETURNING *",
+		},
+	}
+	for i, tt := range testcases {
+		stmt := tt.Insert.statement(func(db *gorm.DB, sql string, vars []interface{}) *gorm.DB { return db.Exec(sql, vars...) }).Statement
+		if sql := stmt.SQL.String(); sql != tt.Result {
+			t.Errorf("case %d: SQL expects %s got %s", i, tt.Result, sql)
+		}
+		if (len(stmt.Vars) > 0 || len(tt.ExpectedVars) > 0) && !reflect.DeepEqual(stmt.Vars, tt.ExpectedVars) {
+			t.Errorf("case %d: Vars expects %v got %v", i, tt.ExpectedVars, stmt.Vars)
+		}
+	}
+
+	if _, err := teacher.InsertFrom([]field.Expr{teacher.ID}, student.Select(student.ID, student.Name)).Exec(); err == nil {
+		t.Errorf("expect error of INSERT columns not matching selected columns")
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	// view DO embeds ReadOnly next to DO, write methods of both become ambiguous and leave the method set
+	view := reflect.TypeOf(&struct {
+		DO
+		ReadOnly
+	}{})
+	for _, method := range []string{"Create", "Save", "Update", "Delete", "Upsert", "InsertFrom", "UpdateQuery", "DeleteQuery",
+		"ForUpdate", "ForNoKeyUpdate", "ForShare", "ForKeyShare", "SkipLocked", "NoWait", "Of"} {
+		if _, ok := view.MethodByName(method); ok {
+			t.Errorf("unexpected write method %s of view DO", method)
+		}
+	}
+	if _, ok := view.MethodByName("Find"); !ok {
+		t.Errorf("expect read method Find of view DO")
+	}
+}
+
+func TestDO_DeleteUsing(t *testing.T) {
+	do := student.DeleteUsing(teacher).Where(teacher.ID.EqCol(student.Instructor), teacher.Name.Eq("tom")).(*DO)
+	stmt := do.db.Delete(&StudentRaw{}).Statement
+	result := "DELETE FROM `student` USING `teacher` WHERE `teacher`.`id` = `student`.`instructor` AND `teacher`.`name` = ?"
+	if sql := stmt.SQL.String(); sql != result {
+		t.Errorf("SQL expects %s got %s", result, sql)
+	}
+	if !reflect.DeepEqual(stmt.Vars, []interface{}{"tom"}) {
+		t.Errorf("Vars expects [tom] got %v", stmt.Vars)
+	}
+}
+
+func TestDO_exprError(t *testing.T) {
+	bad := u.Age.Cast("int); DROP TABLE users; --")
+	if err := u.Select(u.ID, bad).db.Error; err == nil {
+		t.Errorf("expect select of invalid cast to fail")
+	}
+	if err := u.Where(bad.IsNotNull()).db.Error; err == nil {
+		t.Errorf("expect condition of invalid cast to fail")
+	}
+	if err := u.Order(bad).db.Error; err == nil {
+		t.Errorf("expect order of invalid cast to fail")
+	}
+}
+
+func TestSetQuery_arity(t *testing.T) {
+	if err := Union(u.Select(u.ID), u.Select(u.ID, u.Name)).Err(); err == nil {
+		t.Errorf("expect error of queries with different number of columns")
+	}
+	if err := Union(u.Select(u.ID, u.Age.Max().As("age")), u.Select(u.ID, u.Name)).Err(); err != nil {
+		t.Errorf("expect queries with same number of columns, got %s", err)
+	}
+	if err := Intersect(u.Select(u.ID), Union(u.Select(u.ID, u.Name), u.Select(u.ID, u.Name))).Err(); err == nil {
+		t.Errorf("expect error of nested set operation with different number of columns")
+	}
+	if err := Table(Union(u.Select(u.ID), u.Select(u.ID, u.Name))).(*DO).db.Error; err == nil {
+		t.Errorf("expect error of set operation in Table")
+	}
+}
+
+func TestFuncTable(t *testing.T) {
+	items := JSONBArrayElements("item", field.NewJSONB("", "items"))
+	if items.TableName() != "item" || items.As("i").TableName() != "i" {
+		t.Errorf("unexpected alias of function table: %s", items.TableName())
+	}
+	if items.Value().ColumnName() != "value" {
+		t.Errorf("unexpected value column of function table: %s", items.Value().ColumnName())
+	}
+
+	bad := JSONBToRecordset("r", field.NewJSONB("", "items"), RecordColumn{Name: "qty", Type: "int; DROP TABLE x"})
+	if bad.Err() == nil {
+		t.Errorf("expect error of invalid record column type")
+	}
+	if err := teacher.Join(bad, field.NewInt("r", "qty").Gt(0)).(*DO).db.Error; err == nil {
+		t.Errorf("expect join of invalid function table to fail")
+	}
+	if err := teacher.JoinLateral(teacher.Select(teacher.ID), "").(*DO).db.Error; err == nil {
+		t.Errorf("expect lateral sub query without alias to fail")
+	}
+
+	// Table of function sources only has no connection to run on
+	series := Table(GenerateSeries("g", 1, 10)).Select()
+	if err := series.(*DO).db.Error; !errors.Is(err, ErrDetachedTable) {
+		t.Errorf("expect ErrDetachedTable of function table, got %v", err)
+	}
+	if _, err := series.(*DO).Find(); !errors.Is(err, ErrDetachedTable) {
+		t.Errorf("expect Find of function table to fail with ErrDetachedTable, got %v", err)
+	}
+}
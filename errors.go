@@ -4,3 +4,13 @@ import "errors"
 
 // ErrEmptyCondition empty condition
 var ErrEmptyCondition = errors.New("empty condition")
+
+// ErrReadOnly write operation on read-only query struct
+var ErrReadOnly = errors.New("read-only query struct")
+
+// ErrDetachedTable query on Table of CTE references or functions only, which has no connection until With adds a CTE
+var ErrDetachedTable = errors.New("table of CTE references or functions only needs With, or a query in Table, to take connection from")
+
+// ErrWithAfterQuery With is called on Table of CTE references after Where, Select or other query methods,
+// which were built without connection and would be lost
+var ErrWithAfterQuery = errors.New("With must be called before other methods on Table of CTE references")
@@ -0,0 +1,197 @@
+package field
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// aggregate aggregate function call, e.g. string_agg(?,? ORDER BY ?) FILTER (WHERE ?),
+// kept in expr to add FILTER and ORDER BY later
+type aggregate struct {
+	fn          string
+	args        []interface{}
+	order       []Expr
+	withinGroup bool // ordered-set aggregate, order is placed in WITHIN GROUP (ORDER BY ...)
+	filter      []Expr
+}
+
+func (a *aggregate) expr() clause.Expr {
+	var sql strings.Builder
+	vars := make([]interface{}, 0, len(a.args)+len(a.order)+len(a.filter))
+	list := func(exprs []Expr, sep string) {
+		for i, e := range exprs {
+			if i > 0 {
+				sql.WriteString(sep)
+			}
+			sql.WriteByte('?')
+			vars = append(vars, e.RawExpr())
+		}
+	}
+
+	sql.WriteString(a.fn + "(")
+	for i, arg := range a.args {
+		if i > 0 {
+			sql.WriteByte(',')
+		}
+		sql.WriteByte('?')
+		vars = append(vars, arg)
+	}
+	if len(a.order) > 0 && !a.withinGroup {
+		sql.WriteString(" ORDER BY ")
+		list(a.order, ",")
+	}
+	sql.WriteByte(')')
+	if len(a.order) > 0 && a.withinGroup {
+		sql.WriteString(" WITHIN GROUP (ORDER BY ")
+		list(a.order, ",")
+		sql.WriteByte(')')
+	}
+	if len(a.filter) > 0 {
+		sql.WriteString(" FILTER (WHERE ")
+		list(a.filter, " AND ")
+		sql.WriteByte(')')
+	}
+	return clause.Expr{SQL: sql.String(), Vars: vars}
+}
+
+func (e expr) setAgg(agg *aggregate) expr {
+	e = e.setE(agg.expr())
+	e.agg = agg
+	return e
+}
+
+func (e expr) aggregate(fn string, args ...interface{}) expr {
+	return e.setAgg(&aggregate{fn: fn, args: args})
+}
+
+// filter FILTER (WHERE cond) of aggregate, conditions are joined by AND,
+// it must be added before OVER which drops the aggregate
+func (e expr) filter(conds []Expr) expr {
+	if e.agg == nil {
+		e.err = errors.New("FILTER can only be used in aggregate function before OVER")
+		return e
+	}
+	for _, cond := range conds {
+		if err := cond.CondError(); err != nil && e.err == nil {
+			e.err = err
+		}
+	}
+	agg := *e.agg
+	agg.filter = append(append([]Expr{}, agg.filter...), conds...)
+	return e.setAgg(&agg)
+}
+
+// orderBy ORDER BY inside aggregate, or WITHIN GROUP of ordered-set aggregate
+func (e expr) orderBy(columns []Expr) expr {
+	if e.agg == nil {
+		e.err = errors.New("ORDER BY can only be used in aggregate function")
+		return e
+	}
+	agg := *e.agg
+	if agg.withinGroup {
+		agg.order = columns
+	} else {
+		agg.order = append(append([]Expr{}, agg.order...), columns...)
+	}
+	return e.setAgg(&agg)
+}
+
+// ======================== general-purpose aggregates ========================
+
+// ArrayAgg array_agg(expr), values including NULL as array
+func (e expr) ArrayAgg() Array { return Array{e.aggregate("array_agg", e.RawExpr())} }
+
+// JSONAgg json_agg(expr), values as json array
+func (e expr) JSONAgg() JSON { return JSON{e.aggregate("json_agg", e.RawExpr())} }
+
+// JSONBAgg jsonb_agg(expr), values as jsonb array
+func (e expr) JSONBAgg() JSONB { return JSONB{e.aggregate("jsonb_agg", e.RawExpr())} }
+
+// StringAgg string_agg(expr, sep), values concatenated by separator
+func (field String) StringAgg(sep string) String {
+	return String{field.aggregate("string_agg", field.RawExpr(), sep)}
+}
+
+// BoolAnd bool_and(expr), true if all values are true
+func (field Bool) BoolAnd() Bool { return Bool{field.aggregate("bool_and", field.RawExpr())} }
+
+// BoolOr bool_or(expr), true if any value is true
+func (field Bool) BoolOr() Bool { return Bool{field.aggregate("bool_or", field.RawExpr())} }
+
+// JSONBObjectAgg jsonb_object_agg(key, value), key/value pairs as jsonb object
+func JSONBObjectAgg(key, value Expr) JSONB {
+	return JSONB{expr{}.aggregate("jsonb_object_agg", key.RawExpr(), value.RawExpr())}
+}
+
+// ======================== ordered-set aggregates ========================
+
+// PercentileCont percentile_cont(fraction) WITHIN GROUP (ORDER BY column), interpolated percentile
+//
+//	field.PercentileCont(0.5, u.Salary).As("median")
+func PercentileCont(fraction float64, column Expr) Float64 {
+	return Float64{orderedSet("percentile_cont", column, fraction)}
+}
+
+// PercentileDisc percentile_disc(fraction) WITHIN GROUP (ORDER BY column), first value whose position is not less than fraction
+func PercentileDisc(fraction float64, column Expr) Field {
+	return Field{orderedSet("percentile_disc", column, fraction)}
+}
+
+// Mode mode() WITHIN GROUP (ORDER BY column), most frequent value
+func Mode(column Expr) Field { return Field{orderedSet("mode", column)} }
+
+func orderedSet(fn string, column Expr, args ...interface{}) expr {
+	return expr{}.setAgg(&aggregate{fn: fn, args: args, order: []Expr{column}, withinGroup: true})
+}
+
+// ======================== FILTER and ORDER BY ========================
+
+// Filter FILTER (WHERE conds) of aggregate, e.g. u.ID.Count().Filter(u.Age.Gt(18))
+func (field Int) Filter(conds ...Expr) Int { return Int{field.filter(conds)} }
+
+// OrderBy ORDER BY inside aggregate
+func (field Int) OrderBy(columns ...Expr) Int { return Int{field.orderBy(columns)} }
+
+// Filter FILTER (WHERE conds) of aggregate
+func (field Float64) Filter(conds ...Expr) Float64 { return Float64{field.filter(conds)} }
+
+// OrderBy ORDER BY inside aggregate, or WITHIN GROUP (ORDER BY ...) of ordered-set aggregate
+func (field Float64) OrderBy(columns ...Expr) Float64 { return Float64{field.orderBy(columns)} }
+
+// Filter FILTER (WHERE conds) of aggregate
+func (field String) Filter(conds ...Expr) String { return String{field.filter(conds)} }
+
+// OrderBy ORDER BY inside aggregate, e.g. u.Name.StringAgg(",").OrderBy(u.Name)
+func (field String) OrderBy(columns ...Expr) String { return String{field.orderBy(columns)} }
+
+// Filter FILTER (WHERE conds) of aggregate
+func (field Bool) Filter(conds ...Expr) Bool { return Bool{field.filter(conds)} }
+
+// OrderBy ORDER BY inside aggregate
+func (field Bool) OrderBy(columns ...Expr) Bool { return Bool{field.orderBy(columns)} }
+
+// Filter FILTER (WHERE conds) of aggregate
+func (f Array) Filter(conds ...Expr) Array { return Array{f.filter(conds)} }
+
+// OrderBy ORDER BY inside aggregate, e.g. u.ID.ArrayAgg().OrderBy(u.CreatedAt)
+func (f Array) OrderBy(columns ...Expr) Array { return Array{f.orderBy(columns)} }
+
+// Filter FILTER (WHERE conds) of aggregate
+func (f JSON) Filter(conds ...Expr) JSON { return JSON{f.filter(conds)} }
+
+// OrderBy ORDER BY inside aggregate
+func (f JSON) OrderBy(columns ...Expr) JSON { return JSON{f.orderBy(columns)} }
+
+// Filter FILTER (WHERE conds) of aggregate
+func (f JSONB) Filter(conds ...Expr) JSONB { return JSONB{f.filter(conds)} }
+
+// OrderBy ORDER BY inside aggregate
+func (f JSONB) OrderBy(columns ...Expr) JSONB { return JSONB{f.orderBy(columns)} }
+
+// Filter FILTER (WHERE conds) of aggregate
+func (field Field) Filter(conds ...Expr) Field { return Field{field.filter(conds)} }
+
+// OrderBy ORDER BY inside aggregate, or WITHIN GROUP (ORDER BY ...) of ordered-set aggregate
+func (field Field) OrderBy(columns ...Expr) Field { return Field{field.orderBy(columns)} }
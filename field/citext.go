@@ -0,0 +1,83 @@
+package field
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// CIText represents a PostgreSQL citext field. citext compares case-insensitively by itself,
+// so comparisons and LIKE are plain operators which keep the column index usable,
+// instead of LOWER(...) or ILIKE a text column needs.
+type CIText Field
+
+func NewCIText(table, column string, opts ...Option) CIText {
+	return CIText{expr: expr{col: toColumn(table, column, opts...)}}
+}
+
+// Eq equal to, case-insensitive
+func (f CIText) Eq(v string) Expr { return String(f).Eq(v) }
+
+// Neq not equal to, case-insensitive
+func (f CIText) Neq(v string) Expr { return String(f).Neq(v) }
+
+// Gt greater than, case-insensitive
+func (f CIText) Gt(v string) Expr { return String(f).Gt(v) }
+
+// Gte greater or equal to, case-insensitive
+func (f CIText) Gte(v string) Expr { return String(f).Gte(v) }
+
+// Lt less than, case-insensitive
+func (f CIText) Lt(v string) Expr { return String(f).Lt(v) }
+
+// Lte less or equal to, case-insensitive
+func (f CIText) Lte(v string) Expr { return String(f).Lte(v) }
+
+// Between inclusive range, case-insensitive
+func (f CIText) Between(left, right string) Expr { return String(f).Between(left, right) }
+
+// NotBetween negated range, case-insensitive
+func (f CIText) NotBetween(left, right string) Expr { return String(f).NotBetween(left, right) }
+
+// In set membership, case-insensitive
+func (f CIText) In(values ...string) Expr { return String(f).In(values...) }
+
+// NotIn negated set membership, case-insensitive
+func (f CIText) NotIn(values ...string) Expr { return String(f).NotIn(values...) }
+
+// Like LIKE, case-insensitive on citext
+func (f CIText) Like(pattern string) Expr { return String(f).Like(pattern) }
+
+// NotLike NOT LIKE, case-insensitive on citext
+func (f CIText) NotLike(pattern string) Expr { return String(f).NotLike(pattern) }
+
+// ILike same as Like on citext, no LOWER needed
+func (f CIText) ILike(pattern string) Expr { return f.Like(pattern) }
+
+// NotILike same as NotLike on citext
+func (f CIText) NotILike(pattern string) Expr { return f.NotLike(pattern) }
+
+// Regexp matches POSIX regular expression with ~, case-insensitive on citext
+func (f CIText) Regexp(pattern string) Expr {
+	return expr{e: clause.Expr{SQL: "? ~ ?", Vars: []interface{}{f.RawExpr(), pattern}}}
+}
+
+// NotRegexp negated Regexp
+func (f CIText) NotRegexp(pattern string) Expr {
+	return expr{e: clause.Expr{SQL: "? !~ ?", Vars: []interface{}{f.RawExpr(), pattern}}}
+}
+
+// CaseSensitiveEq equal to with case, compare as text, equal to "self::text = v"
+func (f CIText) CaseSensitiveEq(v string) Expr {
+	return expr{e: clause.Expr{SQL: "?::text = ?", Vars: []interface{}{f.RawExpr(), v}}}
+}
+
+// Lower converts to lower-case text
+func (f CIText) Lower() String { return String(f).Lower() }
+
+// Upper converts to upper-case text
+func (f CIText) Upper() String { return String(f).Upper() }
+
+// Value set value
+func (f CIText) Value(v string) AssignExpr { return f.value(v) }
+
+// Zero set empty string
+func (f CIText) Zero() AssignExpr { return f.value("") }
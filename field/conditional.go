@@ -0,0 +1,143 @@
+package field
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// typedExpr typed fields, e.g. Int, String, Float64
+type typedExpr interface {
+	~struct{ expr }
+	Expr
+}
+
+// CaseExpr CASE WHEN cond THEN value ... ELSE value END, created by Case
+//
+//	field.Case().When(u.Age.Lt(18), "minor").When(u.Age.Lt(60), "adult").Else("senior").String().As("stage")
+type CaseExpr struct {
+	whens   []interface{} // cond and value pairs
+	els     interface{}
+	hasElse bool
+	err     error
+}
+
+// Case new CASE expression
+func Case() CaseExpr { return CaseExpr{} }
+
+// When WHEN cond THEN value, value is column expression or bound value
+func (c CaseExpr) When(cond Expr, value interface{}) CaseExpr {
+	if err := cond.CondError(); err != nil && c.err == nil {
+		c.err = err
+	}
+	c.whens = append(append([]interface{}{}, c.whens...), cond.RawExpr(), exprValue(value))
+	return c
+}
+
+// Else ELSE value, NULL when it's not set
+func (c CaseExpr) Else(value interface{}) CaseExpr {
+	c.els, c.hasElse = exprValue(value), true
+	return c
+}
+
+// String CASE expression of string result
+func (c CaseExpr) String() String { return String{c.expr()} }
+
+// Int CASE expression of integer result, CAST(CASE ... END AS integer)
+func (c CaseExpr) Int() Int { return Int{c.cast("integer")} }
+
+// Float64 CASE expression of float result, CAST(CASE ... END AS double precision)
+func (c CaseExpr) Float64() Float64 { return Float64{c.cast("double precision")} }
+
+// Bool CASE expression of boolean result, CAST(CASE ... END AS boolean)
+func (c CaseExpr) Bool() Bool { return Bool{c.cast("boolean")} }
+
+// Field CASE expression of other result
+func (c CaseExpr) Field() Field { return Field{c.expr()} }
+
+func (c CaseExpr) expr() expr {
+	if len(c.whens) == 0 {
+		return expr{e: clause.Expr{SQL: "NULL"}, err: errors.New("CASE requires at least one WHEN")}
+	}
+	var sql strings.Builder
+	sql.WriteString("CASE")
+	for i := 0; i < len(c.whens); i += 2 {
+		sql.WriteString(" WHEN ? THEN ?")
+	}
+	vars := c.whens
+	if c.hasElse {
+		sql.WriteString(" ELSE ?")
+		vars = append(append([]interface{}{}, vars...), c.els)
+	}
+	sql.WriteString(" END")
+	return expr{e: clause.Expr{SQL: sql.String(), Vars: vars}, err: c.err}
+}
+
+// cast CAST(CASE ... END AS typ), CASE of bound values only resolves to text, e.g. THEN 1 ELSE 0
+func (c CaseExpr) cast(typ string) expr {
+	return c.expr().Cast(typ).expr
+}
+
+// exprValue raw expression of column, or value itself
+func exprValue(value interface{}) interface{} {
+	if e, ok := value.(Expr); ok {
+		return e.RawExpr()
+	}
+	return value
+}
+
+// Coalesce COALESCE(field, values...), first non-null value,
+// values are column expressions or bound values
+//
+//	field.Coalesce(u.Nickname, u.Name, "anonymous")
+func Coalesce[T typedExpr](field T, values ...interface{}) T {
+	return variadic[T]("COALESCE", field, values)
+}
+
+// NullIf NULLIF(field, value), NULL when field equals to value
+func NullIf[T typedExpr](field T, value interface{}) T {
+	e := struct{ expr }(field).expr
+	return T{e.setE(clause.Expr{SQL: "NULLIF(?,?)", Vars: []interface{}{e.RawExpr(), exprValue(value)}})}
+}
+
+// Greatest GREATEST(field, values...), largest value ignoring NULL
+func Greatest[T typedExpr](field T, values ...interface{}) T {
+	return variadic[T]("GREATEST", field, values)
+}
+
+// Least LEAST(field, values...), smallest value ignoring NULL
+func Least[T typedExpr](field T, values ...interface{}) T {
+	return variadic[T]("LEAST", field, values)
+}
+
+func variadic[T typedExpr](fn string, field T, values []interface{}) T {
+	e := struct{ expr }(field).expr
+	placeholders := []string{"?"}
+	vars := []interface{}{e.RawExpr()}
+	for _, value := range values {
+		placeholders = append(placeholders, "?")
+		vars = append(vars, exprValue(value))
+	}
+	return T{e.setE(clause.Expr{SQL: fn + "(" + strings.Join(placeholders, ",") + ")", Vars: vars})}
+}
+
+// typeName safe type name, e.g. numeric(10,2), double precision, text[]
+var typeName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*( [A-Za-z_][A-Za-z0-9_]*)*(\(\d+(,\s*\d+)?\))?(\[\])?$`)
+
+// IsTypeName whether typ is a plain type name that is safe to write into SQL,
+// e.g. integer, numeric(10,2), double precision, text[]
+func IsTypeName(typ string) bool { return typeName.MatchString(typ) }
+
+// Cast CAST(expr AS typ), typ must be a plain type name like numeric(10,2) or text[],
+// otherwise the expression is not cast and reports error by CondError
+func (e expr) Cast(typ string) Field {
+	typ = strings.TrimSpace(typ)
+	if !IsTypeName(typ) {
+		e.err = fmt.Errorf("invalid cast type %q", typ)
+		return Field{e}
+	}
+	return Field{e.setE(clause.Expr{SQL: "CAST(? AS " + typ + ")", Vars: []interface{}{e.RawExpr()}})}
+}
@@ -0,0 +1,107 @@
+package field
+
+import (
+	"fmt"
+	"strings"
+
+	"go.ipao.vip/gen/types"
+	"gorm.io/gorm/clause"
+)
+
+// Date represents a PostgreSQL date field
+type Date Field
+
+func NewDate(table, column string, opts ...Option) Date {
+	return Date{expr: expr{col: toColumn(table, column, opts...)}}
+}
+
+// Eq equal to
+func (f Date) Eq(v types.Date) Expr { return expr{e: clause.Eq{Column: f.RawExpr(), Value: v}} }
+
+// Neq not equal to
+func (f Date) Neq(v types.Date) Expr { return expr{e: clause.Neq{Column: f.RawExpr(), Value: v}} }
+
+// Gt greater than
+func (f Date) Gt(v types.Date) Expr { return expr{e: clause.Gt{Column: f.RawExpr(), Value: v}} }
+
+// Gte greater or equal to
+func (f Date) Gte(v types.Date) Expr { return expr{e: clause.Gte{Column: f.RawExpr(), Value: v}} }
+
+// Lt less than
+func (f Date) Lt(v types.Date) Expr { return expr{e: clause.Lt{Column: f.RawExpr(), Value: v}} }
+
+// Lte less or equal to
+func (f Date) Lte(v types.Date) Expr { return expr{e: clause.Lte{Column: f.RawExpr(), Value: v}} }
+
+// In set membership
+func (f Date) In(values ...types.Date) Expr {
+	return expr{e: clause.IN{Column: f.RawExpr(), Values: f.toSlice(values...)}}
+}
+
+// NotIn negated set membership
+func (f Date) NotIn(values ...types.Date) Expr {
+	return expr{e: clause.Not(f.In(values...).expression())}
+}
+
+// Between inclusive range
+func (f Date) Between(left, right types.Date) Expr {
+	return f.between([]interface{}{left, right})
+}
+
+// NotBetween negated range
+func (f Date) NotBetween(left, right types.Date) Expr {
+	return Not(f.Between(left, right))
+}
+
+// AddDays date + integer, days later
+func (f Date) AddDays(days int) Date { return Date{f.add(days)} }
+
+// SubDays date - integer, days earlier
+func (f Date) SubDays(days int) Date { return Date{f.sub(days)} }
+
+// DaysSince date - other, number of days from other to f
+func (f Date) DaysSince(other types.Date) Int {
+	return Int{expr{e: clause.Expr{SQL: "?-?::date", Vars: []interface{}{f.RawExpr(), other}}}}
+}
+
+// DatePart equal to DATE_PART(part, self), part is year, month, day, dow, doy, week, quarter...
+func (f Date) DatePart(part string) Float64 { return datePart(f.expr, part) }
+
+// Time convert to timestamp at midnight, equal to "self::timestamp"
+func (f Date) Time() Time {
+	return Time{expr{e: clause.Expr{SQL: "?::timestamp", Vars: []interface{}{f.RawExpr()}}}}
+}
+
+// At combine with time of day into timestamp, equal to "self + time"
+func (f Date) At(t types.Time) Time {
+	return Time{expr{e: clause.Expr{SQL: "?+?::time", Vars: []interface{}{f.RawExpr(), t}}}}
+}
+
+// Max MAX(f)
+func (f Date) Max() Date { return Date{f.aggregate("MAX", f.RawExpr())} }
+
+// Min MIN(f)
+func (f Date) Min() Date { return Date{f.aggregate("MIN", f.RawExpr())} }
+
+// Value set value
+func (f Date) Value(v types.Date) AssignExpr { return f.value(v) }
+
+// Zero set zero value
+func (f Date) Zero() AssignExpr { return f.value(types.Date{}) }
+
+func (f Date) toSlice(values ...types.Date) []interface{} {
+	slice := make([]interface{}, len(values))
+	for i, v := range values {
+		slice[i] = v
+	}
+	return slice
+}
+
+// datePart DATE_PART(part, e), unknown part reports error by CondError instead of failing in database
+func datePart(e expr, part string) Float64 {
+	if !isDatePart(strings.ToLower(part)) {
+		e.err = fmt.Errorf("invalid DATE_PART part %q", part)
+		return Float64{e}
+	}
+	return Float64{expr{e: clause.Expr{SQL: "DATE_PART(?,?)", Vars: []interface{}{strings.ToLower(part), e.RawExpr()}}}}
+}
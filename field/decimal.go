@@ -0,0 +1,104 @@
+package field
+
+import (
+	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/types"
+)
+
+// Decimal represents a PostgreSQL numeric field, arithmetic and aggregates keep numeric results
+type Decimal Field
+
+// NewDecimal create new Decimal
+func NewDecimal(table, column string, opts ...Option) Decimal {
+	return Decimal{expr: expr{col: toColumn(table, column, opts...)}}
+}
+
+// Eq equal to
+func (f Decimal) Eq(v types.Decimal) Expr { return expr{e: clause.Eq{Column: f.RawExpr(), Value: v}} }
+
+// Neq not equal to
+func (f Decimal) Neq(v types.Decimal) Expr { return expr{e: clause.Neq{Column: f.RawExpr(), Value: v}} }
+
+// Gt greater than
+func (f Decimal) Gt(v types.Decimal) Expr { return expr{e: clause.Gt{Column: f.RawExpr(), Value: v}} }
+
+// Gte greater or equal to
+func (f Decimal) Gte(v types.Decimal) Expr { return expr{e: clause.Gte{Column: f.RawExpr(), Value: v}} }
+
+// Lt less than
+func (f Decimal) Lt(v types.Decimal) Expr { return expr{e: clause.Lt{Column: f.RawExpr(), Value: v}} }
+
+// Lte less or equal to
+func (f Decimal) Lte(v types.Decimal) Expr { return expr{e: clause.Lte{Column: f.RawExpr(), Value: v}} }
+
+// In set membership
+func (f Decimal) In(values ...types.Decimal) Expr {
+	return expr{e: clause.IN{Column: f.RawExpr(), Values: f.toSlice(values...)}}
+}
+
+// NotIn negated set membership
+func (f Decimal) NotIn(values ...types.Decimal) Expr {
+	return expr{e: clause.Not(f.In(values...).expression())}
+}
+
+// Between inclusive range
+func (f Decimal) Between(left, right types.Decimal) Expr {
+	return f.between([]interface{}{left, right})
+}
+
+// NotBetween negated range
+func (f Decimal) NotBetween(left, right types.Decimal) Expr {
+	return Not(f.Between(left, right))
+}
+
+// Add f + v
+func (f Decimal) Add(v types.Decimal) Decimal { return Decimal{f.add(v)} }
+
+// Sub f - v
+func (f Decimal) Sub(v types.Decimal) Decimal { return Decimal{f.sub(v)} }
+
+// Mul f * v
+func (f Decimal) Mul(v types.Decimal) Decimal { return Decimal{f.mul(v)} }
+
+// Div f / v, numeric division, not truncated to integer
+func (f Decimal) Div(v types.Decimal) Decimal { return Decimal{f.div(v)} }
+
+// Round ROUND(f, scale), half away from zero as PostgreSQL does for numeric
+func (f Decimal) Round(scale int) Decimal {
+	return Decimal{f.setE(clause.Expr{SQL: "ROUND(?,?)", Vars: []interface{}{f.RawExpr(), scale}})}
+}
+
+// Trunc TRUNC(f, scale), truncate toward zero
+func (f Decimal) Trunc(scale int) Decimal {
+	return Decimal{f.setE(clause.Expr{SQL: "TRUNC(?,?)", Vars: []interface{}{f.RawExpr(), scale}})}
+}
+
+// Sum SUM(f), numeric result
+func (f Decimal) Sum() Decimal { return Decimal{f.sum()} }
+
+// Avg AVG(f), numeric result
+func (f Decimal) Avg() Decimal { return Decimal{f.aggregate("AVG", f.RawExpr())} }
+
+// Max MAX(f)
+func (f Decimal) Max() Decimal { return Decimal{f.aggregate("MAX", f.RawExpr())} }
+
+// Min MIN(f)
+func (f Decimal) Min() Decimal { return Decimal{f.aggregate("MIN", f.RawExpr())} }
+
+// Filter FILTER (WHERE conds) of aggregate, e.g. o.Amount.Sum().Filter(o.Status.Eq("paid"))
+func (f Decimal) Filter(conds ...Expr) Decimal { return Decimal{f.filter(conds)} }
+
+// Value set value
+func (f Decimal) Value(v types.Decimal) AssignExpr { return f.value(v) }
+
+// Zero set zero value
+func (f Decimal) Zero() AssignExpr { return f.value(types.Decimal{}) }
+
+func (f Decimal) toSlice(values ...types.Decimal) []interface{} {
+	slice := make([]interface{}, len(values))
+	for i, v := range values {
+		slice[i] = v
+	}
+	return slice
+}
@@ -0,0 +1,66 @@
+package field
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// Enum PostgreSQL enum type field, T is the generated enum type
+type Enum[T ~string] Field
+
+// NewEnum ...
+func NewEnum[T ~string](table, column string, opts ...Option) Enum[T] {
+	return Enum[T]{expr: expr{col: toColumn(table, column, opts...)}}
+}
+
+// Eq equal to
+func (field Enum[T]) Eq(value T) Expr {
+	return expr{e: clause.Eq{Column: field.RawExpr(), Value: value}}
+}
+
+// Neq not equal to
+func (field Enum[T]) Neq(value T) Expr {
+	return expr{e: clause.Neq{Column: field.RawExpr(), Value: value}}
+}
+
+// Gt greater than, compared by the declaration order of enum labels
+func (field Enum[T]) Gt(value T) Expr {
+	return expr{e: clause.Gt{Column: field.RawExpr(), Value: value}}
+}
+
+// Gte greater or equal to
+func (field Enum[T]) Gte(value T) Expr {
+	return expr{e: clause.Gte{Column: field.RawExpr(), Value: value}}
+}
+
+// Lt less than
+func (field Enum[T]) Lt(value T) Expr {
+	return expr{e: clause.Lt{Column: field.RawExpr(), Value: value}}
+}
+
+// Lte less or equal to
+func (field Enum[T]) Lte(value T) Expr {
+	return expr{e: clause.Lte{Column: field.RawExpr(), Value: value}}
+}
+
+// In ...
+func (field Enum[T]) In(values ...T) Expr {
+	return expr{e: clause.IN{Column: field.RawExpr(), Values: field.toSlice(values)}}
+}
+
+// NotIn ...
+func (field Enum[T]) NotIn(values ...T) Expr {
+	return expr{e: clause.Not(field.In(values...).expression())}
+}
+
+// Value ...
+func (field Enum[T]) Value(value T) AssignExpr {
+	return field.value(value)
+}
+
+func (field Enum[T]) toSlice(values []T) []interface{} {
+	slice := make([]interface{}, len(values))
+	for i, v := range values {
+		slice[i] = v
+	}
+	return slice
+}
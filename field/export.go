@@ -39,6 +39,11 @@ func NewUnsafeFieldRaw(rawSQL string, vars ...interface{}) Field {
 	return Field{expr: expr{e: clause.Expr{SQL: rawSQL, Vars: vars}}}
 }
 
+// Excluded column of the row proposed for insertion, used in ON CONFLICT DO UPDATE, e.g. EXCLUDED.name
+func Excluded(col Expr) Field {
+	return Field{expr: expr{col: clause.Column{Table: "excluded", Name: string(col.ColumnName())}}}
+}
+
 // NewSerializer create new field2
 func NewSerializer(table, column string, opts ...Option) Serializer {
 	return Serializer{expr: expr{col: toColumn(table, column, opts...)}}
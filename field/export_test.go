@@ -8,12 +8,15 @@ import (
 	"time"
 
 	"go.ipao.vip/gen/field"
+	"go.ipao.vip/gen/types"
 )
 
 var _ field.ScanValuer = new(password)
 
 type password string
 
+type orderStatus string
+
 func (p *password) Scan(src interface{}) error {
 	*p = password(fmt.Sprintf("this is password {%q}", src))
 	return nil
@@ -88,7 +91,7 @@ func TestExpr_Build(t *testing.T) {
 		},
 		{
 			Expr:   field.NewField("", "id").GroupConcat(),
-			Result: "GROUP_CONCAT(`id`)",
+			Result: "string_agg(`id`, ',')",
 		},
 		{
 			Expr:         field.NewUnsafeFieldRaw("if(column1=?,column2,column3)", "1"),
@@ -122,7 +125,7 @@ func TestExpr_Build(t *testing.T) {
 		},
 		{
 			Expr:         field.NewUnsafeFieldRaw("if(column1=?,column2,column3)", "1").GroupConcat(),
-			Result:       "GROUP_CONCAT(if(column1=?,column2,column3))",
+			Result:       "string_agg(if(column1=?,column2,column3), ',')",
 			ExpectedVars: []interface{}{"1"},
 		},
 		{
@@ -339,6 +342,36 @@ func TestExpr_Build(t *testing.T) {
 			ExpectedVars: []interface{}{float64(3.0)},
 			Result:       "`score` DIV ?",
 		},
+		// ======================== decimal ========================
+		{
+			Expr:         field.NewDecimal("", "amount").Gte(types.MustDecimal("10.50")),
+			ExpectedVars: []interface{}{"10.50"},
+			Result:       "`amount` >= ?",
+		},
+		{
+			Expr:         field.NewDecimal("", "amount").Mul(types.MustDecimal("1.08")).Round(2),
+			ExpectedVars: []interface{}{"1.08", 2},
+			Result:       "ROUND(`amount`*?,?)",
+		},
+		{
+			Expr:         field.NewDecimal("", "amount").Sum().Filter(field.NewString("", "status").Eq("paid")).Gt(types.MustDecimal("100")),
+			ExpectedVars: []interface{}{"paid", "100"},
+			Result:       "SUM(`amount`) FILTER (WHERE `status` = ?) > ?",
+		},
+		{
+			Expr:   field.NewDecimal("", "amount").Avg().As("avg_amount"),
+			Result: "AVG(`amount`) AS `avg_amount`",
+		},
+		// ======================== money ========================
+		{
+			Expr:         field.NewMoney("", "price").Mul(3).Add(types.NewMoneyFromMinor(500)),
+			ExpectedVars: []interface{}{int64(3), "5.00"},
+			Result:       "`price`*?+?",
+		},
+		{
+			Expr:   field.NewMoney("", "total").Sum().Filter(field.NewBool("", "paid")).As("revenue"),
+			Result: "SUM(`total`) FILTER (WHERE `paid`) AS `revenue`",
+		},
 		// ======================== string ========================
 		{
 			Expr:         field.NewString("", "name").Eq("tom"),
@@ -432,19 +465,185 @@ func TestExpr_Build(t *testing.T) {
 			Result:       "`creatAt` BETWEEN ? AND ?",
 		},
 		{
-			Expr:         field.NewTime("", "creatAt").Add(24 * time.Hour),
-			ExpectedVars: []interface{}{time.Duration(24 * time.Hour).Microseconds()},
-			Result:       "DATE_ADD(`creatAt`, INTERVAL ? MICROSECOND)",
+			Expr:         field.NewTime("", "creatAt").Add(types.IntervalOf(24 * time.Hour)),
+			ExpectedVars: []interface{}{"24:00:00"},
+			Result:       "`creatAt`+?::interval",
+		},
+		{
+			Expr:         field.NewTime("", "creatAt").AddDuration(time.Hour).SubDuration(90 * time.Second),
+			ExpectedVars: []interface{}{"01:00:00", "00:01:30"},
+			Result:       "`creatAt`+?::interval-?::interval",
+		},
+		{
+			Expr:         field.NewTime("", "creatAt").Sub(types.NewInterval(1, 2, 0)),
+			ExpectedVars: []interface{}{"1 mon 2 days"},
+			Result:       "`creatAt`-?::interval",
 		},
 		{
-			Expr:         field.NewTime("", "creatAt").Sub(24 * time.Hour),
-			ExpectedVars: []interface{}{time.Duration(24 * time.Hour).Microseconds()},
-			Result:       "DATE_SUB(`creatAt`, INTERVAL ? MICROSECOND)",
+			Expr:         field.NewTime("", "updateAt").ToChar("YYYY-MM-DD"),
+			ExpectedVars: []interface{}{"YYYY-MM-DD"},
+			Result:       "TO_CHAR(`updateAt`,?)",
 		},
 		{
-			Expr:         field.NewTime("", "updateAt").DateFormat("%W %M %Y"),
-			ExpectedVars: []interface{}{"%W %M %Y"},
-			Result:       "DATE_FORMAT(`updateAt`,?)",
+			Expr:         field.NewTime("", "createdAt").DateTrunc("month").As("month"),
+			ExpectedVars: []interface{}{"month"},
+			Result:       "DATE_TRUNC(?,`createdAt`) AS `month`",
+		},
+		{
+			Expr:   field.NewTime("", "createdAt").Extract("epoch"),
+			Result: "EXTRACT(EPOCH FROM `createdAt`)",
+		},
+		{
+			Expr:   field.NewTime("", "createdAt").Year(),
+			Result: "EXTRACT(YEAR FROM `createdAt`)::int",
+		},
+		{
+			Expr:         field.NewTime("", "createdAt").AtTimeZone("Asia/Shanghai").Date(),
+			ExpectedVars: []interface{}{"Asia/Shanghai"},
+			Result:       "DATE(`createdAt` AT TIME ZONE ?)",
+		},
+		{
+			Expr:         field.NewTime("", "birthday").Age(timeData).Gt(types.NewInterval(18*12, 0, 0)),
+			ExpectedVars: []interface{}{timeData, "18 years"},
+			Result:       "AGE(`birthday`,?) > ?",
+		},
+		{
+			Expr:         field.NewTime("", "start_at").Overlaps(field.NewTime("", "end_at"), timeData, timeData.Add(time.Hour)),
+			ExpectedVars: []interface{}{timeData, timeData.Add(time.Hour)},
+			Result:       "(`start_at`,`end_at`) OVERLAPS (?,?)",
+		},
+		{
+			Expr:         field.NewTime("", "createdAt").DateDiff(timeData),
+			ExpectedVars: []interface{}{timeData},
+			Result:       "`createdAt`::date-?::date",
+		},
+		// ======================== date ========================
+		{
+			Expr:         field.NewDate("", "birthday").Between(types.Date(timeData), types.Date(timeData.AddDate(0, 1, 0))),
+			ExpectedVars: []interface{}{time.Date(2021, 6, 29, 0, 0, 0, 0, time.UTC), time.Date(2021, 7, 29, 0, 0, 0, 0, time.UTC)},
+			Result:       "`birthday` BETWEEN ? AND ?",
+		},
+		{
+			Expr:         field.NewDate("", "due_on").AddDays(7).Lt(types.Date(timeData)),
+			ExpectedVars: []interface{}{7, time.Date(2021, 6, 29, 0, 0, 0, 0, time.UTC)},
+			Result:       "`due_on`+? < ?",
+		},
+		{
+			Expr:         field.NewDate("", "due_on").DatePart("DOW"),
+			ExpectedVars: []interface{}{"dow"},
+			Result:       "DATE_PART(?,`due_on`)",
+		},
+		{
+			Expr:   field.NewDate("", "due_on").Time(),
+			Result: "`due_on`::timestamp",
+		},
+		{
+			Expr:         field.NewDate("", "due_on").At(types.NewTime(18, 30, 0, 0)),
+			ExpectedVars: []interface{}{"18:30:00"},
+			Result:       "`due_on`+?::time",
+		},
+		// ======================== time of day ========================
+		{
+			Expr:         field.NewTimeOfDay("", "opens_at").Between(types.NewTime(9, 0, 0, 0), types.NewTime(17, 0, 0, 0)),
+			ExpectedVars: []interface{}{"09:00:00", "17:00:00"},
+			Result:       "`opens_at` BETWEEN ? AND ?",
+		},
+		{
+			Expr:         field.NewTimeOfDay("", "opens_at").Add(types.IntervalOf(30*time.Minute)).In(types.NewTime(9, 30, 0, 0), types.NewTime(10, 0, 0, 0)),
+			ExpectedVars: []interface{}{"00:30:00", "09:30:00", "10:00:00"},
+			Result:       "`opens_at`+?::interval IN (?,?)",
+		},
+		{
+			Expr:         field.NewTimeOfDay("", "opens_at").On(types.Date(timeData)),
+			ExpectedVars: []interface{}{time.Date(2021, 6, 29, 0, 0, 0, 0, time.UTC)},
+			Result:       "?::date+`opens_at`",
+		},
+		// ======================== interval ========================
+		{
+			Expr:         field.NewInterval("", "duration").Sum().Filter(field.NewBool("", "billable")).Gt(types.IntervalOf(90 * time.Minute)),
+			ExpectedVars: []interface{}{"01:30:00"},
+			Result:       "SUM(`duration`) FILTER (WHERE `billable`) > ?",
+		},
+		{
+			Expr:   field.NewInterval("", "duration").Avg().Extract("epoch"),
+			Result: "EXTRACT(EPOCH FROM AVG(`duration`))",
+		},
+		// ======================== hstore ========================
+		{
+			Expr:         field.NewHStore("", "attrs").Get("color").Eq("red"),
+			ExpectedVars: []interface{}{"color", "red"},
+			Result:       "`attrs`->?::text = ?",
+		},
+		{
+			Expr:         field.NewHStore("", "attrs").HasKey("color"),
+			ExpectedVars: []interface{}{"color"},
+			Result:       "exist(`attrs`,?)",
+		},
+		{
+			Expr:         field.NewHStore("", "attrs").HasAllKeys("color", "size"),
+			ExpectedVars: []interface{}{"color", "size"},
+			Result:       "exists_all(`attrs`,ARRAY[?,?]::text[])",
+		},
+		{
+			Expr:         field.NewHStore("", "attrs").Contains(types.NewHStore(map[string]string{"size": "L", "color": "red"})),
+			ExpectedVars: []interface{}{`"color"=>"red", "size"=>"L"`},
+			Result:       "`attrs` @> ?",
+		},
+		{
+			Expr:   field.NewHStore("", "attrs").Keys().Contains(field.NewArray("", "tags")),
+			Result: "akeys(`attrs`) @> `tags`",
+		},
+		{
+			Expr:         field.NewHStore("", "attrs").DeleteKey("color", "size").(field.Expr),
+			ExpectedVars: []interface{}{"color", "size"},
+			Result:       "`attrs` = delete(`attrs`,ARRAY[?,?]::text[])",
+		},
+		// ======================== ltree ========================
+		{
+			Expr:         field.NewLTree("", "path").IsDescendantOf(types.LTree("Top.Science")),
+			ExpectedVars: []interface{}{"Top.Science"},
+			Result:       "`path` <@ ?",
+		},
+		{
+			Expr:         field.NewLTree("", "path").Match("*.Astronomy.*"),
+			ExpectedVars: []interface{}{"*.Astronomy.*"},
+			Result:       "`path` ~ ?::lquery",
+		},
+		{
+			Expr:         field.NewLTree("", "path").MatchAny("Top.*", "*.Hobbies"),
+			ExpectedVars: []interface{}{"Top.*", "*.Hobbies"},
+			Result:       "lt_q_regex(`path`,ARRAY[?,?]::lquery[])",
+		},
+		{
+			Expr:         field.NewLTree("", "path").MatchText("Europe & Russia*@"),
+			ExpectedVars: []interface{}{"Europe & Russia*@"},
+			Result:       "`path` @ ?::ltxtquery",
+		},
+		{
+			Expr:         field.NewLTree("", "path").Subpath(0, 2).Eq(types.LTree("Top.Science")),
+			ExpectedVars: []interface{}{0, 2, "Top.Science"},
+			Result:       "subpath(`path`,?,?) = ?",
+		},
+		{
+			Expr:         field.NewLTree("", "path").NLevel().Gt(2),
+			ExpectedVars: []interface{}{2},
+			Result:       "nlevel(`path`) > ?",
+		},
+		// ======================== citext ========================
+		{
+			Expr:         field.NewCIText("", "email").Eq("Foo@Example.com"),
+			ExpectedVars: []interface{}{"Foo@Example.com"},
+			Result:       "`email` = ?",
+		},
+		{
+			Expr:         field.NewCIText("", "email").ILike("%@example.com"),
+			ExpectedVars: []interface{}{"%@example.com"},
+			Result:       "`email` LIKE ?",
+		},
+		{
+			Expr:         field.NewCIText("", "email").CaseSensitiveEq("Foo@Example.com"),
+			ExpectedVars: []interface{}{"Foo@Example.com"},
+			Result:       "`email`::text = ?",
 		},
 		// ======================== bool ========================
 		{
@@ -466,6 +665,186 @@ func TestExpr_Build(t *testing.T) {
 			ExpectedVars: []interface{}{true},
 			Result:       "`male` OR ?",
 		},
+		// ======================== enum ========================
+		{
+			Expr:         field.NewEnum[orderStatus]("order", "status").Eq("paid"),
+			ExpectedVars: []interface{}{orderStatus("paid")},
+			Result:       "`order`.`status` = ?",
+		},
+		{
+			Expr:         field.NewEnum[orderStatus]("", "status").In("paid", "shipped"),
+			ExpectedVars: []interface{}{orderStatus("paid"), orderStatus("shipped")},
+			Result:       "`status` IN (?,?)",
+		},
+		{
+			Expr:         field.NewEnum[orderStatus]("", "status").NotIn("canceled"),
+			ExpectedVars: []interface{}{orderStatus("canceled")},
+			Result:       "`status` <> ?",
+		},
+		// ======================== window ========================
+		{
+			Expr:   field.RowNumber().Over(field.PartitionBy(field.NewInt("", "dept_id")).OrderBy(field.NewInt("", "salary").Desc())).As("rn"),
+			Result: "ROW_NUMBER() OVER (PARTITION BY `dept_id` ORDER BY `salary` DESC) AS `rn`",
+		},
+		{
+			Expr:   field.DenseRank().Over(field.OrderBy(field.NewInt("", "score"))),
+			Result: "DENSE_RANK() OVER (ORDER BY `score`)",
+		},
+		{
+			Expr:         field.Lag(field.NewFloat64("", "price"), 1).Over(field.OrderBy(field.NewTime("", "day"))),
+			ExpectedVars: []interface{}{1},
+			Result:       "LAG(`price`, ?) OVER (ORDER BY `day`)",
+		},
+		{
+			Expr:   field.NewInt("", "amount").Sum().Over(field.PartitionBy(field.NewInt("", "user_id")).OrderBy(field.NewInt("", "id")).Rows(field.UnboundedPreceding, field.CurrentRow)),
+			Result: "SUM(`amount`) OVER (PARTITION BY `user_id` ORDER BY `id` ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)",
+		},
+		{
+			Expr:         field.NewFloat64("", "price").Avg().Over(field.OrderBy(field.NewInt("", "id")).Range(field.Preceding(2), field.Following(2))).Gt(10),
+			ExpectedVars: []interface{}{2, 2, float64(10)},
+			Result:       "AVG(`price`) OVER (ORDER BY `id` RANGE BETWEEN ? PRECEDING AND ? FOLLOWING) > ?",
+		},
+		{
+			Expr:         field.NewDecimal("", "amount").Sum().Over(field.PartitionBy(field.NewInt("", "user_id"))).Gt(types.MustDecimal("100")),
+			ExpectedVars: []interface{}{"100"},
+			Result:       "SUM(`amount`) OVER (PARTITION BY `user_id`) > ?",
+		},
+		{
+			Expr:   field.NewInterval("", "duration").Avg().Over(field.OrderBy(field.NewInt("", "id"))),
+			Result: "AVG(`duration`) OVER (ORDER BY `id`)",
+		},
+		{
+			Expr:         field.NewString("", "name").StringAgg(",").Over(field.PartitionBy(field.NewInt("", "dept_id"))),
+			ExpectedVars: []interface{}{","},
+			Result:       "string_agg(`name`,?) OVER (PARTITION BY `dept_id`)",
+		},
+		{
+			Expr:         field.Lead(field.NewDecimal("", "amount"), 1).Over(field.OrderBy(field.NewInt("", "id"))).Lt(types.MustDecimal("0")),
+			ExpectedVars: []interface{}{1, "0"},
+			Result:       "LEAD(`amount`, ?) OVER (ORDER BY `id`) < ?",
+		},
+		{
+			Expr:         field.NthValue(field.NewTime("", "created_at"), 2).Over(field.OrderBy(field.NewInt("", "id"))).Gt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			ExpectedVars: []interface{}{2, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			Result:       "NTH_VALUE(`created_at`, ?) OVER (ORDER BY `id`) > ?",
+		},
+		{
+			Expr:   field.FirstValue(field.NewString("", "name")).Over(field.PartitionBy(field.NewInt("", "dept_id"))).Upper(),
+			Result: "UPPER(FIRST_VALUE(`name`) OVER (PARTITION BY `dept_id`))",
+		},
+		// ======================== conditional ========================
+		{
+			Expr:         field.Case().When(field.NewInt("", "age").Lt(18), "minor").Else("adult").String().As("stage"),
+			ExpectedVars: []interface{}{18, "minor", "adult"},
+			Result:       "CASE WHEN `age` < ? THEN ? ELSE ? END AS `stage`",
+		},
+		{
+			Expr:         field.Case().When(field.NewBool("", "vip"), field.NewFloat64("", "price")).Float64().Mul(2),
+			ExpectedVars: []interface{}{float64(2)},
+			Result:       "(CAST(CASE WHEN `vip` THEN `price` END AS double precision))*?",
+		},
+		{
+			Expr:         field.Case().When(field.NewInt("", "age").Lt(18), 1).Else(0).Int().Sum(),
+			ExpectedVars: []interface{}{18, 1, 0},
+			Result:       "SUM(CAST(CASE WHEN `age` < ? THEN ? ELSE ? END AS integer))",
+		},
+		{
+			Expr:         field.Case().When(field.NewInt("", "age").Gte(18), true).Else(false).Bool().Is(true),
+			ExpectedVars: []interface{}{18, true, false, true},
+			Result:       "CAST(CASE WHEN `age` >= ? THEN ? ELSE ? END AS boolean) = ?",
+		},
+		{
+			Expr:         field.Coalesce(field.NewString("", "nickname"), field.NewString("", "name"), "anonymous").Eq("bob"),
+			ExpectedVars: []interface{}{"anonymous", "bob"},
+			Result:       "COALESCE(`nickname`,`name`,?) = ?",
+		},
+		{
+			Expr:         field.NullIf(field.NewInt("", "score"), 0),
+			ExpectedVars: []interface{}{0},
+			Result:       "NULLIF(`score`,?)",
+		},
+		{
+			Expr:         field.Greatest(field.NewInt("", "a"), field.NewInt("", "b"), 10),
+			ExpectedVars: []interface{}{10},
+			Result:       "GREATEST(`a`,`b`,?)",
+		},
+		{
+			Expr:   field.Least(field.NewTime("", "created_at"), field.NewTime("", "updated_at")),
+			Result: "LEAST(`created_at`,`updated_at`)",
+		},
+		{
+			Expr:   field.NewString("", "amount").Cast("numeric(10, 2)"),
+			Result: "CAST(`amount` AS numeric(10, 2))",
+		},
+		{
+			Expr:   field.NewInt("", "id").Cast("double precision"),
+			Result: "CAST(`id` AS double precision)",
+		},
+		// ======================== grouping ========================
+		{
+			Expr:         field.Grouping(field.NewString("", "region"), field.NewString("", "city")).Eq(3),
+			ExpectedVars: []interface{}{3},
+			Result:       "GROUPING(`region`,`city`) = ?",
+		},
+		{
+			Expr:   field.GroupingSets([]field.Expr{field.NewString("", "region")}, nil),
+			Result: "GROUPING SETS ((`region`),())",
+		},
+		// ======================== aggregate ========================
+		{
+			Expr:   field.NewInt("", "id").ArrayAgg().OrderBy(field.NewTime("", "created_at").Desc()),
+			Result: "array_agg(`id` ORDER BY `created_at` DESC)",
+		},
+		{
+			Expr:         field.NewString("", "name").StringAgg(", ").OrderBy(field.NewString("", "name")).Filter(field.NewBool("", "active")).As("names"),
+			ExpectedVars: []interface{}{", "},
+			Result:       "string_agg(`name`,? ORDER BY `name`) FILTER (WHERE `active`) AS `names`",
+		},
+		{
+			Expr:   field.NewJSONB("", "attrs").JSONBAgg(),
+			Result: "jsonb_agg(`attrs`)",
+		},
+		{
+			Expr:   field.NewInt("", "id").JSONAgg(),
+			Result: "json_agg(`id`)",
+		},
+		{
+			Expr:   field.JSONBObjectAgg(field.NewString("", "key"), field.NewInt("", "value")),
+			Result: "jsonb_object_agg(`key`,`value`)",
+		},
+		{
+			Expr:   field.NewBool("", "paid").BoolAnd(),
+			Result: "bool_and(`paid`)",
+		},
+		{
+			Expr:         field.NewInt("", "id").Count().Filter(field.NewInt("", "age").Gt(18), field.NewBool("", "vip")),
+			ExpectedVars: []interface{}{18},
+			Result:       "COUNT(`id`) FILTER (WHERE `age` > ? AND `vip`)",
+		},
+		{
+			Expr:   field.NewInt("", "id").Count().Filter(field.NewBool("", "vip")).Over(field.PartitionBy(field.NewInt("", "dept_id"))),
+			Result: "COUNT(`id`) FILTER (WHERE `vip`) OVER (PARTITION BY `dept_id`)",
+		},
+		{
+			Expr:         field.NewFloat64("", "amount").Sum().Filter(field.NewString("", "status").Eq("paid")).Gt(100),
+			ExpectedVars: []interface{}{"paid", float64(100)},
+			Result:       "SUM(`amount`) FILTER (WHERE `status` = ?) > ?",
+		},
+		{
+			Expr:         field.PercentileCont(0.5, field.NewFloat64("", "salary")).As("median"),
+			ExpectedVars: []interface{}{0.5},
+			Result:       "percentile_cont(?) WITHIN GROUP (ORDER BY `salary`) AS `median`",
+		},
+		{
+			Expr:         field.PercentileDisc(0.9, field.NewInt("", "latency")).OrderBy(field.NewInt("", "latency").Desc()),
+			ExpectedVars: []interface{}{0.9},
+			Result:       "percentile_disc(?) WITHIN GROUP (ORDER BY `latency` DESC)",
+		},
+		{
+			Expr:         field.Mode(field.NewString("", "city")).Filter(field.NewInt("", "age").Lt(30)),
+			ExpectedVars: []interface{}{30},
+			Result:       "mode() WITHIN GROUP (ORDER BY `city`) FILTER (WHERE `age` < ?)",
+		},
 	}
 
 	for _, testcase := range testcases {
@@ -473,6 +852,41 @@ func TestExpr_Build(t *testing.T) {
 	}
 }
 
+func TestExpr_CondError(t *testing.T) {
+	for _, typ := range []string{"numeric; DROP TABLE users", "text)", "int --", ""} {
+		if err := field.NewInt("", "id").Cast(typ).CondError(); err == nil {
+			t.Errorf("expect error of cast to %q", typ)
+		}
+	}
+	if err := field.NewString("", "name").Cast("text[]").CondError(); err != nil {
+		t.Errorf("unexpected error of cast: %s", err)
+	}
+	if err := field.NewInt("", "id").Add(1).OrderBy(field.NewInt("", "id")).CondError(); err == nil {
+		t.Errorf("expect error of ORDER BY in non-aggregate expression")
+	}
+	if err := field.NewInt("", "id").Add(1).Filter(field.NewBool("", "vip")).CondError(); err == nil {
+		t.Errorf("expect error of FILTER in non-aggregate expression")
+	}
+	if err := field.NewInt("", "id").Count().Over(field.PartitionBy(field.NewInt("", "dept_id"))).Filter(field.NewBool("", "vip")).CondError(); err == nil {
+		t.Errorf("expect error of FILTER after OVER")
+	}
+	if err := field.RowNumber().Over(field.OrderBy(field.NewInt("", "id")).Rows(field.Preceding(-1), field.CurrentRow)).CondError(); err == nil {
+		t.Errorf("expect error of negative frame offset")
+	}
+	if err := field.Case().Else(1).Int().CondError(); err == nil {
+		t.Errorf("expect error of CASE without WHEN")
+	}
+	if err := field.NewTime("", "created_at").DateTrunc("fortnight").CondError(); err == nil {
+		t.Errorf("expect error of invalid DATE_TRUNC unit")
+	}
+	if err := field.NewDate("", "due_on").DatePart("weekday").CondError(); err == nil {
+		t.Errorf("expect error of invalid DATE_PART part")
+	}
+	if err := field.NewTime("", "created_at").Extract("year FROM now()) --").CondError(); err == nil {
+		t.Errorf("expect error of invalid EXTRACT part")
+	}
+}
+
 func TestExpr_BuildColumn(t *testing.T) {
 	stmt := field.GetStatement()
 	id := field.NewUint("user", "id")
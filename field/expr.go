@@ -7,6 +7,8 @@ import (
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/types"
 )
 
 var _ Expr = new(Field)
@@ -66,10 +68,12 @@ type expr struct {
 
 	e         clause.Expression
 	buildOpts []BuildOpt
+	agg       *aggregate
+	err       error
 }
 
 func (e expr) BeCond() interface{} { return e.expression() }
-func (expr) CondError() error      { return nil }
+func (e expr) CondError() error    { return e.err }
 
 func (e expr) AssignExpr() expression {
 	return e.expression()
@@ -149,6 +153,7 @@ func (e expr) RawExpr() expression {
 
 func (e expr) setE(expression clause.Expression) expr {
 	e.e = expression
+	e.agg = nil
 	return e
 }
 
@@ -172,7 +177,7 @@ func (e expr) IsNotNull() Expr {
 }
 
 func (e expr) Count() Int {
-	return Int{e.setE(clause.Expr{SQL: "COUNT(?)", Vars: []interface{}{e.RawExpr()}})}
+	return Int{e.aggregate("COUNT", e.RawExpr())}
 }
 
 func (e expr) Distinct() Int {
@@ -184,15 +189,15 @@ func (e expr) Length() Int {
 }
 
 func (e expr) Max() Float64 {
-	return Float64{e.setE(clause.Expr{SQL: "MAX(?)", Vars: []interface{}{e.RawExpr()}})}
+	return Float64{e.aggregate("MAX", e.RawExpr())}
 }
 
 func (e expr) Min() Float64 {
-	return Float64{e.setE(clause.Expr{SQL: "MIN(?)", Vars: []interface{}{e.RawExpr()}})}
+	return Float64{e.aggregate("MIN", e.RawExpr())}
 }
 
 func (e expr) Avg() Float64 {
-	return Float64{e.setE(clause.Expr{SQL: "AVG(?)", Vars: []interface{}{e.RawExpr()}})}
+	return Float64{e.aggregate("AVG", e.RawExpr())}
 }
 
 func (e expr) Abs() Float64 {
@@ -203,8 +208,9 @@ func (e expr) Null() AssignExpr {
 	return e.setE(clause.Eq{Column: e.col.Name, Value: nil})
 }
 
+// GroupConcat string_agg(expr, ','), values concatenated by comma, use StringAgg for other separators
 func (e expr) GroupConcat() Expr {
-	return e.setE(clause.Expr{SQL: "GROUP_CONCAT(?)", Vars: []interface{}{e.RawExpr()}})
+	return e.setE(clause.Expr{SQL: "string_agg(?, ',')", Vars: []interface{}{e.RawExpr()}})
 }
 
 // ======================== comparison between columns ========================
@@ -297,7 +303,9 @@ func (e expr) between(values []interface{}) expr {
 func (e expr) add(value interface{}) expr {
 	switch v := value.(type) {
 	case time.Duration:
-		return e.setE(clause.Expr{SQL: "DATE_ADD(?, INTERVAL ? MICROSECOND)", Vars: []interface{}{e.RawExpr(), v.Microseconds()}})
+		return e.add(types.IntervalOf(v))
+	case types.Interval:
+		return e.setE(clause.Expr{SQL: "?+?::interval", Vars: []interface{}{e.RawExpr(), v}})
 	default:
 		return e.setE(clause.Expr{SQL: "?+?", Vars: []interface{}{e.RawExpr(), value}})
 	}
@@ -306,7 +314,9 @@ func (e expr) add(value interface{}) expr {
 func (e expr) sub(value interface{}) expr {
 	switch v := value.(type) {
 	case time.Duration:
-		return e.setE(clause.Expr{SQL: "DATE_SUB(?, INTERVAL ? MICROSECOND)", Vars: []interface{}{e.RawExpr(), v.Microseconds()}})
+		return e.sub(types.IntervalOf(v))
+	case types.Interval:
+		return e.setE(clause.Expr{SQL: "?-?::interval", Vars: []interface{}{e.RawExpr(), v}})
 	default:
 		return e.setE(clause.Expr{SQL: "?-?", Vars: []interface{}{e.RawExpr(), value}})
 	}
@@ -423,5 +433,5 @@ func (e expr) field(value interface{}) expr {
 }
 
 func (e expr) sum() expr {
-	return e.setE(clause.Expr{SQL: "SUM(?)", Vars: []interface{}{e.RawExpr()}})
+	return e.aggregate("SUM", e.RawExpr())
 }
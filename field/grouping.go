@@ -0,0 +1,43 @@
+package field
+
+import (
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// Rollup ROLLUP (columns), grouping sets of every prefix of columns, used in Group
+func Rollup(columns ...Expr) Expr { return Field{groupingElement("ROLLUP (", columns)} }
+
+// Cube CUBE (columns), grouping sets of every subset of columns, used in Group
+func Cube(columns ...Expr) Expr { return Field{groupingElement("CUBE (", columns)} }
+
+// GroupingSets GROUPING SETS ((set1), (set2), ...), nil or empty set is the grand total ()
+func GroupingSets(sets ...[]Expr) Expr {
+	sqls := make([]string, len(sets))
+	var vars []interface{}
+	for i, set := range sets {
+		sqls[i] = "(" + placeholderList(len(set)) + ")"
+		for _, column := range set {
+			vars = append(vars, column.RawExpr())
+		}
+	}
+	return Field{expr{e: clause.Expr{SQL: "GROUPING SETS (" + strings.Join(sqls, ",") + ")", Vars: vars}}}
+}
+
+// Grouping GROUPING(columns), bit mask of columns not included in grouping set of current row,
+// used to tell subtotal rows apart
+func Grouping(columns ...Expr) Int { return Int{groupingElement("GROUPING(", columns)} }
+
+// groupingElement prefix(columns), prefix contains opening parenthesis
+func groupingElement(prefix string, columns []Expr) expr {
+	vars := make([]interface{}, len(columns))
+	for i, column := range columns {
+		vars[i] = column.RawExpr()
+	}
+	return expr{e: clause.Expr{SQL: prefix + placeholderList(len(columns)) + ")", Vars: vars}}
+}
+
+func placeholderList(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,109 @@
+package field
+
+import (
+	"fmt"
+	"strings"
+
+	"go.ipao.vip/gen/types"
+	"gorm.io/gorm/clause"
+)
+
+// HStore represents a PostgreSQL hstore field.
+// Key existence is built with the exist/exists_any/exists_all functions instead of the ?, ?| and ?& operators,
+// whose question marks would be taken as placeholders.
+type HStore Field
+
+func NewHStore(table, column string, opts ...Option) HStore {
+	return HStore{expr: expr{col: toColumn(table, column, opts...)}}
+}
+
+// Eq equal to
+func (f HStore) Eq(v types.HStore) Expr { return expr{e: clause.Eq{Column: f.RawExpr(), Value: v}} }
+
+// Neq not equal to
+func (f HStore) Neq(v types.HStore) Expr { return expr{e: clause.Neq{Column: f.RawExpr(), Value: v}} }
+
+// Get value of key, equal to "self -> key"
+func (f HStore) Get(key string) String {
+	return String{expr{e: clause.Expr{SQL: "?->?::text", Vars: []interface{}{f.RawExpr(), key}}}}
+}
+
+// HasKey key exists, equal to "self ? key"
+func (f HStore) HasKey(key string) Expr {
+	return expr{e: clause.Expr{SQL: "exist(?,?)", Vars: []interface{}{f.RawExpr(), key}}}
+}
+
+// HasAnyKeys any of keys exists, equal to "self ?| ARRAY[keys]"
+func (f HStore) HasAnyKeys(keys ...string) Expr {
+	if len(keys) == 0 {
+		return expr{e: clause.Expr{SQL: "1=0"}}
+	}
+	return expr{e: f.keysExpr("exists_any(?,%s)", keys)}
+}
+
+// HasAllKeys all keys exist, equal to "self ?& ARRAY[keys]"
+func (f HStore) HasAllKeys(keys ...string) Expr {
+	if len(keys) == 0 {
+		return expr{e: clause.Expr{SQL: "1=1"}}
+	}
+	return expr{e: f.keysExpr("exists_all(?,%s)", keys)}
+}
+
+// Defined key exists with non-NULL value, equal to defined(self, key)
+func (f HStore) Defined(key string) Expr {
+	return expr{e: clause.Expr{SQL: "defined(?,?)", Vars: []interface{}{f.RawExpr(), key}}}
+}
+
+// Contains uses @> (left contains right)
+func (f HStore) Contains(v types.HStore) Expr {
+	return expr{e: clause.Expr{SQL: "? @> ?", Vars: []interface{}{f.RawExpr(), v}}}
+}
+
+// ContainedBy uses <@ (left is contained by right)
+func (f HStore) ContainedBy(v types.HStore) Expr {
+	return expr{e: clause.Expr{SQL: "? <@ ?", Vars: []interface{}{f.RawExpr(), v}}}
+}
+
+// Keys keys as text array, equal to akeys(self)
+func (f HStore) Keys() Array {
+	return Array{expr{e: clause.Expr{SQL: "akeys(?)", Vars: []interface{}{f.RawExpr()}}}}
+}
+
+// Values values as text array, equal to avals(self)
+func (f HStore) Values() Array {
+	return Array{expr{e: clause.Expr{SQL: "avals(?)", Vars: []interface{}{f.RawExpr()}}}}
+}
+
+// Value set value
+func (f HStore) Value(v types.HStore) AssignExpr { return f.value(v) }
+
+// Zero set empty hstore
+func (f HStore) Zero() AssignExpr { return f.value(types.HStore{}) }
+
+// Merge set self = self || v, keys of v overwrite existing keys
+func (f HStore) Merge(v types.HStore) AssignExpr {
+	return f.value(clause.Expr{SQL: "?||?", Vars: []interface{}{f.RawExpr(), v}})
+}
+
+// DeleteKey set self = delete(self, keys), remove keys from hstore
+func (f HStore) DeleteKey(keys ...string) AssignExpr {
+	switch len(keys) {
+	case 0:
+		return f.value(f.RawExpr())
+	case 1:
+		return f.value(clause.Expr{SQL: "delete(?,?::text)", Vars: []interface{}{f.RawExpr(), keys[0]}})
+	default:
+		return f.value(f.keysExpr("delete(?,%s)", keys))
+	}
+}
+
+// keysExpr format ARRAY[?,...] of keys into sql after the column placeholder
+func (f HStore) keysExpr(sql string, keys []string) clause.Expr {
+	vars := make([]interface{}, 0, 1+len(keys))
+	vars = append(vars, f.RawExpr())
+	for _, k := range keys {
+		vars = append(vars, k)
+	}
+	array := "ARRAY[?" + strings.Repeat(",?", len(keys)-1) + "]::text[]"
+	return clause.Expr{SQL: fmt.Sprintf(sql, array), Vars: vars}
+}
@@ -0,0 +1,87 @@
+package field
+
+import (
+	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/types"
+)
+
+// Interval represents a PostgreSQL interval field
+type Interval Field
+
+// NewInterval create new Interval
+func NewInterval(table, column string, opts ...Option) Interval {
+	return Interval{expr: expr{col: toColumn(table, column, opts...)}}
+}
+
+// Eq equal to
+func (f Interval) Eq(v types.Interval) Expr { return expr{e: clause.Eq{Column: f.RawExpr(), Value: v}} }
+
+// Neq not equal to
+func (f Interval) Neq(v types.Interval) Expr {
+	return expr{e: clause.Neq{Column: f.RawExpr(), Value: v}}
+}
+
+// Gt greater than
+func (f Interval) Gt(v types.Interval) Expr { return expr{e: clause.Gt{Column: f.RawExpr(), Value: v}} }
+
+// Gte greater or equal to
+func (f Interval) Gte(v types.Interval) Expr {
+	return expr{e: clause.Gte{Column: f.RawExpr(), Value: v}}
+}
+
+// Lt less than
+func (f Interval) Lt(v types.Interval) Expr { return expr{e: clause.Lt{Column: f.RawExpr(), Value: v}} }
+
+// Lte less or equal to
+func (f Interval) Lte(v types.Interval) Expr {
+	return expr{e: clause.Lte{Column: f.RawExpr(), Value: v}}
+}
+
+// Between inclusive range
+func (f Interval) Between(left, right types.Interval) Expr {
+	return f.between([]interface{}{left, right})
+}
+
+// NotBetween negated range
+func (f Interval) NotBetween(left, right types.Interval) Expr {
+	return Not(f.Between(left, right))
+}
+
+// Add f + v
+func (f Interval) Add(v types.Interval) Interval { return Interval{f.add(v)} }
+
+// Sub f - v
+func (f Interval) Sub(v types.Interval) Interval { return Interval{f.sub(v)} }
+
+// Mul f * factor, e.g. interval '1 hour' * 1.5
+func (f Interval) Mul(factor float64) Interval { return Interval{f.mul(factor)} }
+
+// Extract equal to EXTRACT(part FROM self), e.g. Extract("epoch") for total seconds
+func (f Interval) Extract(part string) Float64 { return Time(f).Extract(part) }
+
+// JustifyInterval equal to JUSTIFY_INTERVAL(self), 24 hours as a day and 30 days as a month
+func (f Interval) JustifyInterval() Interval {
+	return Interval{f.setE(clause.Expr{SQL: "JUSTIFY_INTERVAL(?)", Vars: []interface{}{f.RawExpr()}})}
+}
+
+// Sum SUM(f), interval result
+func (f Interval) Sum() Interval { return Interval{f.sum()} }
+
+// Avg AVG(f), interval result
+func (f Interval) Avg() Interval { return Interval{f.aggregate("AVG", f.RawExpr())} }
+
+// Max MAX(f)
+func (f Interval) Max() Interval { return Interval{f.aggregate("MAX", f.RawExpr())} }
+
+// Min MIN(f)
+func (f Interval) Min() Interval { return Interval{f.aggregate("MIN", f.RawExpr())} }
+
+// Filter FILTER (WHERE conds) of aggregate
+func (f Interval) Filter(conds ...Expr) Interval { return Interval{f.filter(conds)} }
+
+// Value set value
+func (f Interval) Value(v types.Interval) AssignExpr { return f.value(v) }
+
+// Zero set zero value
+func (f Interval) Zero() AssignExpr { return f.value(types.Interval{}) }
@@ -0,0 +1,97 @@
+package field
+
+import (
+	"strings"
+
+	"go.ipao.vip/gen/types"
+	"gorm.io/gorm/clause"
+)
+
+// LTree represents a PostgreSQL ltree field
+type LTree Field
+
+func NewLTree(table, column string, opts ...Option) LTree {
+	return LTree{expr: expr{col: toColumn(table, column, opts...)}}
+}
+
+// Eq equal to
+func (f LTree) Eq(v types.LTree) Expr { return expr{e: clause.Eq{Column: f.RawExpr(), Value: v}} }
+
+// Neq not equal to
+func (f LTree) Neq(v types.LTree) Expr { return expr{e: clause.Neq{Column: f.RawExpr(), Value: v}} }
+
+// Gt greater than, ltree sorts by label path
+func (f LTree) Gt(v types.LTree) Expr { return expr{e: clause.Gt{Column: f.RawExpr(), Value: v}} }
+
+// Lt less than, ltree sorts by label path
+func (f LTree) Lt(v types.LTree) Expr { return expr{e: clause.Lt{Column: f.RawExpr(), Value: v}} }
+
+// In set membership
+func (f LTree) In(values ...types.LTree) Expr {
+	slice := make([]interface{}, len(values))
+	for i, v := range values {
+		slice[i] = v
+	}
+	return expr{e: clause.IN{Column: f.RawExpr(), Values: slice}}
+}
+
+// IsAncestorOf self is ancestor of v or equal, equal to "self @> v"
+func (f LTree) IsAncestorOf(v types.LTree) Expr {
+	return expr{e: clause.Expr{SQL: "? @> ?", Vars: []interface{}{f.RawExpr(), v}}}
+}
+
+// IsDescendantOf self is descendant of v or equal, equal to "self <@ v"
+func (f LTree) IsDescendantOf(v types.LTree) Expr {
+	return expr{e: clause.Expr{SQL: "? <@ ?", Vars: []interface{}{f.RawExpr(), v}}}
+}
+
+// Match matches lquery, equal to "self ~ lquery", e.g. Match("*.Science.*")
+func (f LTree) Match(lquery string) Expr {
+	return expr{e: clause.Expr{SQL: "? ~ ?::lquery", Vars: []interface{}{f.RawExpr(), lquery}}}
+}
+
+// MatchAny matches any of lqueries, equal to "self ? ARRAY[lqueries]",
+// built with lt_q_regex as the question mark would be taken as placeholder
+func (f LTree) MatchAny(lqueries ...string) Expr {
+	if len(lqueries) == 0 {
+		return expr{e: clause.Expr{SQL: "1=0"}}
+	}
+	vars := make([]interface{}, 0, 1+len(lqueries))
+	vars = append(vars, f.RawExpr())
+	for _, q := range lqueries {
+		vars = append(vars, q)
+	}
+	sql := "lt_q_regex(?,ARRAY[?" + strings.Repeat(",?", len(lqueries)-1) + "]::lquery[])"
+	return expr{e: clause.Expr{SQL: sql, Vars: vars}}
+}
+
+// MatchText matches full text ltxtquery, equal to "self @ ltxtquery", e.g. MatchText("Europe & Russia*@")
+func (f LTree) MatchText(ltxtquery string) Expr {
+	return expr{e: clause.Expr{SQL: "? @ ?::ltxtquery", Vars: []interface{}{f.RawExpr(), ltxtquery}}}
+}
+
+// NLevel number of labels, equal to nlevel(self)
+func (f LTree) NLevel() Int {
+	return Int{expr{e: clause.Expr{SQL: "nlevel(?)", Vars: []interface{}{f.RawExpr()}}}}
+}
+
+// Subpath labels from offset with length, equal to subpath(self, offset, length)
+func (f LTree) Subpath(offset, length int) LTree {
+	return LTree{f.setE(clause.Expr{SQL: "subpath(?,?,?)", Vars: []interface{}{f.RawExpr(), offset, length}})}
+}
+
+// SubpathFrom labels from offset to the end, equal to subpath(self, offset)
+func (f LTree) SubpathFrom(offset int) LTree {
+	return LTree{f.setE(clause.Expr{SQL: "subpath(?,?)", Vars: []interface{}{f.RawExpr(), offset}})}
+}
+
+// Concat self || v
+func (f LTree) Concat(v types.LTree) LTree {
+	return LTree{f.setE(clause.Expr{SQL: "?||?::ltree", Vars: []interface{}{f.RawExpr(), v}})}
+}
+
+// Value set value
+func (f LTree) Value(v types.LTree) AssignExpr { return f.value(v) }
+
+// Zero set empty path
+func (f LTree) Zero() AssignExpr { return f.value(types.LTree("")) }
@@ -53,6 +53,36 @@ func (f Money) Like(v types.Money) Expr { return expr{e: clause.Like{Column: f.R
 // NotLike negated LIKE on textual form
 func (f Money) NotLike(v types.Money) Expr { return expr{e: clause.Not(f.Like(v).expression())} }
 
+// Add f + v
+func (f Money) Add(v types.Money) Money { return Money{f.add(v)} }
+
+// Sub f - v
+func (f Money) Sub(v types.Money) Money { return Money{f.sub(v)} }
+
+// Mul f * factor, e.g. unit price * quantity
+func (f Money) Mul(factor int64) Money { return Money{f.mul(factor)} }
+
+// Div f / divisor, truncated to minor units
+func (f Money) Div(divisor int64) Money { return Money{f.div(divisor)} }
+
+// Sum SUM(f), money result
+func (f Money) Sum() Money { return Money{f.sum()} }
+
+// Max MAX(f)
+func (f Money) Max() Money { return Money{f.aggregate("MAX", f.RawExpr())} }
+
+// Min MIN(f)
+func (f Money) Min() Money { return Money{f.aggregate("MIN", f.RawExpr())} }
+
+// Filter FILTER (WHERE conds) of aggregate, e.g. o.Total.Sum().Filter(o.Status.Eq("paid"))
+func (f Money) Filter(conds ...Expr) Money { return Money{f.filter(conds)} }
+
+// Value set value
+func (f Money) Value(v types.Money) AssignExpr { return f.value(v) }
+
+// Zero set zero value
+func (f Money) Zero() AssignExpr { return f.value(types.Money{}) }
+
 func (f Money) toSlice(values ...types.Money) []interface{} {
 	slice := make([]interface{}, len(values))
 	for i, v := range values {
@@ -12,6 +12,8 @@ const (
 	// gorm tag
 	TagKeyGormColumn        = "column"
 	TagKeyGormType          = "type"
+	TagKeyGormPrecision     = "precision"
+	TagKeyGormScale         = "scale"
 	TagKeyGormPrimaryKey    = "primaryKey"
 	TagKeyGormAutoIncrement = "autoIncrement"
 	TagKeyGormNotNull       = "not null"
@@ -25,8 +27,10 @@ var tagKeyPriorities = map[string]int16{
 	TagKeyGorm: 100,
 	TagKeyJson: 99,
 
-	TagKeyGormColumn:        10,
-	TagKeyGormType:          9,
+	TagKeyGormColumn:        12,
+	TagKeyGormType:          11,
+	TagKeyGormPrecision:     10,
+	TagKeyGormScale:         9,
 	TagKeyGormPrimaryKey:    8,
 	TagKeyGormAutoIncrement: 7,
 	TagKeyGormNotNull:       6,
@@ -2,9 +2,12 @@ package field
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/types"
 )
 
 // Time time type field
@@ -60,13 +63,23 @@ func (field Time) NotIn(values ...time.Time) Expr {
 	return expr{e: clause.Not(field.In(values...).expression())}
 }
 
-// Add ...
-func (field Time) Add(value time.Duration) Time {
+// Add time + interval, e.g. o.CreatedAt.Add(types.NewInterval(1, 0, 0)) for one month later
+func (field Time) Add(value types.Interval) Time {
 	return Time{field.add(value)}
 }
 
-// Sub ...
-func (field Time) Sub(value time.Duration) Time {
+// Sub time - interval
+func (field Time) Sub(value types.Interval) Time {
+	return Time{field.sub(value)}
+}
+
+// AddDuration time + duration, e.g. o.CreatedAt.AddDuration(time.Hour), Add of time.Duration before Interval
+func (field Time) AddDuration(value time.Duration) Time {
+	return Time{field.add(value)}
+}
+
+// SubDuration time - duration
+func (field Time) SubDuration(value time.Duration) Time {
 	return Time{field.sub(value)}
 }
 
@@ -75,14 +88,60 @@ func (field Time) Date() Time {
 	return Time{expr{e: clause.Expr{SQL: "DATE(?)", Vars: []interface{}{field.RawExpr()}}}}
 }
 
-// DateDiff equal to DATADIFF(self, value)
+// DateDiff days between dates of self and value, equal to "self::date - value::date"
 func (field Time) DateDiff(value time.Time) Int {
-	return Int{expr{e: clause.Expr{SQL: "DATEDIFF(?,?)", Vars: []interface{}{field.RawExpr(), value}}}}
+	return Int{expr{e: clause.Expr{SQL: "?::date-?::date", Vars: []interface{}{field.RawExpr(), value}}}}
+}
+
+// DateTrunc equal to DATE_TRUNC(unit, self), unit is microseconds, milliseconds, second, minute, hour,
+// day, week, month, quarter, year, decade, century or millennium
+//
+//	o.CreatedAt.DateTrunc("month").As("month")
+func (field Time) DateTrunc(unit string) Time {
+	e := field.setE(clause.Expr{SQL: "DATE_TRUNC(?,?)", Vars: []interface{}{unit, field.RawExpr()}})
+	if !truncUnits[strings.ToLower(unit)] {
+		e.err = fmt.Errorf("invalid DATE_TRUNC unit %q", unit)
+	}
+	return Time{e}
+}
+
+// Extract equal to EXTRACT(part FROM self), part is a DATE_TRUNC unit or epoch, dow, isodow, doy,
+// isoyear, julian, timezone, timezone_hour, timezone_minute
+func (field Time) Extract(part string) Float64 {
+	part = strings.ToLower(part)
+	if !isDatePart(part) {
+		e := field.expr
+		e.err = fmt.Errorf("invalid EXTRACT part %q", part)
+		return Float64{e}
+	}
+	return Float64{field.setE(clause.Expr{SQL: "EXTRACT(" + strings.ToUpper(part) + " FROM ?)", Vars: []interface{}{field.RawExpr()}})}
+}
+
+// AtTimeZone equal to "self AT TIME ZONE tz", timestamptz is converted to local time of tz and vice versa
+func (field Time) AtTimeZone(tz string) Time {
+	return Time{field.setE(clause.Expr{SQL: "? AT TIME ZONE ?", Vars: []interface{}{field.RawExpr(), tz}})}
+}
+
+// ToChar equal to TO_CHAR(self, format), e.g. ToChar("YYYY-MM-DD HH24:MI")
+func (field Time) ToChar(format string) String {
+	return String{expr{e: clause.Expr{SQL: "TO_CHAR(?,?)", Vars: []interface{}{field.RawExpr(), format}}}}
 }
 
-// DateFormat equal to DATE_FORMAT(self, value)
-func (field Time) DateFormat(value string) String {
-	return String{expr{e: clause.Expr{SQL: "DATE_FORMAT(?,?)", Vars: []interface{}{field.RawExpr(), value}}}}
+// Age equal to AGE(self, other), symbolic interval of years, months and days,
+// other is column expression or time value
+func (field Time) Age(other interface{}) Interval {
+	return Interval{expr{e: clause.Expr{SQL: "AGE(?,?)", Vars: []interface{}{field.RawExpr(), exprValue(other)}}}}
+}
+
+// Overlaps equal to "(self, end) OVERLAPS (otherStart, otherEnd)", true when two periods overlap,
+// arguments are column expressions or time values
+//
+//	b.StartAt.Overlaps(b.EndAt, from, to)
+func (field Time) Overlaps(end, otherStart, otherEnd interface{}) Expr {
+	return expr{e: clause.Expr{
+		SQL:  "(?,?) OVERLAPS (?,?)",
+		Vars: []interface{}{field.RawExpr(), exprValue(end), exprValue(otherStart), exprValue(otherEnd)},
+	}}
 }
 
 // Now return result of NOW()
@@ -90,85 +149,85 @@ func (field Time) Now() Time {
 	return Time{expr{e: clause.Expr{SQL: "NOW()"}}}
 }
 
-// CurDate return result of CURDATE()
+// CurDate return result of CURRENT_DATE
 func (field Time) CurDate() Time {
-	return Time{expr{e: clause.Expr{SQL: "CURDATE()"}}}
+	return Time{expr{e: clause.Expr{SQL: "CURRENT_DATE"}}}
 }
 
-// CurTime return result of CURTIME()
+// CurTime return result of CURRENT_TIME
 func (field Time) CurTime() Time {
-	return Time{expr{e: clause.Expr{SQL: "CURTIME()"}}}
+	return Time{expr{e: clause.Expr{SQL: "CURRENT_TIME"}}}
 }
 
-// DayName equal to DAYNAME(self)
+// DayName name of weekday, e.g. Monday
 func (field Time) DayName() String {
-	return String{expr{e: clause.Expr{SQL: "DAYNAME(?)", Vars: []interface{}{field.RawExpr()}}}}
+	return String{expr{e: clause.Expr{SQL: "TO_CHAR(?,'FMDay')", Vars: []interface{}{field.RawExpr()}}}}
 }
 
-// MonthName equal to MONTHNAME(self)
+// MonthName name of month, e.g. January
 func (field Time) MonthName() String {
-	return String{expr{e: clause.Expr{SQL: "MONTHNAME(?)", Vars: []interface{}{field.RawExpr()}}}}
+	return String{expr{e: clause.Expr{SQL: "TO_CHAR(?,'FMMonth')", Vars: []interface{}{field.RawExpr()}}}}
 }
 
-func (field Time) Year() Int {
-	return Int{expr{e: clause.Expr{SQL: "YEAR(?)", Vars: []interface{}{field.RawExpr()}}}}
-}
+// Year equal to EXTRACT(YEAR FROM self)
+func (field Time) Year() Int { return field.extractInt("YEAR") }
 
-// Month equal to MONTH(self)
-func (field Time) Month() Int {
-	return Int{expr{e: clause.Expr{SQL: "MONTH(?)", Vars: []interface{}{field.RawExpr()}}}}
-}
+// Month equal to EXTRACT(MONTH FROM self)
+func (field Time) Month() Int { return field.extractInt("MONTH") }
 
-// Day equal to DAY(self)
-func (field Time) Day() Int {
-	return Int{expr{e: clause.Expr{SQL: "DAY(?)", Vars: []interface{}{field.RawExpr()}}}}
-}
+// Day equal to EXTRACT(DAY FROM self)
+func (field Time) Day() Int { return field.extractInt("DAY") }
 
-// Hour equal to HOUR(self)
-func (field Time) Hour() Int {
-	return Int{expr{e: clause.Expr{SQL: "HOUR(?)", Vars: []interface{}{field.RawExpr()}}}}
-}
+// Hour equal to EXTRACT(HOUR FROM self)
+func (field Time) Hour() Int { return field.extractInt("HOUR") }
 
-// Minute equal to MINUTE(self)
-func (field Time) Minute() Int {
-	return Int{expr{e: clause.Expr{SQL: "MINUTE(?)", Vars: []interface{}{field.RawExpr()}}}}
-}
+// Minute equal to EXTRACT(MINUTE FROM self)
+func (field Time) Minute() Int { return field.extractInt("MINUTE") }
 
-// Second equal to SECOND(self)
+// Second whole seconds of self
 func (field Time) Second() Int {
-	return Int{expr{e: clause.Expr{SQL: "SECOND(?)", Vars: []interface{}{field.RawExpr()}}}}
+	return Int{expr{e: clause.Expr{SQL: "FLOOR(EXTRACT(SECOND FROM ?))::int", Vars: []interface{}{field.RawExpr()}}}}
 }
 
-// MicroSecond equal to MICROSECOND(self)
+// MicroSecond microseconds part of self
 func (field Time) MicroSecond() Int {
-	return Int{expr{e: clause.Expr{SQL: "MICROSECOND(?)", Vars: []interface{}{field.RawExpr()}}}}
+	return Int{expr{e: clause.Expr{SQL: "EXTRACT(MICROSECONDS FROM ?)::int%1000000", Vars: []interface{}{field.RawExpr()}}}}
 }
 
-// DayOfWeek equal to DAYOFWEEK(self)
+// DayOfWeek day of week from 1 (Sunday) to 7 (Saturday), equal to EXTRACT(DOW FROM self)+1
 func (field Time) DayOfWeek() Int {
-	return Int{expr{e: clause.Expr{SQL: "DAYOFWEEK(?)", Vars: []interface{}{field.RawExpr()}}}}
+	return Int{expr{e: clause.Expr{SQL: "EXTRACT(DOW FROM ?)::int+1", Vars: []interface{}{field.RawExpr()}}}}
 }
 
-// DayOfMonth equal to DAYOFMONTH(self)
-func (field Time) DayOfMonth() Int {
-	return Int{expr{e: clause.Expr{SQL: "DAYOFMONTH(?)", Vars: []interface{}{field.RawExpr()}}}}
-}
+// DayOfMonth equal to EXTRACT(DAY FROM self)
+func (field Time) DayOfMonth() Int { return field.extractInt("DAY") }
 
-// DayOfYear equal to DAYOFYEAR(self)
-func (field Time) DayOfYear() Int {
-	return Int{expr{e: clause.Expr{SQL: "DAYOFYEAR(?)", Vars: []interface{}{field.RawExpr()}}}}
-}
+// DayOfYear equal to EXTRACT(DOY FROM self)
+func (field Time) DayOfYear() Int { return field.extractInt("DOY") }
 
-// FromDays equal to FROM_DAYS(self)
-func (field Time) FromDays(value int) Time {
-	return Time{expr{e: clause.Expr{SQL: fmt.Sprintf("FROM_DAYS(%d)", value)}}}
+// FromUnixtime equal to TO_TIMESTAMP(value)
+func (field Time) FromUnixtime(value int64) Time {
+	return Time{expr{e: clause.Expr{SQL: "TO_TIMESTAMP(?)", Vars: []interface{}{value}}}}
 }
 
-// FromUnixtime equal to FROM_UNIXTIME(self)
-func (field Time) FromUnixtime(value int64) Time {
-	return Time{expr{e: clause.Expr{SQL: fmt.Sprintf("FROM_UNIXTIME(%d)", value)}}}
+func (field Time) extractInt(part string) Int {
+	return Int{expr{e: clause.Expr{SQL: "EXTRACT(" + part + " FROM ?)::int", Vars: []interface{}{field.RawExpr()}}}}
 }
 
+// isDatePart valid part of EXTRACT and DATE_PART
+func isDatePart(part string) bool { return truncUnits[part] || extractParts[part] }
+
+var (
+	truncUnits = map[string]bool{
+		"microseconds": true, "milliseconds": true, "second": true, "minute": true, "hour": true, "day": true,
+		"week": true, "month": true, "quarter": true, "year": true, "decade": true, "century": true, "millennium": true,
+	}
+	extractParts = map[string]bool{
+		"epoch": true, "dow": true, "isodow": true, "doy": true, "isoyear": true, "julian": true,
+		"timezone": true, "timezone_hour": true, "timezone_minute": true,
+	}
+)
+
 // Value set value
 func (field Time) Value(value time.Time) AssignExpr {
 	return field.value(value)
@@ -0,0 +1,85 @@
+package field
+
+import (
+	"go.ipao.vip/gen/types"
+	"gorm.io/gorm/clause"
+)
+
+// TimeOfDay represents a PostgreSQL time field
+type TimeOfDay Field
+
+func NewTimeOfDay(table, column string, opts ...Option) TimeOfDay {
+	return TimeOfDay{expr: expr{col: toColumn(table, column, opts...)}}
+}
+
+// Eq equal to
+func (f TimeOfDay) Eq(v types.Time) Expr { return expr{e: clause.Eq{Column: f.RawExpr(), Value: v}} }
+
+// Neq not equal to
+func (f TimeOfDay) Neq(v types.Time) Expr { return expr{e: clause.Neq{Column: f.RawExpr(), Value: v}} }
+
+// Gt greater than
+func (f TimeOfDay) Gt(v types.Time) Expr { return expr{e: clause.Gt{Column: f.RawExpr(), Value: v}} }
+
+// Gte greater or equal to
+func (f TimeOfDay) Gte(v types.Time) Expr { return expr{e: clause.Gte{Column: f.RawExpr(), Value: v}} }
+
+// Lt less than
+func (f TimeOfDay) Lt(v types.Time) Expr { return expr{e: clause.Lt{Column: f.RawExpr(), Value: v}} }
+
+// Lte less or equal to
+func (f TimeOfDay) Lte(v types.Time) Expr { return expr{e: clause.Lte{Column: f.RawExpr(), Value: v}} }
+
+// In set membership
+func (f TimeOfDay) In(values ...types.Time) Expr {
+	return expr{e: clause.IN{Column: f.RawExpr(), Values: f.toSlice(values...)}}
+}
+
+// NotIn negated set membership
+func (f TimeOfDay) NotIn(values ...types.Time) Expr {
+	return expr{e: clause.Not(f.In(values...).expression())}
+}
+
+// Between inclusive range, e.g. opening hours
+func (f TimeOfDay) Between(left, right types.Time) Expr {
+	return f.between([]interface{}{left, right})
+}
+
+// NotBetween negated range
+func (f TimeOfDay) NotBetween(left, right types.Time) Expr {
+	return Not(f.Between(left, right))
+}
+
+// Add time + interval, wraps around midnight
+func (f TimeOfDay) Add(v types.Interval) TimeOfDay { return TimeOfDay{f.add(v)} }
+
+// Sub time - interval, wraps around midnight
+func (f TimeOfDay) Sub(v types.Interval) TimeOfDay { return TimeOfDay{f.sub(v)} }
+
+// DatePart equal to DATE_PART(part, self), part is hour, minute, second, epoch...
+func (f TimeOfDay) DatePart(part string) Float64 { return datePart(f.expr, part) }
+
+// On combine with date into timestamp, equal to "date + self"
+func (f TimeOfDay) On(date types.Date) Time {
+	return Time{expr{e: clause.Expr{SQL: "?::date+?", Vars: []interface{}{date, f.RawExpr()}}}}
+}
+
+// Max MAX(f)
+func (f TimeOfDay) Max() TimeOfDay { return TimeOfDay{f.aggregate("MAX", f.RawExpr())} }
+
+// Min MIN(f)
+func (f TimeOfDay) Min() TimeOfDay { return TimeOfDay{f.aggregate("MIN", f.RawExpr())} }
+
+// Value set value
+func (f TimeOfDay) Value(v types.Time) AssignExpr { return f.value(v) }
+
+// Zero set zero value
+func (f TimeOfDay) Zero() AssignExpr { return f.value(types.Time(0)) }
+
+func (f TimeOfDay) toSlice(values ...types.Time) []interface{} {
+	slice := make([]interface{}, len(values))
+	for i, v := range values {
+		slice[i] = v
+	}
+	return slice
+}
@@ -0,0 +1,240 @@
+package field
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// FrameBound start or end of window frame, created by UnboundedPreceding, CurrentRow,
+// UnboundedFollowing, Preceding and Following
+type FrameBound struct {
+	sql    string
+	offset interface{} // bound value of n PRECEDING and n FOLLOWING
+	err    error
+}
+
+var (
+	// UnboundedPreceding UNBOUNDED PRECEDING
+	UnboundedPreceding = FrameBound{sql: "UNBOUNDED PRECEDING"}
+	// CurrentRow CURRENT ROW
+	CurrentRow = FrameBound{sql: "CURRENT ROW"}
+	// UnboundedFollowing UNBOUNDED FOLLOWING
+	UnboundedFollowing = FrameBound{sql: "UNBOUNDED FOLLOWING"}
+)
+
+// Preceding n PRECEDING, n must not be negative
+func Preceding(n int) FrameBound { return offsetBound(n, "PRECEDING") }
+
+// Following n FOLLOWING, n must not be negative
+func Following(n int) FrameBound { return offsetBound(n, "FOLLOWING") }
+
+func offsetBound(n int, direction string) FrameBound {
+	if n < 0 {
+		return FrameBound{err: fmt.Errorf("frame offset of %s must not be negative, got %d", direction, n)}
+	}
+	return FrameBound{sql: "? " + direction, offset: n}
+}
+
+// Window window definition of OVER clause
+//
+//	field.RowNumber().Over(field.PartitionBy(u.DeptID).OrderBy(u.Salary.Desc())).As("rn")
+//	u.Salary.Sum().Over(field.OrderBy(u.ID).Rows(field.UnboundedPreceding, field.CurrentRow))
+//	field.Lag(u.Salary, 1).Over(field.OrderBy(u.ID)) // typed as u.Salary
+type Window struct {
+	partition []Expr
+	order     []Expr
+	frame     string
+	frameVars []interface{}
+	err       error
+}
+
+// PartitionBy new window partitioned by columns
+func PartitionBy(columns ...Expr) Window { return Window{}.PartitionBy(columns...) }
+
+// OrderBy new window ordered by columns
+func OrderBy(columns ...Expr) Window { return Window{}.OrderBy(columns...) }
+
+// PartitionBy PARTITION BY columns
+func (w Window) PartitionBy(columns ...Expr) Window {
+	w.partition = append(append([]Expr{}, w.partition...), columns...)
+	return w
+}
+
+// OrderBy ORDER BY columns, e.g. u.Age.Desc()
+func (w Window) OrderBy(columns ...Expr) Window {
+	w.order = append(append([]Expr{}, w.order...), columns...)
+	return w
+}
+
+// Rows ROWS BETWEEN start AND end
+func (w Window) Rows(start, end FrameBound) Window { return w.between("ROWS", start, end) }
+
+// Range RANGE BETWEEN start AND end
+func (w Window) Range(start, end FrameBound) Window { return w.between("RANGE", start, end) }
+
+// Groups GROUPS BETWEEN start AND end
+func (w Window) Groups(start, end FrameBound) Window { return w.between("GROUPS", start, end) }
+
+func (w Window) between(mode string, start, end FrameBound) Window {
+	w.frame, w.frameVars = mode+" BETWEEN "+start.sql+" AND "+end.sql, nil
+	for _, bound := range []FrameBound{start, end} {
+		if bound.err != nil && w.err == nil {
+			w.err = bound.err
+		}
+		if bound.offset != nil {
+			w.frameVars = append(w.frameVars, bound.offset)
+		}
+	}
+	return w
+}
+
+// build window definition, e.g. PARTITION BY ? ORDER BY ? DESC ROWS BETWEEN ...
+func (w Window) build() (sql string, vars []interface{}) {
+	var parts []string
+	list := func(keyword string, columns []Expr) {
+		if len(columns) == 0 {
+			return
+		}
+		placeholders := make([]string, len(columns))
+		for i, column := range columns {
+			placeholders[i] = "?"
+			vars = append(vars, column.RawExpr())
+		}
+		parts = append(parts, keyword+" "+strings.Join(placeholders, ","))
+	}
+	list("PARTITION BY", w.partition)
+	list("ORDER BY", w.order)
+	if w.frame != "" {
+		parts = append(parts, w.frame)
+		vars = append(vars, w.frameVars...)
+	}
+	return strings.Join(parts, " "), vars
+}
+
+func (e expr) over(w Window) expr {
+	if w.err != nil && e.err == nil {
+		e.err = w.err
+	}
+	sql, vars := w.build()
+	return e.setE(clause.Expr{SQL: "? OVER (" + sql + ")", Vars: append([]interface{}{e.RawExpr()}, vars...)})
+}
+
+// Over window function or aggregate over window, e.g. u.Age.Count().Over(window)
+func (field Int) Over(w Window) Int { return Int{field.over(w)} }
+
+// Over window function or aggregate over window, e.g. u.Score.Avg().Over(window)
+func (field Float64) Over(w Window) Float64 { return Float64{field.over(w)} }
+
+// Over window function over window, e.g. field.Lag(u.Score, 1).Over(window)
+func (field Field) Over(w Window) Field { return Field{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Int8) Over(w Window) Int8 { return Int8{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Int16) Over(w Window) Int16 { return Int16{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Int32) Over(w Window) Int32 { return Int32{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Int64) Over(w Window) Int64 { return Int64{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Uint) Over(w Window) Uint { return Uint{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Uint8) Over(w Window) Uint8 { return Uint8{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Uint16) Over(w Window) Uint16 { return Uint16{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Uint32) Over(w Window) Uint32 { return Uint32{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Uint64) Over(w Window) Uint64 { return Uint64{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Float32) Over(w Window) Float32 { return Float32{field.over(w)} }
+
+// Over window function or aggregate over window
+func (f Decimal) Over(w Window) Decimal { return Decimal{f.over(w)} }
+
+// Over window function or aggregate over window
+func (f Money) Over(w Window) Money { return Money{f.over(w)} }
+
+// Over window function or aggregate over window
+func (f Interval) Over(w Window) Interval { return Interval{f.over(w)} }
+
+// Over window function or aggregate over window
+func (field Time) Over(w Window) Time { return Time{field.over(w)} }
+
+// Over window function or aggregate over window
+func (f Date) Over(w Window) Date { return Date{f.over(w)} }
+
+// Over window function or aggregate over window
+func (f TimeOfDay) Over(w Window) TimeOfDay { return TimeOfDay{f.over(w)} }
+
+// Over window function or aggregate over window
+func (field String) Over(w Window) String { return String{field.over(w)} }
+
+// Over window function or aggregate over window
+func (field Bool) Over(w Window) Bool { return Bool{field.over(w)} }
+
+// Over window function or aggregate over window
+func (f Array) Over(w Window) Array { return Array{f.over(w)} }
+
+// Over window function or aggregate over window
+func (f JSON) Over(w Window) JSON { return JSON{f.over(w)} }
+
+// Over window function or aggregate over window
+func (f JSONB) Over(w Window) JSONB { return JSONB{f.over(w)} }
+
+// ======================== window functions ========================
+
+// RowNumber ROW_NUMBER(), number of current row in partition
+func RowNumber() Int { return Int{expr{e: clause.Expr{SQL: "ROW_NUMBER()"}}} }
+
+// Rank RANK(), rank of current row with gaps
+func Rank() Int { return Int{expr{e: clause.Expr{SQL: "RANK()"}}} }
+
+// DenseRank DENSE_RANK(), rank of current row without gaps
+func DenseRank() Int { return Int{expr{e: clause.Expr{SQL: "DENSE_RANK()"}}} }
+
+// PercentRank PERCENT_RANK(), relative rank of current row
+func PercentRank() Float64 { return Float64{expr{e: clause.Expr{SQL: "PERCENT_RANK()"}}} }
+
+// CumeDist CUME_DIST(), cumulative distribution of current row
+func CumeDist() Float64 { return Float64{expr{e: clause.Expr{SQL: "CUME_DIST()"}}} }
+
+// Ntile NTILE(n), bucket number of current row
+func Ntile(n int) Int { return Int{expr{e: clause.Expr{SQL: "NTILE(?)", Vars: []interface{}{n}}}} }
+
+// windowValue column wrapper typed result of value window functions, e.g. Float64 of field.Lag(u.Price, 1)
+type windowValue[T any] interface {
+	~struct{ expr }
+	Over(Window) T
+}
+
+// Lag LAG(col, n), value of column n rows before current row, typed as col
+func Lag[T windowValue[T]](col T, n int) T { return valueWindowFunc("LAG(?, ?)", col, n) }
+
+// Lead LEAD(col, n), value of column n rows after current row, typed as col
+func Lead[T windowValue[T]](col T, n int) T { return valueWindowFunc("LEAD(?, ?)", col, n) }
+
+// FirstValue FIRST_VALUE(col), value of column at first row of window frame, typed as col
+func FirstValue[T windowValue[T]](col T) T { return valueWindowFunc("FIRST_VALUE(?)", col) }
+
+// LastValue LAST_VALUE(col), value of column at last row of window frame, typed as col
+func LastValue[T windowValue[T]](col T) T { return valueWindowFunc("LAST_VALUE(?)", col) }
+
+// NthValue NTH_VALUE(col, n), value of column at nth row of window frame, typed as col
+func NthValue[T windowValue[T]](col T, n int) T { return valueWindowFunc("NTH_VALUE(?, ?)", col, n) }
+
+func valueWindowFunc[T windowValue[T]](sql string, col T, args ...interface{}) T {
+	vars := append([]interface{}{Field(col).RawExpr()}, args...)
+	return T(Field{expr{e: clause.Expr{SQL: sql, Vars: vars}}})
+}
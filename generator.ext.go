@@ -3,6 +3,7 @@ package gen
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	"gorm.io/gorm"
@@ -10,6 +11,8 @@ import (
 
 	"go.ipao.vip/gen/field"
 	"go.ipao.vip/gen/helper"
+	"go.ipao.vip/gen/internal/generate"
+	"go.ipao.vip/gen/internal/model"
 )
 
 func DefaultConfig() Config {
@@ -80,83 +83,266 @@ func (c *ConfigOptRelation) Config(db *gorm.DB) *field.RelateConfig {
 	return opt
 }
 
+// ConfigOpt yaml config of generator, unset fields keep the value of DefaultConfig
 type ConfigOpt struct {
-	Ignores     []string                                `yaml:"ignores"`
+	OutPath      string `yaml:"out_path"`
+	OutFile      string `yaml:"out_file"`
+	ModelPkgPath string `yaml:"model_pkg_path"`
+	WithUnitTest bool   `yaml:"with_unit_test"`
+
+	FieldNullable     *bool `yaml:"field_nullable"`
+	FieldCoverable    *bool `yaml:"field_coverable"`
+	FieldSignable     *bool `yaml:"field_signable"`
+	FieldWithIndexTag *bool `yaml:"field_with_index_tag"`
+	FieldWithTypeTag  *bool `yaml:"field_with_type_tag"`
+	FieldWithRelation *bool `yaml:"field_with_relation"`
+
+	Mode         []string `yaml:"mode"`          // default_query, without_context, query_interface
+	SchemaLayout string   `yaml:"schema_layout"` // prefix, sub_package
+	Schemas      []string `yaml:"schemas"`       // other schemas to generate, table name is qualified with schema
+
+	DataTypes      map[string]string `yaml:"data_types"`      // db type -> go type, extend default mapping
+	Ignores        []string          `yaml:"ignores"`         // table names to ignore
+	IgnorePatterns []string          `yaml:"ignore_patterns"` // regexp of table names to ignore
+	Renames        map[string]string `yaml:"renames"`         // table name -> model name
+
 	Imports     []string                                `yaml:"imports"`
 	FieldType   map[string]map[string]string            `yaml:"field_type"`
 	FieldRelate map[string]map[string]ConfigOptRelation `yaml:"field_relate"`
-}
 
-func GenerateWithDefault(db *gorm.DB, transformConfigFile string) {
-	g := NewGenerator(DefaultConfig())
-	g.UseDB(db)
+	ignorePatterns []*regexp.Regexp
+}
 
-	if transformConfigFile == "" {
-		g.GenerateModels(g.GenerateAllTable()...)
-		g.Execute()
-		return
+var (
+	configOptModes = map[string]GenerateMode{
+		"default_query":   WithDefaultQuery,
+		"without_context": WithoutContext,
+		"query_interface": WithQueryInterface,
+	}
+	configOptSchemaLayouts = map[string]SchemaLayout{
+		"":            SchemaPrefix,
+		"prefix":      SchemaPrefix,
+		"sub_package": SchemaSubPackage,
 	}
+)
 
-	conf, err := os.ReadFile(transformConfigFile)
+// LoadConfigOpt load yaml config file
+func LoadConfigOpt(file string) (*ConfigOpt, error) {
+	conf, err := os.ReadFile(file)
 	if err != nil {
-		panic(fmt.Errorf("read transform config file %q fail: %w", transformConfigFile, err))
+		return nil, fmt.Errorf("read transform config file %q fail: %w", file, err)
 	}
 
-	// yaml config parse to ConfigOpt
-	var cfgOpt ConfigOpt
-	if err := helper.UnmarshalYAML([]byte(conf), &cfgOpt); err != nil {
-		panic(fmt.Errorf("parse yaml config fail: %w", err))
+	var opt ConfigOpt
+	if err := helper.UnmarshalYAML(conf, &opt); err != nil {
+		return nil, fmt.Errorf("parse yaml config fail: %w", err)
 	}
+	return &opt, nil
+}
 
-	g.WithTableNameStrategy(func(tableName string) string {
-		if strings.HasPrefix(tableName, "_") {
-			return ""
+// Config generator config, based on DefaultConfig
+func (c *ConfigOpt) Config() (Config, error) {
+	cfg := DefaultConfig()
+	if c.OutPath != "" {
+		if cfg.ModelPkgPath == cfg.OutPath { // keep co-located
+			cfg.ModelPkgPath = c.OutPath
 		}
+		cfg.OutPath = c.OutPath
+	}
+	if c.OutFile != "" {
+		cfg.OutFile = c.OutFile
+	}
+	if c.ModelPkgPath != "" {
+		cfg.ModelPkgPath = c.ModelPkgPath
+	}
+	cfg.WithUnitTest = c.WithUnitTest
 
-		// ignores table
-		for _, ignore := range cfgOpt.Ignores {
-			if strings.EqualFold(ignore, tableName) {
-				return ""
+	for _, flag := range []struct {
+		value  *bool
+		target *bool
+	}{
+		{c.FieldNullable, &cfg.FieldNullable},
+		{c.FieldCoverable, &cfg.FieldCoverable},
+		{c.FieldSignable, &cfg.FieldSignable},
+		{c.FieldWithIndexTag, &cfg.FieldWithIndexTag},
+		{c.FieldWithTypeTag, &cfg.FieldWithTypeTag},
+		{c.FieldWithRelation, &cfg.FieldWithRelation},
+	} {
+		if flag.value != nil {
+			*flag.target = *flag.value
+		}
+	}
+
+	if len(c.Mode) > 0 {
+		cfg.Mode = 0
+		for _, name := range c.Mode {
+			mode, ok := configOptModes[name]
+			if !ok {
+				return cfg, fmt.Errorf("unknown mode %q", name)
 			}
+			cfg.Mode |= mode
+		}
+	}
+
+	layout, ok := configOptSchemaLayouts[c.SchemaLayout]
+	if !ok {
+		return cfg, fmt.Errorf("unknown schema layout %q", c.SchemaLayout)
+	}
+	cfg.SchemaLayout = layout
+
+	if len(c.DataTypes) > 0 {
+		dataTypeMap := model.DefaultDataTypeMap()
+		for dbType, goType := range c.DataTypes {
+			goType := goType
+			dataTypeMap[strings.ToLower(dbType)] = func(gorm.ColumnType) string { return goType }
 		}
+		cfg.WithDataTypeMap(dataTypeMap)
+	}
 
+	c.ignorePatterns = c.ignorePatterns[:0]
+	for _, pattern := range c.IgnorePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+		c.ignorePatterns = append(c.ignorePatterns, re)
+	}
+
+	cfg.WithImportPkgPath(c.Imports...)
+	cfg.WithTableNameStrategy(func(tableName string) string {
+		if c.Ignored(tableName) {
+			return ""
+		}
 		return tableName
 	})
+	return cfg, nil
+}
+
+// Ignored reports whether table is ignored, tables starting with "_" are always ignored
+func (c *ConfigOpt) Ignored(tableName string) bool {
+	if strings.HasPrefix(tableName, "_") {
+		return true
+	}
+	for _, ignore := range c.Ignores {
+		if strings.EqualFold(ignore, tableName) {
+			return true
+		}
+	}
+	for _, re := range c.ignorePatterns {
+		if re.MatchString(tableName) {
+			return true
+		}
+	}
+	return false
+}
 
-	g.WithImportPkgPath(cfgOpt.Imports...)
+// NewGenerator create generator with config and db, tables not renamed are named by NamingStrategy of db
+func (c *ConfigOpt) NewGenerator(db *gorm.DB) (*Generator, error) {
+	cfg, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
+	g := NewGenerator(cfg)
+	g.UseDB(db)
+	if len(c.Renames) > 0 {
+		g.WithModelNameStrategy(func(tableName string) string {
+			if name, ok := c.Renames[tableName]; ok {
+				return name
+			}
+			return g.db.Config.NamingStrategy.SchemaName(tableName)
+		})
+	}
+	return g, nil
+}
 
+// ListTables tables to generate, tables of Schemas are qualified with schema, e.g. billing.invoices
+func (c *ConfigOpt) ListTables(db *gorm.DB) ([]string, error) {
 	tables, err := db.Migrator().GetTables()
 	if err != nil {
-		panic(fmt.Errorf("get all tables fail: %w", err))
+		return nil, fmt.Errorf("get all tables fail: %w", err)
+	}
+	for _, schemaName := range c.Schemas {
+		schemaTables, err := generate.GetTables(db, &model.Config{SchemaName: schemaName})
+		if err != nil {
+			return nil, fmt.Errorf("get all tables of schema %q fail: %w", schemaName, err)
+		}
+		for _, table := range schemaTables {
+			tables = append(tables, schemaName+"."+table)
+		}
 	}
 
-	mapTables := make(map[string][]ModelOpt)
+	result := tables[:0]
 	for _, table := range tables {
-		opts := []ModelOpt{}
-		if fieldTypes, ok := cfgOpt.FieldType[table]; ok {
-			for f, typ := range fieldTypes {
-				opts = append(opts, FieldType(f, typ))
-			}
+		if !c.Ignored(table[strings.LastIndex(table, ".")+1:]) {
+			result = append(result, table)
 		}
-		if fieldTypes, ok := cfgOpt.FieldRelate[table]; ok {
-			for f, relation := range fieldTypes {
-				r := field.RelationshipType(relation.Relation)
+	}
+	return result, nil
+}
 
-				switch r {
-				case field.HasOne, field.BelongsTo, field.HasMany, field.Many2Many:
-				default:
-					panic("unsupported relationship type: " + relation.Relation)
-				}
+// GenerateModels generate models of all tables with per-table field types and relations
+func (c *ConfigOpt) GenerateModels(g *Generator) ([]interface{}, error) {
+	tables, err := c.ListTables(g.db)
+	if err != nil {
+		return nil, err
+	}
 
-				opts = append(opts, FieldRelate(r, f, g.GenerateModel(relation.Table), relation.Config(db)))
+	var tableList []string
+	var models []interface{}
+	for _, table := range tables {
+		if i := strings.Index(table, "."); i > 0 {
+			models = append(models, g.GenerateModelIn(table[:i], table[i+1:], c.modelOpts(g, table)...))
+			continue
+		}
+		tableList = append(tableList, table)
+	}
+	return append(g.generateTables(tableList, func(table string) []ModelOpt { return c.modelOpts(g, table) }), models...), nil
+}
+
+func (c *ConfigOpt) modelOpts(g *Generator, table string) (opts []ModelOpt) {
+	if fieldTypes, ok := c.FieldType[table]; ok {
+		for f, typ := range fieldTypes {
+			opts = append(opts, FieldType(f, typ))
+		}
+	}
+	if fieldTypes, ok := c.FieldRelate[table]; ok {
+		for f, relation := range fieldTypes {
+			r := field.RelationshipType(relation.Relation)
+
+			switch r {
+			case field.HasOne, field.BelongsTo, field.HasMany, field.Many2Many:
+			default:
+				panic("unsupported relationship type: " + relation.Relation)
 			}
+
+			opts = append(opts, FieldRelate(r, f, g.GenerateModel(relation.Table), relation.Config(g.db)))
 		}
-		mapTables[table] = opts
 	}
+	return opts
+}
 
-	models := []interface{}{}
-	for tbl, opts := range mapTables {
-		models = append(models, g.GenerateModel(tbl, opts...))
+// GenerateWithDefault generate code of all tables with DefaultConfig, transformConfigFile is optional yaml config
+func GenerateWithDefault(db *gorm.DB, transformConfigFile string) {
+	if transformConfigFile == "" {
+		g := NewGenerator(DefaultConfig())
+		g.UseDB(db)
+		g.GenerateModels(g.GenerateAllTable()...)
+		g.Execute()
+		return
+	}
+
+	opt, err := LoadConfigOpt(transformConfigFile)
+	if err != nil {
+		panic(err)
+	}
+
+	g, err := opt.NewGenerator(db)
+	if err != nil {
+		panic(fmt.Errorf("create generator fail: %w", err))
+	}
+	models, err := opt.GenerateModels(g)
+	if err != nil {
+		panic(err)
 	}
 	g.GenerateModels(models...)
 
@@ -4,16 +4,19 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 
+	"golang.org/x/mod/modfile"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/imports"
 	"gorm.io/gorm"
@@ -81,6 +84,10 @@ type Generator struct {
 	Data   map[string]*genInfo                  // gen query data
 	models map[string]*generate.QueryStructMeta // gen model data
 
+	schemaGens map[string]*Generator // generators of schema sub packages, used by SchemaSubPackage layout
+
+	rendered *renderedFiles // files rendered in memory instead of written, set by ExecuteCheck
+
 	logger Logger
 }
 
@@ -111,7 +118,12 @@ func (g *Generator) GenerateModel(tableName string, opts ...ModelOpt) *generate.
 
 // GenerateModelAs catch table info from db, return a BaseStruct
 func (g *Generator) GenerateModelAs(tableName, modelName string, opts ...ModelOpt) *generate.QueryStructMeta {
-	meta, err := generate.GetQueryStructMeta(g.db, g.genModelConfig(tableName, modelName, opts))
+	return g.generateModel(g.genModelConfig(tableName, modelName, opts))
+}
+
+func (g *Generator) generateModel(conf *model.Config) *generate.QueryStructMeta {
+	tableName := conf.TableName
+	meta, err := generate.GetQueryStructMeta(g.db, conf)
 	if err != nil {
 		g.db.Logger.Error(context.Background(), "generate struct from table fail: %s", err)
 		panic("generate struct fail")
@@ -135,13 +147,164 @@ func (g *Generator) GenerateAllTable(opts ...ModelOpt) (tableModels []interface{
 
 	g.info(fmt.Sprintf("find %d table from db: %s", len(tableList), tableList))
 
+	return g.generateTables(tableList, func(string) []ModelOpt { return opts })
+}
+
+// generateTables generate tables with their own options, relation fields inferred from foreign keys are appended
+func (g *Generator) generateTables(tableList []string, tableOpts func(tableName string) []ModelOpt) (tableModels []interface{}) {
+	var relations map[string][]ModelOpt
+	if g.FieldWithRelation {
+		relations = g.foreignKeyRelations(tableList)
+	}
+
 	tableModels = make([]interface{}, len(tableList))
 	for i, tableName := range tableList {
+		opts := tableOpts(tableName)
+		if len(relations[tableName]) > 0 {
+			opts = append(append([]ModelOpt{}, opts...), relations[tableName]...)
+		}
 		tableModels[i] = g.GenerateModel(tableName, opts...)
 	}
 	return tableModels
 }
 
+// GenerateModelIn catch table info from db in specified schema, return a BaseStruct with schema-qualified table name
+//
+// With SchemaPrefix layout model is named with schema prefix, e.g. billing.invoices -> BillingInvoice (Q.Billing.Invoice);
+// with SchemaSubPackage layout model is generated into sub package named after schema, e.g. billing.Invoice (Q.Billing.Invoice)
+func (g *Generator) GenerateModelIn(schemaName, tableName string, opts ...ModelOpt) *generate.QueryStructMeta {
+	group, modelName := g.schemaModelName(schemaName, tableName)
+
+	if g.SchemaLayout == SchemaSubPackage {
+		child := g.schemaGenerator(schemaName)
+		conf := child.genModelConfig(tableName, modelName, opts)
+		conf.SchemaName = schemaName
+		return child.generateModel(conf)
+	}
+
+	conf := g.genModelConfig(tableName, group+modelName, opts)
+	conf.SchemaName = schemaName
+	conf.ModelNameNS = nil // already applied, keep the schema prefix
+	meta := g.generateModel(conf)
+	if meta != nil {
+		meta.SchemaGroup, meta.GroupField = group, modelName
+		meta.FileName = schemaName + "_" + meta.FileName
+	}
+	return meta
+}
+
+// schemaModelName schema group and model name of table in schema, e.g. billing.invoices -> Billing, Invoice
+//
+// The model is named group+modelName with SchemaPrefix layout, and modelName in schema sub package otherwise
+func (g *Generator) schemaModelName(schemaName, tableName string) (group, modelName string) {
+	modelName = g.db.Config.NamingStrategy.SchemaName(tableName)
+	if g.modelNameNS != nil {
+		modelName = g.modelNameNS(tableName)
+	}
+	return g.db.Config.NamingStrategy.SchemaName(schemaName), modelName
+}
+
+// GenerateAllTableIn generate all tables in specified schema
+func (g *Generator) GenerateAllTableIn(schemaName string, opts ...ModelOpt) (tableModels []interface{}) {
+	tableList, err := generate.GetTables(g.db, &model.Config{SchemaName: schemaName})
+	if err != nil {
+		panic(fmt.Errorf("get all tables of schema %q fail: %w", schemaName, err))
+	}
+
+	g.info(fmt.Sprintf("find %d table from schema <%s>: %s", len(tableList), schemaName, tableList))
+
+	tableModels = make([]interface{}, len(tableList))
+	for i, tableName := range tableList {
+		tableModels[i] = g.GenerateModelIn(schemaName, tableName, opts...)
+	}
+	return tableModels
+}
+
+// schemaGenerator generator of schema sub package, models and query code are generated under <OutPath>/<schema>
+func (g *Generator) schemaGenerator(schemaName string) *Generator {
+	if child := g.schemaGens[schemaName]; child != nil {
+		return child
+	}
+
+	modelOutPath, err := g.getModelOutputPath()
+	if err != nil {
+		panic(fmt.Errorf("create schema generator fail: %w", err))
+	}
+	pkgName := schemaPkgName(schemaName)
+
+	cfg := g.Config
+	cfg.OutPath = filepath.Join(g.OutPath, pkgName)
+	cfg.OutFile = filepath.Base(g.OutFile)
+	cfg.ModelPkgPath = filepath.Join(modelOutPath, pkgName)
+	if err = cfg.Revise(); err != nil {
+		panic(fmt.Errorf("create schema generator fail: %w", err))
+	}
+
+	child := &Generator{
+		Config: cfg,
+		Data:   make(map[string]*genInfo),
+		models: make(map[string]*generate.QueryStructMeta),
+
+		logger: g.logger,
+	}
+	if g.schemaGens == nil {
+		g.schemaGens = make(map[string]*Generator)
+	}
+	g.schemaGens[schemaName] = child
+	return child
+}
+
+// schemaPkgName package name for schema, e.g. "Billing-2024" -> "billing2024"
+func schemaPkgName(schemaName string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return -1
+	}, schemaName)
+}
+
+// GenerateView catch view or materialized view info from db, return a read-only BaseStruct
+func (g *Generator) GenerateView(viewName string, opts ...ModelOpt) *generate.QueryStructMeta {
+	return g.GenerateViewAs(viewName, g.db.Config.NamingStrategy.SchemaName(viewName), opts...)
+}
+
+// GenerateViewAs catch view or materialized view info from db, return a read-only BaseStruct
+func (g *Generator) GenerateViewAs(viewName, modelName string, opts ...ModelOpt) *generate.QueryStructMeta {
+	meta, err := generate.GetViewQueryStructMeta(g.db, g.genModelConfig(viewName, modelName, opts))
+	if err != nil {
+		g.db.Logger.Error(context.Background(), "generate struct from view fail: %s", err)
+		panic("generate struct fail")
+	}
+	if meta == nil {
+		g.info(fmt.Sprintf("ignore view <%s>", viewName))
+		return nil
+	}
+	g.models[meta.ModelStructName] = meta
+
+	g.info(fmt.Sprintf("got %d columns from view <%s>", len(meta.Fields), meta.TableName))
+	return meta
+}
+
+// GenerateAllView generate all views and materialized views in db
+func (g *Generator) GenerateAllView(opts ...ModelOpt) (viewModels []interface{}) {
+	viewList, err := generate.GetViews(g.db, g.genModelConfig("", "", nil))
+	if err != nil {
+		panic(fmt.Errorf("get all views fail: %w", err))
+	}
+
+	g.info(fmt.Sprintf("find %d view from db", len(viewList)))
+
+	viewModels = make([]interface{}, len(viewList))
+	for i, view := range viewList {
+		viewModels[i] = g.GenerateView(view.Name, opts...)
+	}
+	return viewModels
+}
+
 // GenerateModelFrom generate model from object
 func (g *Generator) GenerateModelFrom(obj helper.Object) *generate.QueryStructMeta {
 	s, err := generate.GetQueryStructMetaFromObject(obj, g.genModelObjConfig())
@@ -172,6 +335,7 @@ func (g *Generator) genModelConfig(tableName, modelName string, modelOpts []Mode
 			TableNameNS:    g.tableNameNS,
 			ModelNameNS:    g.modelNameNS,
 			FileNameNS:     g.fileNameNS,
+			EnumNameNS:     g.enumNameNS,
 		},
 		FieldConfig: model.FieldConfig{
 			DataTypeMap: g.dataTypeMap,
@@ -201,7 +365,11 @@ func (g *Generator) GenerateModels(models ...interface{}) {
 		}
 		structMeta.ReviseFieldNameFor(model.DOKeywords)
 
-		_, err := g.pushQueryStructMeta(structMeta)
+		target := g
+		if child := g.schemaGens[structMeta.Schema]; child != nil { // generated into schema sub package
+			target = child
+		}
+		_, err := target.pushQueryStructMeta(structMeta)
 		if err != nil {
 			g.db.Logger.Error(context.Background(), "gen struct fail: %v", err)
 			panic("gen struct fail")
@@ -235,17 +403,38 @@ func (g *Generator) genModelObjConfig() *model.Config {
 func (g *Generator) Execute() {
 	g.info("Start generating code.")
 
+	if err := g.generate(); err != nil {
+		g.db.Logger.Error(context.Background(), "%s", err)
+		panic(err.Error())
+	}
+
+	g.info("Generate code done.")
+}
+
+// generate generate code of schema sub packages, models and queries
+func (g *Generator) generate() error {
+	for _, schemaName := range g.schemaNames() {
+		child := g.schemaGens[schemaName]
+		child.rendered = g.rendered
+		if err := child.generate(); err != nil {
+			return err
+		}
+
+		pkgPath, err := loadPkgPath(child.OutPath)
+		if err != nil {
+			return fmt.Errorf("parse query pkg path of schema %s fail: %w", schemaName, err)
+		}
+		child.queryPkgPath = pkgPath
+	}
+
 	if err := g.generateModelFile(); err != nil {
-		g.db.Logger.Error(context.Background(), "generate model struct fail: %s", err)
-		panic("generate model struct fail")
+		return fmt.Errorf("generate model struct fail: %w", err)
 	}
 
 	if err := g.generateQueryFile(); err != nil {
-		g.db.Logger.Error(context.Background(), "generate query code fail: %s", err)
-		panic("generate query code fail")
+		return fmt.Errorf("generate query code fail: %w", err)
 	}
-
-	g.info("Generate code done.")
+	return nil
 }
 
 // info logger
@@ -258,11 +447,11 @@ func (g *Generator) info(logInfos ...string) {
 
 // generateQueryFile generate query code and save to file
 func (g *Generator) generateQueryFile() (err error) {
-	if len(g.Data) == 0 {
+	if len(g.Data) == 0 && len(g.schemaGens) == 0 {
 		return nil
 	}
 
-	if err = os.MkdirAll(g.OutPath, os.ModePerm); err != nil {
+	if err = g.mkdir(g.OutPath); err != nil {
 		return fmt.Errorf("make dir outpath(%s) fail: %s", g.OutPath, err)
 	}
 
@@ -278,7 +467,7 @@ func (g *Generator) generateQueryFile() (err error) {
 				errChan <- err
 			}
 
-			if g.WithUnitTest {
+			if g.WithUnitTest && !info.ReadOnly() { // unit test writes data
 				err = g.generateQueryUnitTestFile(info)
 				if err != nil { // do not panic
 					g.db.Logger.Error(context.Background(), "generate unit test fail: %s", err)
@@ -296,7 +485,7 @@ func (g *Generator) generateQueryFile() (err error) {
 	var buf bytes.Buffer
 	err = render(tmpl.Header, &buf, map[string]interface{}{
 		"Package":        g.queryPkgName,
-		"ImportPkgPaths": importList.Add(g.importPkgPaths...).Paths(),
+		"ImportPkgPaths": importList.Add(g.importPkgPaths...).Add(g.schemaPkgPaths()...).Paths(),
 	})
 	if err != nil {
 		return err
@@ -393,6 +582,12 @@ func (g *Generator) generateSingleQueryFile(data *genInfo) (err error) {
         if data.StructInfo.Package != "" {
             data.QueryStructMeta.StructPkgPrefix = data.StructInfo.Package + "."
         }
+        // Enum types live in model package
+        for _, f := range data.Fields {
+            if f.EnumType != "" && !strings.Contains(f.EnumType, ".") {
+                f.EnumType = data.QueryStructMeta.StructPkgPrefix + f.EnumType
+            }
+        }
     }
     err = render(tmpl.Header, &buf, map[string]interface{}{
         "Package": g.queryPkgName,
@@ -477,7 +672,7 @@ func (g *Generator) generateModelFile() error {
 		return err
 	}
 
-	if err = os.MkdirAll(modelOutPath, os.ModePerm); err != nil {
+	if err = g.mkdir(modelOutPath); err != nil {
 		return fmt.Errorf("create model pkg path(%s) fail: %s", modelOutPath, err)
 	}
 
@@ -530,6 +725,43 @@ func (g *Generator) generateModelFile() error {
 	case <-pool.AsyncWaitAll():
 		g.fillModelPkgPath(modelOutPath)
 	}
+	return g.generateEnumFile(modelOutPath)
+}
+
+// generateEnumFile generate enum types used by models and save to file
+func (g *Generator) generateEnumFile(modelOutPath string) error {
+	var pkg string
+	enumMap := make(map[string]*model.Enum)
+	for _, data := range g.models {
+		if data == nil || !data.Generated {
+			continue
+		}
+		for _, e := range data.Enums {
+			enumMap[e.Name] = e
+			pkg = data.StructInfo.Package
+		}
+	}
+	if len(enumMap) == 0 {
+		return nil
+	}
+
+	enums := make([]*model.Enum, 0, len(enumMap))
+	for _, e := range enumMap {
+		enums = append(enums, e)
+	}
+	sort.Slice(enums, func(i, j int) bool { return enums[i].Name < enums[j].Name })
+
+	var buf bytes.Buffer
+	err := render(tmpl.Enum, &buf, map[string]interface{}{"Package": pkg, "Enums": enums})
+	if err != nil {
+		return err
+	}
+
+	enumFile := modelOutPath + "enums.gen.go"
+	if err = g.output(enumFile, buf.Bytes()); err != nil {
+		return err
+	}
+	g.info(fmt.Sprintf("generate enum file: %s", enumFile))
 	return nil
 }
 
@@ -546,19 +778,59 @@ func (g *Generator) getModelOutputPath() (outPath string, err error) {
 }
 
 func (g *Generator) fillModelPkgPath(filePath string) {
+	pkgPath, err := loadPkgPath(filePath)
+	if err != nil {
+		g.db.Logger.Warn(context.Background(), "parse model pkg path fail: %s", err)
+		return
+	}
+	g.Config.modelPkgPath = pkgPath
+}
+
+// loadPkgPath import path of package in dir
+//
+// import path is resolved from go.mod when dir has no go files yet, e.g. not generated in check mode
+func loadPkgPath(dir string) (string, error) {
 	pkgs, err := packages.Load(&packages.Config{
 		Mode: packages.NeedName,
-		Dir:  filePath,
+		Dir:  dir,
 	})
+	if err == nil && len(pkgs) > 0 && pkgs[0].PkgPath != "." {
+		return pkgs[0].PkgPath, nil
+	}
+	if pkgPath, modErr := modulePkgPath(dir); modErr == nil {
+		return pkgPath, nil
+	}
 	if err != nil {
-		g.db.Logger.Warn(context.Background(), "parse model pkg path fail: %s", err)
-		return
+		return "", err
 	}
-	if len(pkgs) == 0 {
-		g.db.Logger.Warn(context.Background(), "parse model pkg path fail: got 0 packages")
-		return
+	return "", errors.New("got 0 packages")
+}
+
+// modulePkgPath import path of dir under the module of nearest go.mod
+func modulePkgPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for root := dir; ; root = filepath.Dir(root) {
+		if data, err := os.ReadFile(filepath.Join(root, "go.mod")); err == nil {
+			modPath := modfile.ModulePath(data)
+			if modPath == "" {
+				return "", fmt.Errorf("no module path in %s", filepath.Join(root, "go.mod"))
+			}
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return modPath, nil
+			}
+			return modPath + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("go.mod not found for %s", dir)
+		}
 	}
-	g.Config.modelPkgPath = pkgs[0].PkgPath
 }
 
 // output format and output
@@ -580,9 +852,23 @@ func (g *Generator) output(fileName string, content []byte) error {
 		}
 		return fmt.Errorf("cannot format file: %w", err)
 	}
+	if g.rendered != nil {
+		g.rendered.Lock()
+		defer g.rendered.Unlock()
+		g.rendered.files[filepath.Clean(fileName)] = result
+		return nil
+	}
 	return os.WriteFile(fileName, result, 0o640)
 }
 
+// mkdir create output dir, nothing is created in check mode
+func (g *Generator) mkdir(dir string) error {
+	if g.rendered != nil {
+		return nil
+	}
+	return os.MkdirAll(dir, os.ModePerm)
+}
+
 func (g *Generator) pushQueryStructMeta(meta *generate.QueryStructMeta) (*genInfo, error) {
 	structName := meta.ModelStructName
 	if g.Data[structName] == nil {
@@ -600,6 +886,84 @@ func (g *Generator) pushQueryStructMeta(meta *generate.QueryStructMeta) (*genInf
 	return g.Data[structName], nil
 }
 
+// schemaGroup query structs of one schema, grouped as a field of Query
+type schemaGroup struct {
+	Name    string     // field name in Query, e.g. Billing
+	Schema  string     // schema name in db server
+	PkgName string     // query package name, only set for SchemaSubPackage layout
+	Data    []*genInfo // query structs, only set for SchemaPrefix layout
+}
+
+// TypeName name of group struct type, e.g. billingSchema
+func (s *schemaGroup) TypeName() string {
+	return strings.ToLower(s.Name[:1]) + s.Name[1:] + "Schema"
+}
+
+// DefaultSchemaData query structs not grouped by schema, sorted by name
+func (g *Generator) DefaultSchemaData() (data []*genInfo) {
+	for _, d := range g.Data {
+		if d.SchemaGroup == "" {
+			data = append(data, d)
+		}
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].ModelStructName < data[j].ModelStructName })
+	return data
+}
+
+// SchemaGroups query structs grouped by schema, sorted by name
+func (g *Generator) SchemaGroups() (groups []*schemaGroup) {
+	groupMap := make(map[string]*schemaGroup)
+	for _, d := range g.Data {
+		if d.SchemaGroup == "" {
+			continue
+		}
+		group := groupMap[d.SchemaGroup]
+		if group == nil {
+			group = &schemaGroup{Name: d.SchemaGroup, Schema: d.Schema}
+			groupMap[d.SchemaGroup] = group
+			groups = append(groups, group)
+		}
+		group.Data = append(group.Data, d)
+	}
+	for _, schemaName := range g.schemaNames() {
+		child := g.schemaGens[schemaName]
+		if len(child.Data) == 0 {
+			continue
+		}
+		groups = append(groups, &schemaGroup{
+			Name:    g.db.Config.NamingStrategy.SchemaName(schemaName),
+			Schema:  schemaName,
+			PkgName: child.queryPkgName,
+		})
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	for _, group := range groups {
+		sort.Slice(group.Data, func(i, j int) bool { return group.Data[i].GroupField < group.Data[j].GroupField })
+	}
+	return groups
+}
+
+// schemaNames names of schema generated into sub package, sorted
+func (g *Generator) schemaNames() []string {
+	names := make([]string, 0, len(g.schemaGens))
+	for name := range g.schemaGens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schemaPkgPaths import paths of schema sub packages
+func (g *Generator) schemaPkgPaths() (paths []string) {
+	for _, schemaName := range g.schemaNames() {
+		if child := g.schemaGens[schemaName]; child.queryPkgPath != "" && len(child.Data) > 0 {
+			paths = append(paths, child.queryPkgPath)
+		}
+	}
+	return paths
+}
+
 func render(tmpl string, wr io.Writer, data interface{}) error {
 	t, err := template.New(tmpl).Parse(tmpl)
 	if err != nil {
@@ -2,6 +2,10 @@ package gen
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +16,9 @@ import (
 	"gorm.io/gorm/utils/tests"
 
 	"go.ipao.vip/gen/field"
+	"go.ipao.vip/gen/helper"
+	"go.ipao.vip/gen/internal/generate"
+	"go.ipao.vip/gen/internal/model"
 )
 
 func TestConfig(t *testing.T) {
@@ -27,6 +34,639 @@ func TestConfig(t *testing.T) {
 	}
 }
 
+func TestConfigOpt(t *testing.T) {
+	var opt ConfigOpt
+	err := helper.UnmarshalYAML([]byte(`
+out_path: ./dal
+field_nullable: true
+field_signable: false
+mode: [default_query, without_context]
+schema_layout: sub_package
+data_types:
+  numeric: decimal.Decimal
+ignores: [schema_migrations]
+ignore_patterns: ["^tmp_"]
+renames:
+  people: Person
+`), &opt)
+	if err != nil {
+		t.Fatalf("parse yaml fail: %s", err)
+	}
+
+	cfg, err := opt.Config()
+	if err != nil {
+		t.Fatalf("build config fail: %s", err)
+	}
+	if cfg.OutPath != "./dal" || cfg.ModelPkgPath != "./dal" {
+		t.Errorf("expect co-located ./dal, got %q and %q", cfg.OutPath, cfg.ModelPkgPath)
+	}
+	if !cfg.FieldNullable || cfg.FieldSignable || !cfg.FieldWithTypeTag {
+		t.Errorf("field flags not applied: %+v", cfg)
+	}
+	if cfg.Mode != WithDefaultQuery|WithoutContext || cfg.SchemaLayout != SchemaSubPackage {
+		t.Errorf("mode or schema layout not applied: %v, %v", cfg.Mode, cfg.SchemaLayout)
+	}
+	if f := cfg.dataTypeMap["numeric"]; f == nil || f(nil) != "decimal.Decimal" || cfg.dataTypeMap["jsonb"] == nil {
+		t.Errorf("data types should extend default mapping")
+	}
+	for table, ignored := range map[string]bool{"schema_migrations": true, "tmp_orders": true, "_backup": true, "orders": false} {
+		if cfg.tableNameNS(table) == "" != ignored {
+			t.Errorf("table %s ignored should be %v", table, ignored)
+		}
+	}
+	if cfg.modelNameNS != nil {
+		t.Errorf("renames are applied by generator with naming strategy of db")
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "schema.sql"), "CREATE TABLE t_orders (id bigint PRIMARY KEY);")
+	db, err := OpenDDL(filepath.Join(dir, "schema.sql"))
+	if err != nil {
+		t.Fatalf("open ddl fail: %s", err)
+	}
+	db.Config.NamingStrategy = schema.NamingStrategy{TablePrefix: "t_"}
+	g, err := opt.NewGenerator(db)
+	if err != nil {
+		t.Fatalf("create generator fail: %s", err)
+	}
+	for table, want := range map[string]string{"people": "Person", "t_orders": "Order"} {
+		if name := g.modelNameNS(table); name != want {
+			t.Errorf("expect model name of %s to be %s, got %s", table, want, name)
+		}
+	}
+
+	if _, err = (&ConfigOpt{Mode: []string{"unknown"}}).Config(); err == nil {
+		t.Errorf("expect error of unknown mode")
+	}
+}
+
+func TestOpenDDL(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"0001_init.up.sql": `
+CREATE TYPE order_status AS ENUM ('pending', 'paid');
+CREATE TABLE users (
+	id bigserial PRIMARY KEY,
+	email varchar(255) NOT NULL UNIQUE,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE TABLE public.orders (
+	id integer GENERATED ALWAYS AS IDENTITY,
+	user_id bigint NOT NULL REFERENCES users,
+	status order_status NOT NULL DEFAULT 'pending'::order_status,
+	tags text[],
+	amount numeric(10,2),
+	CONSTRAINT orders_pkey PRIMARY KEY (id)
+);
+CREATE INDEX idx_orders_user ON ONLY public.orders USING btree (user_id);
+COMMENT ON COLUMN public.orders.amount IS 'total amount';`,
+		"0001_init.down.sql": `DROP TABLE orders;`,
+		"0002_note.up.sql":   `ALTER TABLE orders ADD COLUMN note text DEFAULT 'n/a', ALTER COLUMN amount SET NOT NULL;`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ddlDB, err := OpenDDL(dir)
+	if err != nil {
+		t.Fatalf("open ddl fail: %s", err)
+	}
+	g := NewGenerator(Config{OutPath: filepath.Join(dir, "query"), FieldWithIndexTag: true})
+	g.UseDB(ddlDB)
+
+	tables, err := ddlDB.Migrator().GetTables()
+	if err != nil || strings.Join(tables, ",") != "orders,users" {
+		t.Errorf("expect tables orders,users, got %v %v", tables, err)
+	}
+
+	meta := g.GenerateModel("orders")
+	fields := make(map[string]*model.Field)
+	for _, f := range meta.Fields {
+		fields[f.ColumnName] = f
+	}
+	for column, want := range map[string]string{
+		"id":      "int32;primaryKey;autoIncrement:true",
+		"user_id": "int64;not null;index:idx_orders_user,priority:1",
+		"status":  "OrderStatus;not null;default:pending",
+		"tags":    "types.Array[string];",
+		"amount":  "types.Decimal;precision:10;scale:2;not null",
+		"note":    "string;default:n/a",
+	} {
+		f := fields[column]
+		if f == nil {
+			t.Errorf("column %s not found", column)
+			continue
+		}
+		typ, tag, _ := strings.Cut(want, ";")
+		if got := f.GORMTag.Build(); f.Type != typ || !strings.Contains(got, tag) {
+			t.Errorf("column %s: expect %s with tag %q, got %s with tag %q", column, typ, tag, f.Type, got)
+		}
+	}
+	if f := fields["amount"]; f != nil && f.ColumnComment != "total amount" {
+		t.Errorf("expect comment of amount, got %q", f.ColumnComment)
+	}
+	if len(meta.Enums) != 1 || len(meta.Enums[0].Values) != 2 {
+		t.Errorf("expect enum order_status with 2 labels, got %+v", meta.Enums)
+	}
+
+	fks, err := generate.GetForeignKeys(ddlDB, g.genModelConfig("", "", nil))
+	if err != nil || len(fks) != 1 || fks[0].Name != "orders_user_id_fkey" || fks[0].RefColumnList != "id" {
+		t.Errorf("expect foreign key orders_user_id_fkey to users.id, got %+v %v", fks, err)
+	}
+}
+
+func TestGenerateEnum(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.18\n")
+	writeTestFile(t, filepath.Join(dir, "schema.sql"), `
+CREATE TYPE order_status AS ENUM ('pending', 'in-progress', 'paid');
+CREATE TYPE unused_mood AS ENUM ('sad', 'happy');
+CREATE TABLE orders (id bigint PRIMARY KEY, status order_status NOT NULL, history order_status[]);`)
+	db, err := OpenDDL(filepath.Join(dir, "schema.sql"))
+	if err != nil {
+		t.Fatalf("open ddl fail: %s", err)
+	}
+	g := NewGenerator(Config{OutPath: filepath.Join(dir, "database")})
+	g.UseDB(db)
+	g.GenerateModels(g.GenerateAllTable()...)
+	g.Execute()
+
+	enums := readTestFile(t, filepath.Join(dir, "model", "enums.gen.go"))
+	for _, want := range []string{
+		"type OrderStatus string",
+		`OrderStatusInProgress OrderStatus = "in-progress"`,
+		"return []OrderStatus{OrderStatusPending, OrderStatusInProgress, OrderStatusPaid}",
+		`func (OrderStatus) GormDataType() string { return "order_status" }`,
+		"case nil:\n\t\t*e = \"\"\n\t\treturn nil",
+		"if e == \"\" {\n\t\treturn nil, nil\n\t}",
+	} {
+		if !strings.Contains(enums, want) {
+			t.Errorf("expect %q in enums.gen.go, got:\n%s", want, enums)
+		}
+	}
+	if strings.Contains(enums, "UnusedMood") {
+		t.Errorf("enum not used by generated tables should be skipped")
+	}
+
+	orders := readTestFile(t, filepath.Join(dir, "model", "orders.gen.go"))
+	for _, want := range []string{"Status  OrderStatus ", "History types.Array[OrderStatus] "} {
+		if !strings.Contains(orders, want) {
+			t.Errorf("expect field %q in orders.gen.go, got:\n%s", want, orders)
+		}
+	}
+	if query := readTestFile(t, filepath.Join(dir, "database", "orders.gen.go")); !strings.Contains(query, "field.Enum[model.OrderStatus]") {
+		t.Errorf("expect field.Enum[model.OrderStatus] in query code")
+	}
+}
+
+func TestGenerateSchemaLayout(t *testing.T) {
+	const schemaSQL = `
+CREATE TYPE order_status AS ENUM ('pending', 'paid');
+CREATE SCHEMA billing;
+CREATE TABLE invoices (id bigint PRIMARY KEY, note text, amount integer);
+CREATE INDEX idx_invoices_note ON invoices (note, amount);
+CREATE TABLE billing.invoices (id bigint PRIMARY KEY, status order_status NOT NULL, amount numeric(10,2), due_on date);
+CREATE INDEX idx_invoices_due ON billing.invoices (due_on, amount);`
+
+	for _, tc := range []struct {
+		layout    SchemaLayout
+		modelFile string
+		queryFile string
+		model     []string
+		query     []string
+	}{
+		{
+			layout:    SchemaPrefix,
+			modelFile: "model/billing_invoices.gen.go",
+			queryFile: "database/gen.go",
+			model: []string{
+				"package model",
+				"type BillingInvoice struct",
+				`const TableNameBillingInvoice = "billing.invoices"`,
+				"Status OrderStatus ",
+				`gorm:"column:amount;type:numeric(10,2);precision:10;scale:2;index:idx_invoices_due,priority:2"`,
+			},
+			query: []string{"Billing billingSchema", "Invoice billingInvoice"},
+		},
+		{
+			layout:    SchemaSubPackage,
+			modelFile: "model/billing/invoices.gen.go",
+			queryFile: "database/billing/gen.go",
+			model: []string{
+				"package billing",
+				"type Invoice struct",
+				`const TableNameInvoice = "billing.invoices"`,
+				"Status OrderStatus ",
+				`gorm:"column:amount;type:numeric(10,2);precision:10;scale:2;index:idx_invoices_due,priority:2"`,
+			},
+			query: []string{"package billing", "Invoice *invoice"},
+		},
+	} {
+		dir := t.TempDir()
+		writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.18\n")
+		writeTestFile(t, filepath.Join(dir, "schema.sql"), schemaSQL)
+		db, err := OpenDDL(filepath.Join(dir, "schema.sql"))
+		if err != nil {
+			t.Fatalf("open ddl fail: %s", err)
+		}
+		g := NewGenerator(Config{OutPath: filepath.Join(dir, "database"), SchemaLayout: tc.layout, FieldWithIndexTag: true, FieldWithTypeTag: true})
+		g.UseDB(db)
+		g.GenerateModels(g.GenerateAllTableIn("billing")...)
+		g.GenerateModels(g.GenerateModel("invoices"))
+		g.Execute()
+
+		code := readTestFile(t, filepath.Join(dir, tc.modelFile))
+		for _, want := range tc.model {
+			if !strings.Contains(code, want) {
+				t.Errorf("layout %d: expect %q in %s, got:\n%s", tc.layout, want, tc.modelFile, code)
+			}
+		}
+		if strings.Contains(code, "idx_invoices_note") {
+			t.Errorf("layout %d: index of public.invoices should not be in billing.invoices", tc.layout)
+		}
+		query := readTestFile(t, filepath.Join(dir, tc.queryFile))
+		for _, want := range tc.query {
+			if !strings.Contains(query, want) {
+				t.Errorf("layout %d: expect %q in %s, got:\n%s", tc.layout, want, tc.queryFile, query)
+			}
+		}
+		if public := readTestFile(t, filepath.Join(dir, "model", "invoices.gen.go")); !strings.Contains(public, "index:idx_invoices_note,priority:1") {
+			t.Errorf("layout %d: expect index of public.invoices, got:\n%s", tc.layout, public)
+		}
+	}
+}
+
+func TestGenerateRelation(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.18\n")
+	writeTestFile(t, filepath.Join(dir, "schema.sql"), `
+CREATE SCHEMA billing;
+CREATE TABLE billing.accounts (id bigint PRIMARY KEY, balance numeric);
+CREATE TABLE customers (id bigint PRIMARY KEY, name text, account_id bigint REFERENCES billing.accounts (id));
+CREATE TABLE profiles (id bigint PRIMARY KEY, customer_id bigint NOT NULL UNIQUE REFERENCES customers (id));
+CREATE TABLE orders (id bigint PRIMARY KEY, customer_id bigint NOT NULL REFERENCES customers (id));
+CREATE TABLE products (id bigint PRIMARY KEY, title text);
+CREATE TABLE users (id bigint PRIMARY KEY, name text);
+CREATE TABLE documents (
+	id bigint PRIMARY KEY,
+	created_by bigint NOT NULL REFERENCES users (id),
+	updated_by bigint REFERENCES users (id)
+);
+CREATE TABLE order_products (
+	order_id bigint NOT NULL REFERENCES orders (id),
+	product_id bigint NOT NULL REFERENCES products (id),
+	PRIMARY KEY (order_id, product_id)
+);`)
+	db, err := OpenDDL(filepath.Join(dir, "schema.sql"))
+	if err != nil {
+		t.Fatalf("open ddl fail: %s", err)
+	}
+	g := NewGenerator(Config{OutPath: filepath.Join(dir, "database"), FieldWithRelation: true})
+	g.UseDB(db)
+	g.GenerateModels(g.GenerateAllTable()...)
+	g.GenerateModels(g.GenerateAllTableIn("billing")...)
+	g.Execute()
+
+	for file, wants := range map[string][]string{
+		"customers.gen.go": {
+			"Account *BillingAccount `gorm:\"foreignKey:AccountID;references:ID\" json:\"account,omitempty\"`",
+			"Profile *Profile `gorm:\"foreignKey:CustomerID;references:ID\" json:\"profile,omitempty\"`",
+			"Orders []Order `gorm:\"foreignKey:CustomerID;references:ID\" json:\"orders,omitempty\"`",
+		},
+		"orders.gen.go": {
+			"Customer *Customer `gorm:\"foreignKey:CustomerID;references:ID\" json:\"customer,omitempty\"`",
+			"Products []Product `gorm:\"foreignKey:ID;joinForeignKey:OrderID;joinReferences:ProductID;many2many:order_products;references:ID\" json:\"products,omitempty\"`",
+		},
+		"documents.gen.go": {
+			"CreatedBy int64 `gorm:\"column:created_by;not null\" json:\"created_by\"`",
+			"CreatedByUser *User `gorm:\"foreignKey:CreatedBy;references:ID\" json:\"created_by_user,omitempty\"`",
+			"UpdatedByUser *User `gorm:\"foreignKey:UpdatedBy;references:ID\" json:\"updated_by_user,omitempty\"`",
+		},
+		"users.gen.go": {
+			"CreatedByDocuments []Document `gorm:\"foreignKey:CreatedBy;references:ID\" json:\"created_by_documents,omitempty\"`",
+			"UpdatedByDocuments []Document `gorm:\"foreignKey:UpdatedBy;references:ID\" json:\"updated_by_documents,omitempty\"`",
+		},
+		"products.gen.go": {
+			"Orders []Order `gorm:\"foreignKey:ID;joinForeignKey:ProductID;joinReferences:OrderID;many2many:order_products;references:ID\" json:\"orders,omitempty\"`",
+		},
+	} {
+		code := readTestFile(t, filepath.Join(dir, "model", file))
+		fields := strings.Join(strings.Fields(code), " ") // ignore alignment
+		for _, want := range wants {
+			if !strings.Contains(fields, want) {
+				t.Errorf("expect %q in %s, got:\n%s", want, file, code)
+			}
+		}
+	}
+	if code := readTestFile(t, filepath.Join(dir, "model", "billing_accounts.gen.go")); strings.Contains(code, "Customers") {
+		t.Errorf("relation should not be added to model of another schema, got:\n%s", code)
+	}
+	if code := readTestFile(t, filepath.Join(dir, "model", "orders.gen.go")); strings.Contains(code, "OrderProducts") {
+		t.Errorf("join table should be related by many2many only, got:\n%s", code)
+	}
+}
+
+func TestGenerateTimeOfDay(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.18\n")
+	writeTestFile(t, filepath.Join(dir, "schema.sql"), `CREATE TABLE shifts (id bigint PRIMARY KEY, starts_at time NOT NULL, local_at timetz NOT NULL, day date NOT NULL);`)
+	db, err := OpenDDL(filepath.Join(dir, "schema.sql"))
+	if err != nil {
+		t.Fatalf("open ddl fail: %s", err)
+	}
+	g := NewGenerator(Config{OutPath: filepath.Join(dir, "database")})
+	g.UseDB(db)
+	g.GenerateModels(g.GenerateModel("shifts"))
+	g.Execute()
+
+	// timetz keeps its offset as string, types.Time has no offset
+	code := strings.Join(strings.Fields(readTestFile(t, filepath.Join(dir, "database", "shifts.gen.go"))+
+		readTestFile(t, filepath.Join(dir, "model", "shifts.gen.go"))), " ")
+	for _, want := range []string{
+		"StartsAt types.Time", "LocalAt string", "Day types.Date",
+		"StartsAt field.TimeOfDay", "LocalAt field.String", "Day field.Date",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expect %q in generated code, got:\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateView(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.18\n")
+	writeTestFile(t, filepath.Join(dir, "schema.sql"), `CREATE TABLE order_totals (user_id bigint PRIMARY KEY, total numeric(12,2));`)
+	db, err := OpenDDL(filepath.Join(dir, "schema.sql"))
+	if err != nil {
+		t.Fatalf("open ddl fail: %s", err)
+	}
+	g := NewGenerator(Config{OutPath: filepath.Join(dir, "database")})
+	g.UseDB(db)
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "views are not parsed from DDL") {
+				t.Errorf("expect error of views in DDL mode, got %v", r)
+			}
+		}()
+		g.GenerateAllView()
+	}()
+
+	// columns are read from DDL table, view info is what GetViews reports from database
+	meta := g.GenerateModel("order_totals")
+	meta.View = &model.View{Name: "order_totals", Materialized: true}
+	g.GenerateModels(meta)
+	g.Execute()
+
+	code := readTestFile(t, filepath.Join(dir, "database", "order_totals.gen.go")) +
+		readTestFile(t, filepath.Join(dir, "model", "order_totals.gen.go"))
+	for _, want := range []string{
+		"gen.ReadOnly // view is read-only",
+		"func (o orderTotalDo) Refresh(ctx context.Context, concurrently bool) error",
+		"mapped from materialized view <order_totals>",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expect %q in generated code", want)
+		}
+	}
+	for _, unwanted := range []string{"func (o orderTotalDo) Create(", "func (o orderTotalDo) Delete(", "func (m *OrderTotal) Update(", "Upsert(", "InsertFrom(", "ForUpdate("} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("unexpected write method %q of view", unwanted)
+		}
+	}
+}
+
+func TestExecuteCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("go.mod", "module example.com/app\n\ngo 1.18\n")
+	writeFile("0001_init.up.sql", `CREATE TABLE users (id bigserial PRIMARY KEY, name text NOT NULL);`)
+
+	newGenerator := func() *Generator {
+		db, err := OpenDDL(dir)
+		if err != nil {
+			t.Fatalf("open ddl fail: %s", err)
+		}
+		g := NewGenerator(Config{OutPath: filepath.Join(dir, "database")})
+		g.UseDB(db)
+		g.GenerateModels(g.GenerateAllTable()...)
+		return g
+	}
+
+	diffs, err := newGenerator().ExecuteCheck()
+	if err != nil {
+		t.Fatalf("check fail: %s", err)
+	}
+	if len(diffs) == 0 || diffs[0].Kind != DiffAdded {
+		t.Errorf("expect added files before generating, got %v", diffs)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "database")); !os.IsNotExist(err) {
+		t.Errorf("expect nothing written in check mode, got %v", err)
+	}
+
+	newGenerator().Execute()
+	if diffs, err = newGenerator().ExecuteCheck(); err != nil || len(diffs) != 0 {
+		t.Errorf("expect no diff after generating, got %v %v", diffs, err)
+	}
+
+	writeFile("0002_email.up.sql", `ALTER TABLE users ADD COLUMN email text, DROP COLUMN name;`)
+	diffs, err = newGenerator().ExecuteCheck()
+	if err != nil {
+		t.Fatalf("check fail: %s", err)
+	}
+	var modelDiff *FileDiff
+	for i := range diffs {
+		if diffs[i].Model == "User" {
+			modelDiff = &diffs[i]
+		}
+	}
+	if modelDiff == nil || modelDiff.Kind != DiffChanged ||
+		strings.Join(modelDiff.AddedFields, ",") != "Email" || strings.Join(modelDiff.RemovedFields, ",") != "Name" {
+		t.Errorf("expect model User changed with +Email -Name, got %v", diffs)
+	}
+
+	writeFile("0003_drop.up.sql", `DROP TABLE users; CREATE TABLE accounts (id bigint PRIMARY KEY);`)
+	diffs, err = newGenerator().ExecuteCheck()
+	if err != nil {
+		t.Fatalf("check fail: %s", err)
+	}
+	var removed bool
+	for _, d := range diffs {
+		if d.Kind == DiffRemoved && filepath.Base(d.Path) == "users.gen.go" {
+			removed = true
+		}
+	}
+	if !removed {
+		t.Errorf("expect users.gen.go removed, got %v", diffs)
+	}
+}
+
+func TestGenerateMigration(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.sql")
+	err := os.WriteFile(schemaFile, []byte(`
+CREATE TABLE users (
+	id bigserial PRIMARY KEY,
+	name varchar(64),
+	age integer NOT NULL DEFAULT 0,
+	legacy text
+);
+COMMENT ON COLUMN users.legacy IS 'unused';
+CREATE INDEX idx_users_legacy ON users (legacy);`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := OpenDDL(schemaFile)
+	if err != nil {
+		t.Fatalf("open ddl fail: %s", err)
+	}
+	g := NewGenerator(Config{OutPath: filepath.Join(dir, "query")})
+	g.UseDB(db)
+
+	user := g.GenerateModelFrom(migrationObject{table: "users", fields: []migrationField{
+		{name: "ID", typ: "int64", gormTag: "column:id;primaryKey;autoIncrement:true"},
+		{name: "Name", typ: "string", gormTag: "column:name;size:128;not null"},
+		{name: "Age", typ: "int32", gormTag: "column:age;not null;default:0"},
+		{name: "Email", typ: "string", gormTag: "column:email;uniqueIndex:idx_users_email", comment: "login email"},
+	}})
+	order := g.GenerateModelFrom(migrationObject{table: "orders", fields: []migrationField{
+		{name: "ID", typ: "int64", gormTag: "primaryKey;autoIncrement:true"},
+		{name: "Amount", typ: "float64", gormTag: "type:numeric(10,2);not null"},
+	}})
+
+	m, err := g.DiffMigration(user, order)
+	if err != nil {
+		t.Fatalf("diff migration fail: %s", err)
+	}
+	expectUp := []string{
+		`CREATE TABLE "orders" (` + "\n\t" + `"id" bigserial,` + "\n\t" + `"amount" numeric(10,2) NOT NULL,` + "\n\t" + `PRIMARY KEY ("id")` + "\n)",
+		`DROP INDEX CONCURRENTLY IF EXISTS "idx_users_legacy"`,
+		`ALTER TABLE "users" ADD COLUMN "email" text`,
+		`ALTER TABLE "users" ALTER COLUMN "name" TYPE character varying(128) USING "name"::character varying(128)`,
+		`ALTER TABLE "users" ALTER COLUMN "name" SET NOT NULL`,
+		`ALTER TABLE "users" DROP COLUMN "legacy"`,
+		`CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS "idx_users_email" ON "users" ("email")`,
+		`COMMENT ON COLUMN "users"."email" IS 'login email'`,
+		`COMMENT ON COLUMN "users"."legacy" IS NULL`,
+	}
+	if got := strings.Join(m.Up, "\n"); got != strings.Join(expectUp, "\n") {
+		t.Errorf("unexpected up migration:\n%s", got)
+	}
+	expectDown := []string{
+		`COMMENT ON COLUMN "users"."legacy" IS 'unused'`,
+		`COMMENT ON COLUMN "users"."email" IS NULL`,
+		`DROP INDEX CONCURRENTLY IF EXISTS "idx_users_email"`,
+		`ALTER TABLE "users" ADD COLUMN "legacy" text`,
+		`ALTER TABLE "users" ALTER COLUMN "name" DROP NOT NULL`,
+		`ALTER TABLE "users" ALTER COLUMN "name" TYPE character varying(64) USING "name"::character varying(64)`,
+		`ALTER TABLE "users" DROP COLUMN "email"`,
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS "idx_users_legacy" ON "users" ("legacy")`,
+		`DROP TABLE "orders"`,
+	}
+	if got := strings.Join(m.Down, "\n"); got != strings.Join(expectDown, "\n") {
+		t.Errorf("unexpected down migration:\n%s", got)
+	}
+
+	files, err := g.GenerateMigration(filepath.Join(dir, "migrations"), "sync_users", user, order)
+	if err != nil || len(files) != 2 || !strings.HasSuffix(files[0], "_sync_users.up.sql") || !strings.HasSuffix(files[1], "_sync_users.down.sql") {
+		t.Fatalf("expect up and down migration files, got %v %v", files, err)
+	}
+
+	// database migrated by generated migration is up to date
+	migrated, err := OpenDDL(schemaFile, files[0])
+	if err != nil {
+		t.Fatalf("apply migration fail: %s", err)
+	}
+	g.UseDB(migrated)
+	if m, err = g.DiffMigration(user, order); err != nil || !m.Empty() {
+		t.Errorf("expect no migration after applying, got %v %v", m, err)
+	}
+}
+
+func TestGenerateMigrationSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "schema.sql"), `
+CREATE SCHEMA billing;
+CREATE TABLE invoices (id bigint PRIMARY KEY, code text, ref text);
+CREATE INDEX idx_invoices_code ON invoices (code);
+CREATE TABLE billing.invoices (id bigint PRIMARY KEY, code text, ref text);
+CREATE INDEX idx_invoices_ref ON billing.invoices (ref);`)
+	db, err := OpenDDL(filepath.Join(dir, "schema.sql"))
+	if err != nil {
+		t.Fatalf("open ddl fail: %s", err)
+	}
+	g := NewGenerator(Config{OutPath: filepath.Join(dir, "query")})
+	g.UseDB(db)
+
+	invoice := g.GenerateModelFrom(migrationObject{table: "billing.invoices", fields: []migrationField{
+		{name: "ID", typ: "int64", gormTag: "column:id;primaryKey"},
+		{name: "Code", typ: "string", gormTag: "column:code;index:idx_invoices_code"},
+		{name: "Ref", typ: "string", gormTag: "column:ref"},
+	}})
+	m, err := g.DiffMigration(invoice)
+	if err != nil {
+		t.Fatalf("diff migration fail: %s", err)
+	}
+	expectUp := []string{
+		`DROP INDEX CONCURRENTLY IF EXISTS "billing"."idx_invoices_ref"`,
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS "idx_invoices_code" ON "billing"."invoices" ("code")`,
+	}
+	if got := strings.Join(m.Up, "\n"); got != strings.Join(expectUp, "\n") {
+		t.Errorf("indexes should be compared with table of the same schema, got up migration:\n%s", got)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read generated file fail: %s", err)
+	}
+	return string(b)
+}
+
+type migrationObject struct {
+	table  string
+	fields []migrationField
+}
+
+func (o migrationObject) TableName() string        { return o.table }
+func (o migrationObject) StructName() string       { return strings.ToUpper(o.table[:1]) + o.table[1:] }
+func (o migrationObject) FileName() string         { return o.table }
+func (o migrationObject) ImportPkgPaths() []string { return nil }
+func (o migrationObject) Fields() []helper.Field {
+	fields := make([]helper.Field, len(o.fields))
+	for i, f := range o.fields {
+		fields[i] = f
+	}
+	return fields
+}
+
+type migrationField struct{ name, typ, gormTag, comment string }
+
+func (f migrationField) Name() string       { return f.name }
+func (f migrationField) Type() string       { return f.typ }
+func (f migrationField) ColumnName() string { return "" }
+func (f migrationField) GORMTag() string    { return f.gormTag }
+func (f migrationField) JSONTag() string    { return "" }
+func (f migrationField) Tag() field.Tag     { return nil }
+func (f migrationField) Comment() string    { return f.comment }
+
 // test data
 type mysqlDialectors struct{ tests.DummyDialector }
 
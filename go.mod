@@ -4,8 +4,11 @@ go 1.18
 
 require (
 	github.com/google/uuid v1.3.0
+	github.com/jinzhu/inflection v1.0.0
+	golang.org/x/mod v0.17.0
 	golang.org/x/tools v0.21.1-0.20240508182429-e35e4ccd0d2d
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
+	gopkg.in/yaml.v3 v3.0.1
+	gorm.io/driver/postgres v1.5.9
 	gorm.io/gorm v1.25.12
 	gorm.io/hints v1.1.0
 	gorm.io/plugin/dbresolver v1.5.3
@@ -13,9 +16,12 @@ require (
 
 require (
 	github.com/go-sql-driver/mysql v1.8.1 // indirect
-	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	github.com/jackc/pgx/v5 v5.5.5 // indirect
+	github.com/jackc/puddle/v2 v2.2.1 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
-	golang.org/x/mod v0.17.0 // indirect
+	golang.org/x/crypto v0.17.0 // indirect
 	golang.org/x/sync v0.10.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	gorm.io/driver/sqlite v1.4.3 // indirect
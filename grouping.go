@@ -0,0 +1,14 @@
+package gen
+
+import "go.ipao.vip/gen/field"
+
+// Rollup ROLLUP (columns) used in Group, e.g. Group(gen.Rollup(o.Region, o.City))
+func Rollup(columns ...field.Expr) field.Expr { return field.Rollup(columns...) }
+
+// Cube CUBE (columns) used in Group
+func Cube(columns ...field.Expr) field.Expr { return field.Cube(columns...) }
+
+// GroupingSets GROUPING SETS (...) used in Group, nil set is the grand total
+//
+//	Group(gen.GroupingSets([]field.Expr{o.Region}, []field.Expr{o.City}, nil))
+func GroupingSets(sets ...[]field.Expr) field.Expr { return field.GroupingSets(sets...) }
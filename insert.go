@@ -0,0 +1,152 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/field"
+)
+
+// InsertSelect INSERT INTO table (columns) SELECT ... builder, created by InsertFrom of DO
+//
+//	a := query.ArchivedOrder
+//	info, err := a.InsertFrom([]field.Expr{a.ID, a.Amount}, o.Select(o.ID, o.Amount).Where(o.CreatedAt.Lt(deadline))).
+//		OnConflict(a.ID).DoNothing().
+//		Exec()
+type InsertSelect struct {
+	do        *DO
+	columns   []clause.Column
+	query     SubQuery
+	conflict  *Upsert // ON CONFLICT, nil when it's not configured
+	returning []clause.Column
+	hasReturn bool
+	err       error
+}
+
+// InsertFrom insert rows selected by q into columns, q must select the same number of columns
+func (d *DO) InsertFrom(columns []field.Expr, q SubQuery) *InsertSelect {
+	s := &InsertSelect{do: d, query: q, columns: make([]clause.Column, len(columns))}
+	for i, column := range columns {
+		s.columns[i] = clause.Column{Name: column.ColumnName().String()}
+	}
+	if n, err := columnCount(q); err != nil {
+		s.err = err
+	} else if n >= 0 && len(columns) > 0 && n != len(columns) {
+		s.err = fmt.Errorf("INSERT has %d target columns but query selects %d columns", len(columns), n)
+	}
+	return s
+}
+
+func (s *InsertSelect) upsert() *Upsert {
+	if s.conflict == nil {
+		s.conflict = &Upsert{do: s.do}
+	}
+	return s.conflict
+}
+
+// OnConflict conflict target columns, conflicting rows are skipped unless DoUpdate is set
+func (s *InsertSelect) OnConflict(columns ...field.Expr) *InsertSelect {
+	s.upsert().OnConflict(columns...)
+	return s
+}
+
+// OnConstraint conflict target by constraint name
+func (s *InsertSelect) OnConstraint(name string) *InsertSelect {
+	s.upsert().OnConstraint(name)
+	return s
+}
+
+// TargetWhere predicate of partial unique index of conflict target
+func (s *InsertSelect) TargetWhere(conds ...Condition) *InsertSelect {
+	s.upsert().TargetWhere(conds...)
+	return s
+}
+
+// DoNothing skip conflicting rows
+func (s *InsertSelect) DoNothing() *InsertSelect {
+	s.upsert().DoNothing()
+	return s
+}
+
+// DoUpdate update conflicting rows, see Upsert.DoUpdate
+func (s *InsertSelect) DoUpdate(columns ...field.AssignExpr) *InsertSelect {
+	s.upsert().DoUpdate(columns...)
+	return s
+}
+
+// Where condition of DO UPDATE
+func (s *InsertSelect) Where(conds ...Condition) *InsertSelect {
+	s.upsert().Where(conds...)
+	return s
+}
+
+// Returning RETURNING columns read by Scan, all columns when columns is empty
+func (s *InsertSelect) Returning(columns ...field.Expr) *InsertSelect {
+	s.hasReturn = true
+	for _, column := range columns {
+		s.returning = append(s.returning, clause.Column{Name: column.ColumnName().String()})
+	}
+	return s
+}
+
+// Exec execute INSERT ... SELECT
+func (s *InsertSelect) Exec() (info ResultInfo, err error) {
+	db := s.statement(func(db *gorm.DB, sql string, vars []interface{}) *gorm.DB { return db.Exec(sql, vars...) })
+	return ResultInfo{RowsAffected: db.RowsAffected, Error: db.Error}, db.Error
+}
+
+// Scan execute INSERT ... SELECT and scan RETURNING rows into dest
+func (s *InsertSelect) Scan(dest interface{}) error {
+	return s.statement(func(db *gorm.DB, sql string, vars []interface{}) *gorm.DB {
+		return db.Raw(sql, vars...).Scan(dest)
+	}).Error
+}
+
+func (s *InsertSelect) statement(exec func(db *gorm.DB, sql string, vars []interface{}) *gorm.DB) *gorm.DB {
+	db := s.do.db.Session(&gorm.Session{NewDB: true})
+	if s.err != nil {
+		_ = db.AddError(s.err)
+		return db
+	}
+	if s.conflict != nil && s.conflict.err != nil {
+		_ = db.AddError(s.conflict.err)
+		return db
+	}
+	if q := s.query.underlyingDB(); q.Error != nil {
+		_ = db.AddError(q.Error)
+		return db
+	}
+
+	sql, vars := s.build()
+	return exec(db, sql, vars)
+}
+
+func (s *InsertSelect) build() (string, []interface{}) {
+	var sql strings.Builder
+	sql.WriteString("INSERT INTO ?")
+	vars := []interface{}{clause.Table{Name: s.do.TableName()}}
+	if len(s.columns) > 0 {
+		sql.WriteString(" ?")
+		vars = append(vars, s.columns)
+	}
+
+	q := s.query.underlyingDB()
+	if _, ok := s.query.(*SetQuery); !ok {
+		q = q.Table(s.query.underlyingDO().TableName())
+	}
+	sql.WriteString(" ?")
+	vars = append(vars, q)
+
+	if s.conflict != nil {
+		sql.WriteString(" ?")
+		vars = append(vars, s.conflict.clauses()[0])
+	}
+	if s.hasReturn {
+		sql.WriteString(" ?")
+		vars = append(vars, clause.Returning{Columns: s.returning})
+	}
+	return sql.String(), vars
+}
@@ -47,10 +47,13 @@ type Dao interface {
 	Where(conds ...Condition) Dao
 	Order(columns ...field.Expr) Dao
 	Distinct(columns ...field.Expr) Dao
+	DistinctOn(columns ...field.Expr) Dao
 	Omit(columns ...field.Expr) Dao
 	Join(table schema.Tabler, conds ...field.Expr) Dao
 	LeftJoin(table schema.Tabler, conds ...field.Expr) Dao
 	RightJoin(table schema.Tabler, conds ...field.Expr) Dao
+	JoinLateral(q SubQuery, alias string, conds ...field.Expr) Dao
+	LeftJoinLateral(q SubQuery, alias string, conds ...field.Expr) Dao
 	Group(columns ...field.Expr) Dao
 	Having(conds ...Condition) Dao
 	Limit(limit int) Dao
@@ -62,6 +65,15 @@ type Dao interface {
 	Joins(field field.RelationField) Dao
 	Preload(field field.RelationField) Dao
 	Clauses(conds ...clause.Expression) Dao
+	With(name string, q SubQuery) Dao
+	WithRecursive(name string, anchor, recursive SubQuery) Dao
+	ForUpdate() Dao
+	ForNoKeyUpdate() Dao
+	ForShare() Dao
+	ForKeyShare() Dao
+	SkipLocked() Dao
+	NoWait() Dao
+	Of(tables ...schema.Tabler) Dao
 
 	Create(value interface{}) error
 	CreateInBatches(value interface{}, batchSize int) error
@@ -80,6 +92,8 @@ type Dao interface {
 	UpdateColumns(values interface{}) (info ResultInfo, err error)
 	UpdateColumnSimple(columns ...field.AssignExpr) (info ResultInfo, err error)
 	Delete(...interface{}) (info ResultInfo, err error)
+	DeleteUsing(tables ...schema.Tabler) Dao
+	InsertFrom(columns []field.Expr, q SubQuery) *InsertSelect
 	Count() (int64, error)
 	Row() *sql.Row
 	Rows() (*sql.Rows, error)
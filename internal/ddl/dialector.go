@@ -0,0 +1,199 @@
+package ddl
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+)
+
+// Dialector postgres dialector serving tables from schema parsed from DDL, no database connection is made
+type Dialector struct {
+	postgres.Dialector
+	Schema *Schema
+}
+
+// Open dialector of schema
+func Open(schema *Schema) *Dialector {
+	return &Dialector{Dialector: postgres.Dialector{Config: &postgres.Config{}}, Schema: schema}
+}
+
+// Initialize nothing to initialize without connection
+func (*Dialector) Initialize(*gorm.DB) error { return nil }
+
+// Migrator migrator reading schema
+func (d *Dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return Migrator{
+		Migrator: migrator.Migrator{Config: migrator.Config{DB: db, Dialector: d}},
+		schema:   d.Schema,
+	}
+}
+
+// Migrator read-only migrator of schema, methods changing database are not supported
+type Migrator struct {
+	migrator.Migrator
+	schema *Schema
+}
+
+// table find table by optionally schema-qualified name
+func (m Migrator) table(value interface{}) *Table {
+	name, _ := value.(string)
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return m.schema.Table(schema, table)
+	}
+	return m.schema.Table("", name)
+}
+
+// CurrentDatabase no database
+func (m Migrator) CurrentDatabase() string { return "" }
+
+// GetTables tables in current schema
+func (m Migrator) GetTables() ([]string, error) { return m.schema.TableNames(""), nil }
+
+// HasTable report whether table exists
+func (m Migrator) HasTable(value interface{}) bool { return m.table(value) != nil }
+
+// TableType table with comment
+func (m Migrator) TableType(value interface{}) (gorm.TableType, error) {
+	t := m.table(value)
+	if t == nil {
+		return nil, nil
+	}
+	tableType := migrator.TableType{SchemaValue: t.Schema, NameValue: t.Name, TypeValue: "BASE TABLE"}
+	if t.Comment != "" {
+		tableType.CommentValue = sql.NullString{String: t.Comment, Valid: true}
+	}
+	return tableType, nil
+}
+
+// ColumnTypes column types as postgres migrator reads them from information_schema
+func (m Migrator) ColumnTypes(value interface{}) ([]gorm.ColumnType, error) {
+	columnTypes := make([]gorm.ColumnType, 0)
+	t := m.table(value)
+	if t == nil {
+		return columnTypes, nil
+	}
+
+	uniques := make(map[string]bool)
+	for _, u := range t.Uniques {
+		if len(u.Columns) == 1 { // only single column unique constraint is reported
+			uniques[u.Columns[0]] = true
+		}
+	}
+	for _, c := range t.Columns {
+		column := &migrator.ColumnType{
+			NameValue:       sql.NullString{String: c.Name, Valid: true},
+			DataTypeValue:   sql.NullString{String: c.Type.DataType(), Valid: true},
+			ColumnTypeValue: sql.NullString{String: c.Type.Format(), Valid: true},
+			NullableValue:   sql.NullBool{Bool: !c.NotNull, Valid: true},
+			PrimaryKeyValue: sql.NullBool{Bool: t.PrimaryKey != nil && contains(t.PrimaryKey.Columns, c.Name), Valid: true},
+			UniqueValue:     sql.NullBool{Bool: uniques[c.Name], Valid: true},
+			ScanTypeValue:   c.Type.ScanType(),
+		}
+		if c.Comment != "" {
+			column.CommentValue = sql.NullString{String: c.Comment, Valid: true}
+		}
+		m.setSize(column, c.Type)
+
+		switch {
+		case c.Identity || strings.HasPrefix(c.Default, "nextval(") && strings.HasSuffix(c.Default, "seq'::regclass)"):
+			column.AutoIncrementValue = sql.NullBool{Bool: true, Valid: true}
+		case c.Default != "" && !strings.EqualFold(c.Default, "null"):
+			column.DefaultValueValue = sql.NullString{String: parseDefaultValue(c.Default), Valid: true}
+		}
+		columnTypes = append(columnTypes, columnType{column})
+	}
+	return columnTypes, nil
+}
+
+// columnType column type without sql.ColumnType, unknown length and decimal size are reported as not ok
+type columnType struct{ *baseColumnType }
+
+type baseColumnType = migrator.ColumnType
+
+func (ct columnType) Length() (int64, bool) {
+	return ct.LengthValue.Int64, ct.LengthValue.Valid
+}
+
+func (ct columnType) DecimalSize() (precision int64, scale int64, ok bool) {
+	return ct.DecimalSizeValue.Int64, ct.ScaleValue.Int64, ct.DecimalSizeValue.Valid
+}
+
+// setSize length, precision and scale as information_schema reports them
+func (m Migrator) setSize(column *migrator.ColumnType, typ Type) {
+	if typ.Array {
+		return
+	}
+	def, ok := typ.def()
+	if !ok {
+		if _, enum := m.schema.Enums[m.schema.Resolve(typ.Schema)+"."+typ.Name]; enum {
+			column.LengthValue = sql.NullInt64{Int64: 32, Valid: true}
+		}
+		return
+	}
+
+	mod := func(i int) (sql.NullInt64, bool) {
+		if i < len(typ.Modifiers) {
+			return sql.NullInt64{Int64: typ.Modifiers[i], Valid: true}, true
+		}
+		return sql.NullInt64{}, false
+	}
+	switch {
+	case def.size > 0:
+		column.LengthValue = sql.NullInt64{Int64: 8 * def.size, Valid: true}
+	case typ.Name == "varchar" || typ.Name == "bpchar" || typ.Name == "bit" || typ.Name == "varbit":
+		column.LengthValue, _ = mod(0)
+	}
+
+	switch {
+	case def.precision > 0:
+		column.DecimalSizeValue = sql.NullInt64{Int64: def.precision, Valid: true}
+		if typ.Name == "int2" || typ.Name == "int4" || typ.Name == "int8" {
+			column.ScaleValue = sql.NullInt64{Valid: true}
+		}
+	case typ.Name == "numeric":
+		if column.DecimalSizeValue, ok = mod(0); ok {
+			column.ScaleValue, _ = mod(1)
+			column.ScaleValue.Valid = true
+		}
+	case typ.Name == "date":
+		column.DecimalSizeValue = sql.NullInt64{Valid: true}
+	case def.datetime:
+		if column.DecimalSizeValue, ok = mod(0); !ok {
+			column.DecimalSizeValue = sql.NullInt64{Int64: 6, Valid: true}
+		}
+	}
+}
+
+var defaultValueRegexp = regexp.MustCompile(`^(.*?)(?:::.*)?$`)
+
+// parseDefaultValue strip type cast and quotes like postgres migrator
+func parseDefaultValue(defaultValue string) string {
+	return strings.Trim(defaultValueRegexp.ReplaceAllString(defaultValue, "$1"), "'")
+}
+
+// GetIndexes indexes created by CREATE INDEX, indexes of primary key and unique constraints are excluded as postgres migrator does.
+// Value is table name optionally qualified with schema, columns are in index key order
+func (m Migrator) GetIndexes(value interface{}) ([]gorm.Index, error) {
+	indexes := make([]gorm.Index, 0)
+	t := m.table(value)
+	if t == nil {
+		return indexes, nil
+	}
+	for _, idx := range t.Indexes {
+		if len(idx.Columns) == 0 {
+			continue
+		}
+		indexes = append(indexes, &migrator.Index{
+			TableName:       t.Name,
+			NameValue:       idx.Name,
+			ColumnList:      append([]string(nil), idx.Columns...), // index key order
+			PrimaryKeyValue: sql.NullBool{Valid: true},
+			UniqueValue:     sql.NullBool{Bool: idx.Unique, Valid: true},
+		})
+	}
+	return indexes, nil
+}
@@ -0,0 +1,400 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenIdent       tokenKind = iota // unquoted identifier or keyword, lower cased
+	tokenQuotedIdent                  // "Quoted" identifier
+	tokenString                       // string literal, unescaped
+	tokenNumber
+	tokenPunct // ( ) , ; . [ ] :: and operators
+)
+
+type token struct {
+	kind       tokenKind
+	text       string
+	start, end int // offset in source
+}
+
+// is reports whether token is keyword or punct s
+func (t token) is(s string) bool {
+	return (t.kind == tokenIdent || t.kind == tokenPunct) && t.text == s
+}
+
+// tokenize split sql source into tokens, comments and psql meta-commands are skipped
+func tokenize(src string) (tokens []token, err error) {
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case c == '\\' && (i == 0 || src[i-1] == '\n'): // psql meta-command, e.g. \connect
+			i = lineEnd(src, i)
+		case strings.HasPrefix(src[i:], "--"):
+			i = lineEnd(src, i)
+		case strings.HasPrefix(src[i:], "/*"):
+			end, err := blockCommentEnd(src, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case c == '\'' || ((c == 'E' || c == 'e') && i+1 < len(src) && src[i+1] == '\''):
+			t, err := scanString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i = t.end
+		case c == '"':
+			t, err := scanQuoted(src, i, '"', tokenQuotedIdent, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i = t.end
+		case c == '$' && dollarTag(src, i) != "":
+			tag := dollarTag(src, i)
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string at offset %d", i)
+			}
+			end += i + len(tag)
+			tokens = append(tokens, token{kind: tokenString, text: src[i+len(tag) : end], start: i, end: end + len(tag)})
+			i = end + len(tag)
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(src) && isIdentPart(src[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(src[i:j]), start: i, end: j})
+			i = j
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9'):
+			j := i + 1
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' || src[j] == 'e' || src[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:j], start: i, end: j})
+			i = j
+		case strings.HasPrefix(src[i:], "::"):
+			tokens = append(tokens, token{kind: tokenPunct, text: "::", start: i, end: i + 2})
+			i += 2
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c), start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func lineEnd(src string, i int) int {
+	if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+		return i + end + 1
+	}
+	return len(src)
+}
+
+// blockCommentEnd end of nested block comment
+func blockCommentEnd(src string, i int) (int, error) {
+	depth := 0
+	for j := i; j+1 < len(src); j++ {
+		switch src[j : j+2] {
+		case "/*":
+			depth++
+			j++
+		case "*/":
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated comment at offset %d", i)
+}
+
+func scanString(src string, i int) (token, error) {
+	escape := src[i] != '\''
+	if escape {
+		i++
+	}
+	t, err := scanQuoted(src, i, '\'', tokenString, escape)
+	if err != nil || !escape {
+		return t, err
+	}
+	t.start--
+	t.text = unescapeString(t.text)
+	return t, nil
+}
+
+// scanQuoted scan quoted text, quote is escaped by doubling it, or by backslash if escape
+func scanQuoted(src string, i int, quote byte, kind tokenKind, escape bool) (token, error) {
+	var sb strings.Builder
+	for j := i + 1; j < len(src); j++ {
+		if src[j] == quote {
+			if j+1 < len(src) && src[j+1] == quote {
+				sb.WriteByte(quote)
+				j++
+				continue
+			}
+			return token{kind: kind, text: sb.String(), start: i, end: j + 1}, nil
+		}
+		if escape && src[j] == '\\' && j+1 < len(src) {
+			sb.WriteByte(src[j]) // keep escape for unescapeString, skip escaped quote
+			j++
+		}
+		sb.WriteByte(src[j])
+	}
+	return token{}, fmt.Errorf("unterminated quoted text at offset %d", i)
+}
+
+// unescapeString unescape common backslash escapes of escape string constant
+func unescapeString(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\r`, "\r", `\'`, "'", `\\`, `\`).Replace(s)
+}
+
+// dollarTag tag of dollar-quoted string start at i, e.g. $$ or $body$, empty if not
+func dollarTag(src string, i int) string {
+	for j := i + 1; j < len(src); j++ {
+		switch c := src[j]; {
+		case c == '$':
+			return src[i : j+1]
+		case !isIdentPart(c) || c >= '0' && c <= '9' && j == i+1:
+			return ""
+		}
+	}
+	return ""
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9' || c == '$'
+}
+
+// cursor token reader of one statement or element
+type cursor struct {
+	src    string
+	tokens []token
+	i      int
+}
+
+func (c *cursor) done() bool { return c.i >= len(c.tokens) }
+
+func (c *cursor) peek() token { return c.peekAt(0) }
+
+// peekAt token n after current one, empty token at end
+func (c *cursor) peekAt(n int) token {
+	if c.i+n >= len(c.tokens) {
+		return token{kind: tokenPunct}
+	}
+	return c.tokens[c.i+n]
+}
+
+func (c *cursor) next() token {
+	t := c.peek()
+	if !c.done() {
+		c.i++
+	}
+	return t
+}
+
+func (c *cursor) prev() token {
+	if c.i == 0 {
+		return token{kind: tokenPunct}
+	}
+	return c.tokens[c.i-1]
+}
+
+// accept consume words if all of them follow in order
+func (c *cursor) accept(words ...string) bool {
+	for i, w := range words {
+		if !c.peekAt(i).is(w) {
+			return false
+		}
+	}
+	c.i += len(words)
+	return true
+}
+
+// peekAny report whether next token is one of words
+func (c *cursor) peekAny(words ...string) bool {
+	for _, w := range words {
+		if c.peek().is(w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cursor) peekIdentifier() (string, error) {
+	t := c.peek()
+	if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+		return "", fmt.Errorf("identifier expected, got %q", c.src[t.start:t.end])
+	}
+	return t.text, nil
+}
+
+func (c *cursor) identifier() (string, error) {
+	name, err := c.peekIdentifier()
+	if err == nil {
+		c.next()
+	}
+	return name, err
+}
+
+// parseName parse optionally schema-qualified name
+func (c *cursor) parseName() (schema, name string, err error) {
+	if name, err = c.identifier(); err != nil {
+		return "", "", err
+	}
+	if c.accept(".") {
+		schema = name
+		name, err = c.identifier()
+	}
+	return schema, name, err
+}
+
+// renamePair parse "from TO to"
+func (c *cursor) renamePair() (from, to string, err error) {
+	if from, err = c.identifier(); err != nil {
+		return
+	}
+	if !c.accept("to") {
+		return "", "", fmt.Errorf("TO expected after %s", from)
+	}
+	to, err = c.identifier()
+	return
+}
+
+// identList parse parenthesized identifier list
+func (c *cursor) identList() (names []string, err error) {
+	elems, err := c.group()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range elems {
+		name, err := e.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// group consume parenthesized list, elements are split by comma
+func (c *cursor) group() ([]*cursor, error) {
+	if !c.peek().is("(") {
+		return nil, fmt.Errorf("( expected, got %q", c.src[c.peek().start:c.peek().end])
+	}
+	start := c.i + 1
+	depth := 0
+	for !c.done() {
+		switch t := c.next(); {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			if depth--; depth == 0 {
+				inner := &cursor{src: c.src, tokens: c.tokens[start : c.i-1]}
+				return inner.split(), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unclosed (")
+}
+
+// split consume rest tokens, split by top-level comma
+func (c *cursor) split() (elems []*cursor) {
+	start, depth := c.i, 0
+	for ; !c.done(); c.i++ {
+		switch t := c.peek(); {
+		case t.is("(") || t.is("["):
+			depth++
+		case t.is(")") || t.is("]"):
+			depth--
+		case t.is(",") && depth == 0:
+			elems = append(elems, &cursor{src: c.src, tokens: c.tokens[start:c.i]})
+			start = c.i + 1
+		}
+	}
+	if start < c.i {
+		elems = append(elems, &cursor{src: c.src, tokens: c.tokens[start:c.i]})
+	}
+	return elems
+}
+
+// skipUntil consume tokens through the top-level punct s
+func (c *cursor) skipUntil(s string) {
+	depth := 0
+	for !c.done() {
+		t := c.next()
+		switch {
+		case t.is(s) && depth == 0:
+			return
+		case t.is("(") || t.is("["):
+			depth++
+		case t.is(")") || t.is("]"):
+			depth--
+		}
+	}
+}
+
+// expr consume expression until one of top-level stop keywords, return expression text as PostgreSQL prints it
+func (c *cursor) expr(stops map[string]bool) string {
+	start, depth := c.i, 0
+	for !c.done() {
+		t := c.peek()
+		if depth == 0 && c.i > start && t.kind == tokenIdent && stops[t.text] {
+			break
+		}
+		switch {
+		case t.is("(") || t.is("["):
+			depth++
+		case t.is(")") || t.is("]"):
+			depth--
+		}
+		c.next()
+	}
+
+	var sb strings.Builder
+	for i := start; i < c.i; i++ {
+		t := c.tokens[i]
+		if i > start {
+			sb.WriteString(c.src[c.tokens[i-1].end:t.start])
+		}
+		switch {
+		case t.kind == tokenIdent && sqlValueFunctions[t.text]:
+			sb.WriteString(strings.ToUpper(t.text))
+		case t.kind == tokenIdent:
+			sb.WriteString(t.text)
+		default:
+			sb.WriteString(c.src[t.start:t.end])
+		}
+	}
+	return sb.String()
+}
+
+// sqlValueFunctions functions printed in upper case by PostgreSQL
+var sqlValueFunctions = map[string]bool{
+	"current_date": true, "current_time": true, "current_timestamp": true, "localtime": true, "localtimestamp": true,
+	"current_role": true, "current_user": true, "session_user": true, "user": true, "current_catalog": true, "current_schema": true,
+}
+
+// indexColumn column of index element, false for expression
+func (c *cursor) indexColumn() (string, bool) {
+	name, err := c.identifier()
+	if err != nil {
+		return "", false
+	}
+	if next := c.peek(); !c.done() && next.kind != tokenIdent && next.kind != tokenQuotedIdent {
+		return "", false // function call, operator, etc.
+	}
+	return name, true
+}
@@ -0,0 +1,77 @@
+package ddl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	for _, tc := range []struct {
+		src  string
+		want string // tokens separated by space, kind prefixed: "q" quoted identifier, "s" string, "n" number
+	}{
+		{src: `CREATE TABLE Users (Id INT);`, want: `create table users ( id int ) ;`},
+		{src: `"Order Items"."Weird""Name"`, want: `q:Order Items . q:Weird"Name`},
+		{src: `'it''s' E'a\'b\n' e'\\'`, want: "s:it's s:a'b\n s:\\"},
+		{src: `$$ body 'x' $$ $fn$ a $$ b $fn$`, want: `s: body 'x'  s: a $$ b `},
+		{src: `$1 price$ 1.5e3 .5`, want: `$ n:1 price$ n:1.5e3 n:.5`},
+		{src: `int[] x::text[3] a.b`, want: `int [ ] x :: text [ n:3 ] a . b`},
+		{src: "a -- comment\n/* block /* nested */ */ b", want: `a b`},
+		{src: "\\connect db\nSELECT 1", want: `select n:1`},
+		{src: `now() >= 'x'`, want: `now ( ) > = s:x`},
+	} {
+		tokens, err := tokenize(tc.src)
+		if err != nil {
+			t.Errorf("tokenize %q: %s", tc.src, err)
+			continue
+		}
+		got := make([]string, len(tokens))
+		for i, tok := range tokens {
+			switch tok.kind {
+			case tokenQuotedIdent:
+				got[i] = "q:" + tok.text
+			case tokenString:
+				got[i] = "s:" + tok.text
+			case tokenNumber:
+				got[i] = "n:" + tok.text
+			default:
+				got[i] = tok.text
+			}
+		}
+		if strings.Join(got, " ") != tc.want {
+			t.Errorf("tokenize %q: expect %q, got %q", tc.src, tc.want, strings.Join(got, " "))
+		}
+	}
+
+	for _, src := range []string{`'open`, `"open`, `$$ open`, `/* open`, `E'\'`} {
+		if _, err := tokenize(src); err == nil {
+			t.Errorf("tokenize %q: expect error", src)
+		}
+	}
+}
+
+func TestCursorExpr(t *testing.T) {
+	for _, tc := range []struct {
+		src, want, rest string
+	}{
+		{src: `now() NOT NULL`, want: `now()`, rest: `not`},
+		{src: `'{a,b}'::text[] NOT NULL`, want: `'{a,b}'::text[]`, rest: `not`},
+		{src: `current_timestamp`, want: `CURRENT_TIMESTAMP`},
+		{src: `(1 + 2) * 3 CHECK (x > 0)`, want: `(1 + 2) * 3`, rest: `check`},
+		{src: `$$ q $$`, want: `$$ q $$`},
+	} {
+		tokens, err := tokenize(tc.src)
+		if err != nil {
+			t.Fatalf("tokenize %q: %s", tc.src, err)
+		}
+		c := &cursor{src: tc.src, tokens: tokens}
+		got := c.expr(columnStops)
+		var rest string
+		if !c.done() {
+			rest = c.peek().text
+		}
+		if got != tc.want || rest != tc.rest {
+			t.Errorf("expr %q: expect %q before %q, got %q before %q", tc.src, tc.want, tc.rest, got, rest)
+		}
+	}
+}
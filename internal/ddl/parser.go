@@ -0,0 +1,689 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse apply DDL statements to schema, statements not about tables, indexes, comments and enum types are ignored
+func (s *Schema) Parse(src string) error {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return err
+	}
+
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && !tokens[i].is(";") {
+			continue
+		}
+		if i > start {
+			c := &cursor{src: src, tokens: tokens[start:i]}
+			if err := s.exec(c); err != nil {
+				return fmt.Errorf("line %d: %w", strings.Count(src[:tokens[start].start], "\n")+1, err)
+			}
+		}
+		start = i + 1
+	}
+	return nil
+}
+
+// UpSection cut down migration of goose or dbmate, which put up and down migration in one file
+func UpSection(src string) string {
+	for _, marker := range []string{"-- +goose Down", "-- migrate:down"} {
+		if i := strings.Index(src, marker); i >= 0 {
+			src = src[:i]
+		}
+	}
+	return src
+}
+
+func (s *Schema) exec(c *cursor) error {
+	switch {
+	case c.accept("create"):
+		c.accept("or", "replace")
+		switch {
+		case c.accept("unique", "index"):
+			return s.createIndex(c, true)
+		case c.accept("index"):
+			return s.createIndex(c, false)
+		case c.accept("type"):
+			return s.createType(c)
+		}
+		for c.peek().kind == tokenIdent && tableKinds[c.peek().text] {
+			c.next()
+		}
+		if c.accept("table") {
+			return s.createTable(c)
+		}
+	case c.accept("alter", "table"):
+		return s.alterTable(c)
+	case c.accept("alter", "index"):
+		return s.alterIndex(c)
+	case c.accept("alter", "type"):
+		return s.alterType(c)
+	case c.accept("drop", "table"):
+		return s.dropTables(c)
+	case c.accept("drop", "index"):
+		return s.dropIndexes(c)
+	case c.accept("drop", "type"):
+		return s.dropTypes(c)
+	case c.accept("comment", "on"):
+		return s.comment(c)
+	case c.accept("set", "search_path"):
+		if c.accept("to") || c.accept("=") {
+			for _, name := range c.split() {
+				if schema, err := name.identifier(); err == nil && schema != "$user" {
+					s.search = schema
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+var tableKinds = map[string]bool{"global": true, "local": true, "temp": true, "temporary": true, "unlogged": true}
+
+// columnStops keywords ending default expression of column definition
+var columnStops = map[string]bool{
+	"constraint": true, "not": true, "null": true, "primary": true, "unique": true,
+	"references": true, "check": true, "collate": true, "generated": true,
+}
+
+func (s *Schema) createTable(c *cursor) error {
+	ifNotExists := c.accept("if", "not", "exists")
+	schema, name, err := c.parseName()
+	if err != nil {
+		return err
+	}
+	if !c.peek().is("(") { // PARTITION OF, OF type or AS query
+		return nil
+	}
+	if s.Table(schema, name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("table %s already exists", name)
+	}
+
+	t := &Table{Schema: s.Resolve(schema), Name: name}
+	elems, err := c.group()
+	if err != nil {
+		return err
+	}
+	for _, e := range elems {
+		if err := s.tableElement(t, e); err != nil {
+			return err
+		}
+	}
+	s.Tables[t.Schema+"."+t.Name] = t
+	return nil
+}
+
+func (s *Schema) tableElement(t *Table, e *cursor) error {
+	switch {
+	case e.accept("like"):
+		schema, name, err := e.parseName()
+		if err != nil {
+			return err
+		}
+		if src := s.Table(schema, name); src != nil {
+			for _, col := range src.Columns {
+				copied := *col
+				copied.Default, copied.Identity, copied.Comment = "", false, "" // INCLUDING options are not supported
+				t.Columns = append(t.Columns, &copied)
+			}
+		}
+		return nil
+	case e.peekAny("constraint", "primary", "unique", "foreign", "check", "exclude"):
+		return s.tableConstraint(t, e)
+	default:
+		return s.columnDef(t, e)
+	}
+}
+
+func (s *Schema) columnDef(t *Table, e *cursor) error {
+	name, err := e.identifier()
+	if err != nil {
+		return err
+	}
+	typ, err := e.parseType()
+	if err != nil {
+		return fmt.Errorf("column %s: %w", name, err)
+	}
+
+	col := &Column{Name: name, Type: typ}
+	if typ.Serial {
+		col.NotNull, col.Default = true, fmt.Sprintf("nextval('%s_%s_seq'::regclass)", t.Name, name)
+	}
+
+	var constraint string
+	for !e.done() {
+		switch {
+		case e.accept("constraint"):
+			if constraint, err = e.identifier(); err != nil {
+				return err
+			}
+			continue
+		case e.accept("not", "null"):
+			col.NotNull = true
+		case e.accept("null"):
+		case e.accept("default"):
+			col.Default = e.expr(columnStops)
+		case e.accept("primary", "key"):
+			t.PrimaryKey = &Constraint{Name: or(constraint, t.Name+"_pkey"), Columns: []string{name}}
+			col.NotNull = true
+		case e.accept("unique"):
+			e.accept("nulls", "not", "distinct")
+			e.accept("nulls", "distinct")
+			t.Uniques = append(t.Uniques, &Constraint{Name: or(constraint, t.constraintName([]string{name}, "key")), Columns: []string{name}})
+		case e.accept("references"):
+			if err = s.references(t, e, constraint, []string{name}); err != nil {
+				return err
+			}
+		case e.accept("check"):
+			if _, err = e.group(); err != nil {
+				return err
+			}
+			e.accept("no", "inherit")
+		case e.accept("collate"):
+			if _, _, err = e.parseName(); err != nil {
+				return err
+			}
+		case e.accept("generated"):
+			if e.accept("always", "as", "identity") || e.accept("by", "default", "as", "identity") {
+				col.Identity, col.NotNull = true, true
+				if e.peek().is("(") {
+					_, err = e.group()
+				}
+			} else { // generated column has no default
+				e.accept("always")
+				e.accept("as")
+				_, err = e.group()
+				e.accept("stored")
+			}
+			if err != nil {
+				return err
+			}
+		default: // DEFERRABLE, STORAGE, COMPRESSION, etc.
+			e.next()
+		}
+		constraint = ""
+	}
+
+	if t.Column(name) != nil {
+		return fmt.Errorf("column %s of table %s already exists", name, t.Name)
+	}
+	t.Columns = append(t.Columns, col)
+	return nil
+}
+
+func (s *Schema) tableConstraint(t *Table, e *cursor) (err error) {
+	var name string
+	if e.accept("constraint") {
+		if name, err = e.identifier(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case e.accept("primary", "key"):
+		columns, err := e.identList()
+		if err != nil {
+			return err
+		}
+		t.PrimaryKey = &Constraint{Name: or(name, t.Name+"_pkey"), Columns: columns}
+		for _, column := range columns {
+			if col := t.Column(column); col != nil {
+				col.NotNull = true
+			}
+		}
+	case e.accept("unique"):
+		e.accept("nulls", "not", "distinct")
+		e.accept("nulls", "distinct")
+		columns, err := e.identList()
+		if err != nil {
+			return err
+		}
+		t.Uniques = append(t.Uniques, &Constraint{Name: or(name, t.constraintName(columns, "key")), Columns: columns})
+	case e.accept("foreign", "key"):
+		columns, err := e.identList()
+		if err != nil {
+			return err
+		}
+		if !e.accept("references") {
+			return fmt.Errorf("REFERENCES expected in foreign key of table %s", t.Name)
+		}
+		return s.references(t, e, name, columns)
+	}
+	return nil // CHECK and EXCLUDE constraints
+}
+
+// references parse referenced table and columns of foreign key
+func (s *Schema) references(t *Table, e *cursor, name string, columns []string) (err error) {
+	fk := &ForeignKey{Name: or(name, t.constraintName(columns, "fkey")), Columns: columns}
+	if fk.RefSchema, fk.RefTable, err = e.parseName(); err != nil {
+		return err
+	}
+	fk.RefSchema = s.Resolve(fk.RefSchema)
+	if e.peek().is("(") {
+		if fk.RefColumns, err = e.identList(); err != nil {
+			return err
+		}
+	}
+	for {
+		switch {
+		case e.accept("match"):
+			e.next()
+			continue
+		case e.accept("on", "delete"), e.accept("on", "update"):
+			if e.accept("set") { // SET NULL or SET DEFAULT, with optional columns
+				e.next()
+				if e.peek().is("(") {
+					_, err = e.group()
+				}
+			} else if !e.accept("no", "action") {
+				e.next()
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		break
+	}
+	t.ForeignKeys = append(t.ForeignKeys, fk)
+	return nil
+}
+
+func (s *Schema) alterTable(c *cursor) error {
+	c.accept("if", "exists")
+	c.accept("only")
+	schema, name, err := c.parseName()
+	if err != nil {
+		return err
+	}
+	c.accept("*")
+
+	t := s.Table(schema, name)
+	if t == nil { // e.g. owner of sequence or view
+		return nil
+	}
+	for _, action := range c.split() {
+		if err := s.alterTableAction(t, action); err != nil {
+			return fmt.Errorf("alter table %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) alterTableAction(t *Table, a *cursor) error {
+	switch {
+	case a.accept("add"):
+		if a.peekAny("constraint", "primary", "unique", "foreign", "check", "exclude") {
+			return s.tableConstraint(t, a)
+		}
+		a.accept("column")
+		if a.accept("if", "not", "exists") {
+			if name, err := a.peekIdentifier(); err == nil && t.Column(name) != nil {
+				return nil
+			}
+		}
+		return s.columnDef(t, a)
+	case a.accept("drop"):
+		if a.accept("constraint") {
+			a.accept("if", "exists")
+			name, err := a.identifier()
+			if err != nil {
+				return err
+			}
+			t.dropConstraint(name)
+			return nil
+		}
+		a.accept("column")
+		a.accept("if", "exists")
+		name, err := a.identifier()
+		if err != nil {
+			return err
+		}
+		t.dropColumn(name)
+	case a.accept("alter"):
+		a.accept("column")
+		name, err := a.identifier()
+		if err != nil {
+			return err
+		}
+		col := t.Column(name)
+		if col == nil {
+			return fmt.Errorf("column %s not found", name)
+		}
+		switch {
+		case a.accept("type"), a.accept("set", "data", "type"):
+			col.Type, err = a.parseType()
+		case a.accept("set", "default"):
+			col.Default = a.expr(nil)
+		case a.accept("drop", "default"):
+			col.Default = ""
+		case a.accept("set", "not", "null"):
+			col.NotNull = true
+		case a.accept("drop", "not", "null"):
+			col.NotNull = false
+		case a.accept("add", "generated"):
+			col.Identity, col.NotNull = true, true
+		case a.accept("drop", "identity"):
+			col.Identity = false
+		}
+		return err
+	case a.accept("rename"):
+		switch {
+		case a.accept("to"):
+			name, err := a.identifier()
+			if err != nil {
+				return err
+			}
+			s.renameTable(t, t.Schema, name)
+		case a.accept("constraint"):
+			from, to, err := a.renamePair()
+			if err != nil {
+				return err
+			}
+			t.renameConstraint(from, to)
+		default:
+			a.accept("column")
+			from, to, err := a.renamePair()
+			if err != nil {
+				return err
+			}
+			s.renameColumn(t, from, to)
+		}
+	case a.accept("set", "schema"):
+		schema, err := a.identifier()
+		if err != nil {
+			return err
+		}
+		s.renameTable(t, schema, t.Name)
+	}
+	return nil // OWNER TO, ENABLE TRIGGER, etc.
+}
+
+func (s *Schema) createIndex(c *cursor, unique bool) error {
+	c.accept("concurrently")
+	ifNotExists := c.accept("if", "not", "exists")
+	var name string
+	if !c.peek().is("on") {
+		var err error
+		if name, err = c.identifier(); err != nil {
+			return err
+		}
+	}
+	if !c.accept("on") {
+		return fmt.Errorf("ON expected in index %s", name)
+	}
+	c.accept("only")
+	schema, tableName, err := c.parseName()
+	if err != nil {
+		return err
+	}
+	t := s.Table(schema, tableName)
+	if t == nil { // e.g. index of materialized view
+		return nil
+	}
+	if c.accept("using") {
+		c.next()
+	}
+
+	idx := &Index{Name: name, Unique: unique}
+	elems, err := c.group()
+	if err != nil {
+		return err
+	}
+	for _, e := range elems {
+		if column, ok := e.indexColumn(); ok {
+			idx.Columns = append(idx.Columns, column)
+		} else {
+			idx.Exprs++
+		}
+	}
+	if c.accept("include") { // included columns are reported as index columns too
+		include, err := c.identList()
+		if err != nil {
+			return err
+		}
+		idx.Columns = append(idx.Columns, include...)
+	}
+	for !c.done() {
+		if c.next().is("where") {
+			idx.Partial = true
+			break
+		}
+	}
+
+	if idx.Name == "" {
+		idx.Name = t.constraintName(idx.Columns, "idx")
+	}
+	if other, _ := s.findIndex(t.Schema, idx.Name); other != nil {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("index %s already exists", idx.Name)
+	}
+	t.Indexes = append(t.Indexes, idx)
+	return nil
+}
+
+func (s *Schema) alterIndex(c *cursor) error {
+	c.accept("if", "exists")
+	schema, name, err := c.parseName()
+	if err != nil {
+		return err
+	}
+	if !c.accept("rename", "to") {
+		return nil
+	}
+	to, err := c.identifier()
+	if err != nil {
+		return err
+	}
+	if t, i := s.findIndex(schema, name); t != nil {
+		t.Indexes[i].Name = to
+	}
+	return nil
+}
+
+func (s *Schema) dropIndexes(c *cursor) error {
+	c.accept("concurrently")
+	c.accept("if", "exists")
+	for _, e := range c.split() {
+		schema, name, err := e.parseName()
+		if err != nil {
+			return err
+		}
+		if t, i := s.findIndex(schema, name); t != nil {
+			t.Indexes = append(t.Indexes[:i], t.Indexes[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) dropTables(c *cursor) error {
+	c.accept("if", "exists")
+	for _, e := range c.split() {
+		schema, name, err := e.parseName()
+		if err != nil {
+			return err
+		}
+		schema = s.Resolve(schema)
+		delete(s.Tables, schema+"."+name)
+		for _, t := range s.Tables { // foreign keys are dropped with CASCADE
+			fks := t.ForeignKeys[:0]
+			for _, fk := range t.ForeignKeys {
+				if fk.RefSchema != schema || fk.RefTable != name {
+					fks = append(fks, fk)
+				}
+			}
+			t.ForeignKeys = fks
+		}
+	}
+	return nil
+}
+
+func (s *Schema) createType(c *cursor) error {
+	schema, name, err := c.parseName()
+	if err != nil {
+		return err
+	}
+	if !c.accept("as", "enum") {
+		return nil // composite, range and base types
+	}
+	elems, err := c.group()
+	if err != nil {
+		return err
+	}
+	labels := make([]string, 0, len(elems))
+	for _, e := range elems {
+		if t := e.next(); t.kind == tokenString {
+			labels = append(labels, t.text)
+		}
+	}
+	s.Enums[s.Resolve(schema)+"."+name] = labels
+	return nil
+}
+
+func (s *Schema) alterType(c *cursor) error {
+	schema, name, err := c.parseName()
+	if err != nil {
+		return err
+	}
+	key := s.Resolve(schema) + "." + name
+	labels, ok := s.Enums[key]
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case c.accept("add", "value"):
+		c.accept("if", "not", "exists")
+		label := c.next()
+		if contains(labels, label.text) {
+			return nil
+		}
+		pos := len(labels)
+		if before := c.accept("before"); before || c.accept("after") {
+			for i, l := range labels {
+				if l == c.peek().text {
+					if pos = i; !before {
+						pos++
+					}
+				}
+			}
+		}
+		labels = append(labels[:pos], append([]string{label.text}, labels[pos:]...)...)
+	case c.accept("rename", "value"):
+		from, to := c.next(), c.peekAt(1)
+		replace(labels, from.text, to.text)
+	case c.accept("rename", "to"):
+		to, err := c.identifier()
+		if err != nil {
+			return err
+		}
+		delete(s.Enums, key)
+		s.Enums[s.Resolve(schema)+"."+to] = labels
+		for _, t := range s.Tables {
+			for _, col := range t.Columns {
+				if col.Type.Name == name && s.Resolve(col.Type.Schema) == s.Resolve(schema) {
+					col.Type.Name = to
+				}
+			}
+		}
+		return nil
+	}
+	s.Enums[key] = labels
+	return nil
+}
+
+func (s *Schema) dropTypes(c *cursor) error {
+	c.accept("if", "exists")
+	for _, e := range c.split() {
+		schema, name, err := e.parseName()
+		if err != nil {
+			return err
+		}
+		delete(s.Enums, s.Resolve(schema)+"."+name)
+	}
+	return nil
+}
+
+// comment COMMENT ON TABLE or COLUMN, other objects are ignored
+func (s *Schema) comment(c *cursor) error {
+	column := c.accept("column")
+	if !column && !c.accept("table") {
+		return nil
+	}
+	var names []string
+	for {
+		name, err := c.identifier()
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		if !c.accept(".") {
+			break
+		}
+	}
+	if !c.accept("is") {
+		return fmt.Errorf("IS expected in comment on %s", strings.Join(names, "."))
+	}
+	var text string
+	if t := c.next(); t.kind == tokenString {
+		text = t.text
+	}
+
+	var schema, table, col string
+	switch {
+	case column && len(names) == 2:
+		table, col = names[0], names[1]
+	case column && len(names) == 3:
+		schema, table, col = names[0], names[1], names[2]
+	case !column && len(names) == 1:
+		table = names[0]
+	case !column && len(names) == 2:
+		schema, table = names[0], names[1]
+	default:
+		return fmt.Errorf("invalid comment target %s", strings.Join(names, "."))
+	}
+
+	t := s.Table(schema, table)
+	switch {
+	case t == nil: // e.g. comment on view
+	case !column:
+		t.Comment = text
+	case t.Column(col) != nil:
+		t.Column(col).Comment = text
+	}
+	return nil
+}
+
+// renameConstraint rename primary key, unique or foreign key constraint
+func (t *Table) renameConstraint(from, to string) {
+	if t.PrimaryKey != nil && t.PrimaryKey.Name == from {
+		t.PrimaryKey.Name = to
+	}
+	for _, u := range t.Uniques {
+		if u.Name == from {
+			u.Name = to
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		if fk.Name == from {
+			fk.Name = to
+		}
+	}
+}
+
+func or(name, defaultName string) string {
+	if name != "" {
+		return name
+	}
+	return defaultName
+}
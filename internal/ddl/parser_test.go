@@ -0,0 +1,171 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// describe table in lines of columns, constraints and indexes, for comparison in tests
+func describe(t *Table) string {
+	var lines []string
+	if t.Comment != "" {
+		lines = append(lines, "comment "+t.Comment)
+	}
+	for _, c := range t.Columns {
+		line := c.Name + " " + c.Type.Format()
+		if c.NotNull {
+			line += " not null"
+		}
+		if c.Default != "" {
+			line += " default " + c.Default
+		}
+		if c.Identity {
+			line += " identity"
+		}
+		if c.Comment != "" {
+			line += " comment " + c.Comment
+		}
+		lines = append(lines, line)
+	}
+	if t.PrimaryKey != nil {
+		lines = append(lines, fmt.Sprintf("pk %s %v", t.PrimaryKey.Name, t.PrimaryKey.Columns))
+	}
+	for _, u := range t.Uniques {
+		lines = append(lines, fmt.Sprintf("unique %s %v", u.Name, u.Columns))
+	}
+	for _, fk := range t.ForeignKeys {
+		lines = append(lines, fmt.Sprintf("fk %s %v %s.%s%v", fk.Name, fk.Columns, fk.RefSchema, fk.RefTable, fk.RefColumns))
+	}
+	for _, idx := range t.Indexes {
+		lines = append(lines, fmt.Sprintf("index %s %v unique=%v partial=%v exprs=%d", idx.Name, idx.Columns, idx.Unique, idx.Partial, idx.Exprs))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestSchema_Parse(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		sql   string
+		table string // qualified table name
+		want  string
+	}{
+		{
+			name:  "quoting",
+			sql:   `CREATE TABLE "Order Items" ("Id" bigint PRIMARY KEY, "select" text, Note TEXT);`,
+			table: "public.Order Items",
+			want: `Id bigint not null
+select text
+note text
+pk Order Items_pkey [Id]`,
+		},
+		{
+			name:  "types and arrays",
+			sql:   `CREATE TABLE t (a varchar(20)[], b int4[][], c numeric(10), d timestamp(3) with time zone, e double precision, f public.mood[], g billing.kind, h serial);`,
+			table: "public.t",
+			want: `a character varying(20)[]
+b integer[]
+c numeric(10,0)
+d timestamp(3) with time zone
+e double precision
+f mood[]
+g billing.kind
+h integer not null default nextval('t_h_seq'::regclass)`,
+		},
+		{
+			name: "defaults",
+			sql: `CREATE TABLE t (
+	a timestamptz NOT NULL DEFAULT now(),
+	b text DEFAULT 'x''y' NOT NULL,
+	c text[] DEFAULT '{}'::text[],
+	d int DEFAULT (1 + 2) CHECK (d > 0),
+	e date DEFAULT CURRENT_DATE,
+	f bigint GENERATED ALWAYS AS IDENTITY (START WITH 10),
+	g int GENERATED ALWAYS AS (d * 2) STORED
+);`,
+			table: "public.t",
+			want: `a timestamp with time zone not null default now()
+b text not null default 'x''y'
+c text[] default '{}'::text[]
+d integer default (1 + 2)
+e date default CURRENT_DATE
+f bigint not null identity
+g integer`,
+		},
+		{
+			name: "dollar quoted bodies",
+			sql: `CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN NEW.updated_at = now(); CREATE TABLE fake (id int); RETURN NEW; END
+$$ LANGUAGE plpgsql;
+CREATE TABLE t (id int, body text DEFAULT $tag$it's;$tag$);
+DO $do$ BEGIN CREATE TABLE other (id int); END $do$;`,
+			table: "public.t",
+			want: `id integer
+body text default $tag$it's;$tag$`,
+		},
+		{
+			name: "comment on",
+			sql: `CREATE SCHEMA billing;
+CREATE TABLE billing.invoices (id int, code text);
+COMMENT ON TABLE billing.invoices IS 'Invoice list';
+COMMENT ON COLUMN billing.invoices.code IS E'code\nof invoice';
+COMMENT ON COLUMN billing.invoices.id IS NULL;
+COMMENT ON VIEW missing IS 'ignored';`,
+			table: "billing.invoices",
+			want: `comment Invoice list
+id integer
+code text comment code
+of invoice`,
+		},
+		{
+			name: "alter table add constraint",
+			sql: `CREATE TABLE users (id bigint, email text);
+CREATE TABLE orders (id bigint, user_id bigint, code text, total numeric);
+ALTER TABLE ONLY orders ADD CONSTRAINT orders_pk PRIMARY KEY (id);
+ALTER TABLE orders ADD CONSTRAINT orders_user_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL (user_id),
+	ADD UNIQUE (code), ADD CONSTRAINT positive CHECK (total > 0);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS code text, ADD COLUMN note text DEFAULT '';
+CREATE INDEX orders_user_idx ON orders USING btree (user_id, lower(code)) WHERE total > 0;`,
+			table: "public.orders",
+			want: `id bigint not null
+user_id bigint
+code text
+total numeric
+note text default ''
+pk orders_pk [id]
+unique orders_code_key [code]
+fk orders_user_fk [user_id] public.users[id]
+index orders_user_idx [user_id] unique=false partial=true exprs=1`,
+		},
+	} {
+		s := NewSchema()
+		if err := s.Parse(tc.sql); err != nil {
+			t.Errorf("%s: parse fail: %s", tc.name, err)
+			continue
+		}
+		table := s.Tables[tc.table]
+		if table == nil {
+			t.Errorf("%s: table %s not found in %v", tc.name, tc.table, s.TableNames(""))
+			continue
+		}
+		if got := describe(table); got != tc.want {
+			t.Errorf("%s: expect\n%s\ngot\n%s", tc.name, tc.want, got)
+		}
+		if tc.name == "dollar quoted bodies" && (s.Tables["public.fake"] != nil || s.Tables["public.other"] != nil) {
+			t.Errorf("%s: statements in dollar quoted bodies should not be parsed", tc.name)
+		}
+	}
+}
+
+func TestSchema_ParseError(t *testing.T) {
+	for _, sql := range []string{
+		`CREATE TABLE t (id int, id text);`,
+		`CREATE TABLE t (id int); COMMENT ON COLUMN t.id 'x';`,
+		`CREATE TABLE t (id int); ALTER TABLE t ADD FOREIGN KEY (id) users (id);`,
+		`CREATE TABLE t (body text DEFAULT $$open);`,
+	} {
+		if err := NewSchema().Parse(sql); err == nil {
+			t.Errorf("parse %q: expect error", sql)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package ddl
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultSchema schema of unqualified names
+const DefaultSchema = "public"
+
+// Schema tables and enum types defined by DDL
+type Schema struct {
+	Tables map[string]*Table   // keyed by qualified name, e.g. public.users
+	Enums  map[string][]string // enum labels keyed by qualified type name, e.g. public.mood
+	search string              // schema of unqualified names, set by SET search_path
+}
+
+// Table table defined by DDL
+type Table struct {
+	Schema      string
+	Name        string
+	Comment     string
+	Columns     []*Column
+	Indexes     []*Index // indexes created by CREATE INDEX
+	PrimaryKey  *Constraint
+	Uniques     []*Constraint
+	ForeignKeys []*ForeignKey
+}
+
+// Column table column
+type Column struct {
+	Name     string
+	Type     Type
+	NotNull  bool
+	Default  string // default expression, empty if no default
+	Identity bool   // GENERATED AS IDENTITY
+	Comment  string
+}
+
+// Index index created by CREATE INDEX
+type Index struct {
+	Name    string
+	Unique  bool
+	Partial bool     // has WHERE predicate
+	Columns []string // plain columns, expressions are omitted
+	Exprs   int      // count of expression elements
+}
+
+// Constraint primary key or unique constraint
+type Constraint struct {
+	Name    string
+	Columns []string
+}
+
+// ForeignKey foreign key constraint
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefSchema  string
+	RefTable   string
+	RefColumns []string // empty for primary key of RefTable
+}
+
+// NewSchema empty schema
+func NewSchema() *Schema {
+	return &Schema{Tables: make(map[string]*Table), Enums: make(map[string][]string)}
+}
+
+// Resolve schema of name, search path is used when schemaName is empty
+func (s *Schema) Resolve(schemaName string) string {
+	switch {
+	case schemaName != "":
+		return schemaName
+	case s.search != "":
+		return s.search
+	default:
+		return DefaultSchema
+	}
+}
+
+// Table find table, schemaName can be empty
+func (s *Schema) Table(schemaName, name string) *Table {
+	return s.Tables[s.Resolve(schemaName)+"."+name]
+}
+
+// TableNames names of tables in schema, sorted
+func (s *Schema) TableNames(schemaName string) (names []string) {
+	schemaName = s.Resolve(schemaName)
+	for _, t := range s.Tables {
+		if t.Schema == schemaName {
+			names = append(names, t.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Column find column by name
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (t *Table) columnIndex(name string) int {
+	for i, c := range t.Columns {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// constraintName default constraint name generated by PostgreSQL, e.g. users_email_key
+func (t *Table) constraintName(columns []string, suffix string) string {
+	return strings.Join(append(append([]string{t.Name}, columns...), suffix), "_")
+}
+
+// dropConstraint remove constraint by name, report whether found
+func (t *Table) dropConstraint(name string) bool {
+	if t.PrimaryKey != nil && t.PrimaryKey.Name == name {
+		t.PrimaryKey = nil
+		return true
+	}
+	for i, u := range t.Uniques {
+		if u.Name == name {
+			t.Uniques = append(t.Uniques[:i], t.Uniques[i+1:]...)
+			return true
+		}
+	}
+	for i, fk := range t.ForeignKeys {
+		if fk.Name == name {
+			t.ForeignKeys = append(t.ForeignKeys[:i], t.ForeignKeys[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// dropColumn remove column with indexes and constraints using it
+func (t *Table) dropColumn(name string) {
+	i := t.columnIndex(name)
+	if i < 0 {
+		return
+	}
+	t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+
+	indexes := t.Indexes[:0]
+	for _, idx := range t.Indexes {
+		if !contains(idx.Columns, name) {
+			indexes = append(indexes, idx)
+		}
+	}
+	t.Indexes = indexes
+	if t.PrimaryKey != nil && contains(t.PrimaryKey.Columns, name) {
+		t.PrimaryKey = nil
+	}
+	uniques := t.Uniques[:0]
+	for _, u := range t.Uniques {
+		if !contains(u.Columns, name) {
+			uniques = append(uniques, u)
+		}
+	}
+	t.Uniques = uniques
+	fks := t.ForeignKeys[:0]
+	for _, fk := range t.ForeignKeys {
+		if !contains(fk.Columns, name) {
+			fks = append(fks, fk)
+		}
+	}
+	t.ForeignKeys = fks
+}
+
+// renameColumn rename column in table and in constraints referencing it
+func (s *Schema) renameColumn(t *Table, from, to string) {
+	if c := t.Column(from); c != nil {
+		c.Name = to
+	}
+	for _, idx := range t.Indexes {
+		replace(idx.Columns, from, to)
+	}
+	if t.PrimaryKey != nil {
+		replace(t.PrimaryKey.Columns, from, to)
+	}
+	for _, u := range t.Uniques {
+		replace(u.Columns, from, to)
+	}
+	for _, fk := range t.ForeignKeys {
+		replace(fk.Columns, from, to)
+	}
+	for _, other := range s.Tables {
+		for _, fk := range other.ForeignKeys {
+			if fk.RefSchema == t.Schema && fk.RefTable == t.Name {
+				replace(fk.RefColumns, from, to)
+			}
+		}
+	}
+}
+
+// renameTable rename or move table, foreign keys referencing it are updated
+func (s *Schema) renameTable(t *Table, schemaName, name string) {
+	for _, other := range s.Tables {
+		for _, fk := range other.ForeignKeys {
+			if fk.RefSchema == t.Schema && fk.RefTable == t.Name {
+				fk.RefSchema, fk.RefTable = schemaName, name
+			}
+		}
+	}
+	delete(s.Tables, t.Schema+"."+t.Name)
+	t.Schema, t.Name = schemaName, name
+	s.Tables[schemaName+"."+name] = t
+}
+
+// findIndex find table of index, indexes share namespace with tables in schema
+func (s *Schema) findIndex(schemaName, name string) (*Table, int) {
+	schemaName = s.Resolve(schemaName)
+	for _, t := range s.Tables {
+		if t.Schema != schemaName {
+			continue
+		}
+		for i, idx := range t.Indexes {
+			if idx.Name == name {
+				return t, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// RefColumns referenced columns of foreign key, primary key of referenced table if not specified
+func (s *Schema) RefColumns(fk *ForeignKey) []string {
+	if len(fk.RefColumns) > 0 {
+		return fk.RefColumns
+	}
+	if ref := s.Tables[fk.RefSchema+"."+fk.RefTable]; ref != nil && ref.PrimaryKey != nil {
+		return ref.PrimaryKey.Columns
+	}
+	return nil
+}
+
+// UniqueColumns report whether columns are exactly covered by primary key, unique constraint or unique index
+func (t *Table) UniqueColumns(columns []string) bool {
+	if t.PrimaryKey != nil && sameSet(t.PrimaryKey.Columns, columns) {
+		return true
+	}
+	for _, u := range t.Uniques {
+		if sameSet(u.Columns, columns) {
+			return true
+		}
+	}
+	for _, idx := range t.Indexes {
+		if idx.Unique && !idx.Partial && idx.Exprs == 0 && sameSet(idx.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func replace(list []string, from, to string) {
+	for i, v := range list {
+		if v == from {
+			list[i] = to
+		}
+	}
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range a {
+		if !contains(b, v) {
+			return false
+		}
+	}
+	return true
+}
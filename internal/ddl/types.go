@@ -0,0 +1,284 @@
+package ddl
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type column data type, normalized as PostgreSQL reports it
+type Type struct {
+	Name      string  // type name as pg_type.typname, e.g. int4, varchar, mood
+	Schema    string  // schema of user defined type, empty for builtin types
+	Modifiers []int64 // type modifiers, e.g. 255 of varchar(255)
+	Array     bool
+	Serial    bool // serial types, default to nextval of sequence
+}
+
+// typeDef builtin type info
+type typeDef struct {
+	format    string       // format_type() name
+	size      int64        // pg_type.typlen, 0 for variable length
+	precision int64        // information_schema numeric_precision of integer and float types
+	datetime  bool         // has information_schema datetime_precision
+	scan      reflect.Type // scan type of pgx stdlib driver
+}
+
+var (
+	int16Type   = reflect.TypeOf(int16(0))
+	int32Type   = reflect.TypeOf(int32(0))
+	int64Type   = reflect.TypeOf(int64(0))
+	float32Type = reflect.TypeOf(float32(0))
+	float64Type = reflect.TypeOf(float64(0))
+	boolType    = reflect.TypeOf(false)
+	timeType    = reflect.TypeOf(time.Time{})
+	bytesType   = reflect.TypeOf([]byte(nil))
+	stringType  = reflect.TypeOf("")
+)
+
+var builtinTypes = map[string]typeDef{
+	"int2":        {format: "smallint", size: 2, precision: 16, scan: int16Type},
+	"int4":        {format: "integer", size: 4, precision: 32, scan: int32Type},
+	"int8":        {format: "bigint", size: 8, precision: 64, scan: int64Type},
+	"float4":      {format: "real", size: 4, precision: 24, scan: float32Type},
+	"float8":      {format: "double precision", size: 8, precision: 53, scan: float64Type},
+	"numeric":     {format: "numeric", scan: float64Type},
+	"bool":        {format: "boolean", size: 1, scan: boolType},
+	"text":        {format: "text"},
+	"varchar":     {format: "character varying"},
+	"bpchar":      {format: "character"},
+	"bytea":       {format: "bytea", scan: bytesType},
+	"date":        {format: "date", size: 4, datetime: true, scan: timeType},
+	"timestamp":   {format: "timestamp without time zone", size: 8, datetime: true, scan: timeType},
+	"timestamptz": {format: "timestamp with time zone", size: 8, datetime: true, scan: timeType},
+	"time":        {format: "time without time zone", size: 8, datetime: true},
+	"timetz":      {format: "time with time zone", size: 12, datetime: true},
+	"interval":    {format: "interval", size: 16, datetime: true},
+	"uuid":        {format: "uuid", size: 16},
+	"json":        {format: "json"},
+	"jsonb":       {format: "jsonb"},
+	"xml":         {format: "xml"},
+	"inet":        {format: "inet"},
+	"cidr":        {format: "cidr"},
+	"macaddr":     {format: "macaddr", size: 6},
+	"macaddr8":    {format: "macaddr8", size: 8},
+	"money":       {format: "money", size: 8},
+	"bit":         {format: "bit"},
+	"varbit":      {format: "bit varying"},
+	"oid":         {format: "oid", size: 4},
+	"tsvector":    {format: "tsvector"},
+	"tsquery":     {format: "tsquery"},
+	"point":       {format: "point", size: 16},
+	"line":        {format: "line", size: 24},
+	"lseg":        {format: "lseg", size: 32},
+	"box":         {format: "box", size: 32},
+	"path":        {format: "path"},
+	"polygon":     {format: "polygon"},
+	"circle":      {format: "circle", size: 24},
+	"int4range":   {format: "int4range"},
+	"int8range":   {format: "int8range"},
+	"numrange":    {format: "numrange"},
+	"tsrange":     {format: "tsrange"},
+	"tstzrange":   {format: "tstzrange"},
+	"daterange":   {format: "daterange"},
+}
+
+// typeAliases SQL type names of builtin types
+var typeAliases = map[string]string{
+	"smallint":                    "int2",
+	"integer":                     "int4",
+	"int":                         "int4",
+	"bigint":                      "int8",
+	"real":                        "float4",
+	"double precision":            "float8",
+	"decimal":                     "numeric",
+	"boolean":                     "bool",
+	"character varying":           "varchar",
+	"character":                   "bpchar",
+	"char":                        "bpchar",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"bit varying":                 "varbit",
+}
+
+var serialTypes = map[string]string{
+	"smallserial": "int2",
+	"serial2":     "int2",
+	"serial":      "int4",
+	"serial4":     "int4",
+	"bigserial":   "int8",
+	"serial8":     "int8",
+}
+
+// ParseType parse SQL type name, e.g. varchar(255), bigserial, timestamptz(3)
+func ParseType(s string) (Type, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return Type{}, err
+	}
+	c := &cursor{src: s, tokens: tokens}
+	typ, err := c.parseType()
+	if err != nil {
+		return typ, err
+	}
+	if !c.done() {
+		return typ, fmt.Errorf("unexpected %q in type %s", c.peek().text, s)
+	}
+	return typ, nil
+}
+
+// parseType parse type name, e.g. double precision, varchar(255), timestamp(3) with time zone, text[]
+func (c *cursor) parseType() (typ Type, err error) {
+	schema, name, err := c.parseName()
+	if err != nil {
+		return typ, err
+	}
+	quoted := c.prev().kind == tokenQuotedIdent
+
+	if !quoted {
+		switch name {
+		case "double":
+			if c.accept("precision") {
+				name = "double precision"
+			}
+		case "character", "char", "bit":
+			if c.accept("varying") {
+				name += " varying"
+			}
+		}
+	}
+
+	if c.peek().is("(") {
+		c.next()
+		for !c.done() && !c.peek().is(")") {
+			if t := c.next(); t.kind == tokenNumber {
+				n, err := strconv.ParseInt(t.text, 10, 64)
+				if err != nil {
+					return typ, fmt.Errorf("invalid type modifier %q of %s", t.text, name)
+				}
+				typ.Modifiers = append(typ.Modifiers, n)
+			}
+		}
+		if !c.accept(")") {
+			return typ, fmt.Errorf("unclosed type modifier of %s", name)
+		}
+	}
+
+	if !quoted && (name == "timestamp" || name == "time") {
+		switch {
+		case c.accept("with", "time", "zone"):
+			name += " with time zone"
+		case c.accept("without", "time", "zone"):
+			name += " without time zone"
+		}
+	}
+	if !quoted && name == "interval" { // interval fields, e.g. interval day to second
+		for c.peek().kind == tokenIdent && intervalFields[c.peek().text] {
+			c.next()
+		}
+	}
+
+	for {
+		switch {
+		case c.accept("["):
+			c.skipUntil("]")
+			typ.Array = true
+			continue
+		case c.accept("array"):
+			typ.Array = true
+			continue
+		}
+		break
+	}
+
+	typ.Name, typ.Schema = name, schema
+	if quoted {
+		return typ, nil
+	}
+	if base, ok := serialTypes[name]; ok && schema == "" {
+		typ.Name, typ.Serial = base, true
+	}
+	if base, ok := typeAliases[typ.Name]; ok {
+		typ.Name = base
+	}
+	if _, ok := builtinTypes[typ.Name]; ok && (schema == "" || schema == "pg_catalog") {
+		typ.Schema = ""
+	}
+	switch {
+	case typ.Name == "float" && schema == "":
+		typ.Name = "float8"
+		if len(typ.Modifiers) == 1 && typ.Modifiers[0] <= 24 {
+			typ.Name = "float4"
+		}
+		typ.Modifiers = nil
+	case (name == "character" || name == "char" || name == "bit") && len(typ.Modifiers) == 0:
+		typ.Modifiers = []int64{1}
+	}
+	return typ, nil
+}
+
+var intervalFields = map[string]bool{"year": true, "month": true, "day": true, "hour": true, "minute": true, "second": true, "to": true}
+
+func (t Type) def() (typeDef, bool) {
+	if t.Schema != "" {
+		return typeDef{}, false
+	}
+	def, ok := builtinTypes[t.Name]
+	return def, ok
+}
+
+// Format type name as format_type() reports it, e.g. character varying(255), integer[]
+func (t Type) Format() string {
+	def, ok := t.def()
+	if !ok {
+		name := t.Name
+		if t.Schema != "" && t.Schema != DefaultSchema {
+			name = t.Schema + "." + name
+		}
+		return t.arraySuffix(name)
+	}
+
+	mods := make([]string, len(t.Modifiers))
+	for i, m := range t.Modifiers {
+		mods[i] = strconv.FormatInt(m, 10)
+	}
+	if t.Name == "numeric" && len(mods) == 1 {
+		mods = append(mods, "0")
+	}
+	if len(mods) == 0 {
+		return t.arraySuffix(def.format)
+	}
+
+	format := def.format + "(" + strings.Join(mods, ",") + ")"
+	if before, after, ok := strings.Cut(def.format, " with"); ok { // timestamp(3) with time zone
+		format = before + "(" + strings.Join(mods, ",") + ") with" + after
+	}
+	return t.arraySuffix(format)
+}
+
+func (t Type) arraySuffix(format string) string {
+	if t.Array {
+		return format + "[]"
+	}
+	return format
+}
+
+// DataType type name as gorm postgres migrator reports it, udt name or format_type() of arrays
+func (t Type) DataType() string {
+	if t.Array {
+		return t.Format()
+	}
+	return t.Name
+}
+
+// ScanType scan type of pgx stdlib driver
+func (t Type) ScanType() reflect.Type {
+	if def, ok := t.def(); ok && !t.Array && def.scan != nil {
+		return def.scan
+	}
+	return stringType
+}
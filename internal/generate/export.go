@@ -31,27 +31,81 @@ func GetQueryStructMeta(db *gorm.DB, conf *model.Config) (*QueryStructMeta, erro
 		return nil, fmt.Errorf("model name %q is invalid: %w", structName, err)
 	}
 
-	columns, err := getTableColumns(db, conf.GetSchemaName(db), tableName, conf.FieldWithIndexTag)
+	schemaName := conf.GetSchemaName(db)
+	columns, err := getTableColumns(db, schemaName, tableName, conf.FieldWithIndexTag)
 	if err != nil {
 		return nil, err
 	}
 
+	enumNameNS := conf.EnumNameNS
+	if enumNameNS == nil {
+		enumNameNS = db.NamingStrategy.SchemaName
+	}
+	enums := getTableEnums(db, schemaName, tableName, enumNameNS)
+
+	tableComment := getTableComment(db, qualifiedName(schemaName, tableName))
+	if conf.SchemaName != "" { // schema-qualified table name
+		tableName = qualifiedName(conf.SchemaName, tableName)
+	}
+
 	return (&QueryStructMeta{
 		db:              db,
 		Source:          model.Table,
 		Generated:       true,
 		FileName:        fileName,
 		TableName:       tableName,
-		TableComment:    getTableComment(db, tableName),
+		TableComment:    tableComment,
+		Schema:          conf.SchemaName,
 		ModelStructName: structName,
 		QueryStructName: uncaptialize(structName),
 		S:               strings.ToLower(structName[0:1]),
 		StructInfo:      parser.Param{Type: structName, Package: conf.ModelPkg},
 		ImportPkgPaths:  conf.ImportPkgPaths,
-		Fields:          getFields(db, conf, columns),
+		Fields:          getFields(db, conf, columns, enums),
+		Enums:           enums,
 	}).addMethodFromAddMethodOpt(conf.GetModelMethods()...), nil
 }
 
+// GetViewQueryStructMeta generate read-only db model by view or materialized view name
+func GetViewQueryStructMeta(db *gorm.DB, conf *model.Config) (*QueryStructMeta, error) {
+	meta, err := GetQueryStructMeta(db, conf)
+	if err != nil || meta == nil {
+		return meta, err
+	}
+
+	views, err := getTableInfo(db).GetViews(conf.GetSchemaName(db))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if qualifiedName(meta.Schema, v.Name) == meta.TableName {
+			meta.View = v
+			return meta, nil
+		}
+	}
+	return nil, fmt.Errorf("view [%s] not found", meta.TableName)
+}
+
+// GetViews get views and materialized views in db
+func GetViews(db *gorm.DB, conf *model.Config) ([]*model.View, error) {
+	return getTableInfo(db).GetViews(conf.GetSchemaName(db))
+}
+
+// GetTables get tables in db
+func GetTables(db *gorm.DB, conf *model.Config) ([]string, error) {
+	return getTableInfo(db).GetTables(conf.GetSchemaName(db))
+}
+
+// GetIndexes get indexes of table in db, primary key and unique constraints are excluded
+func GetIndexes(db *gorm.DB, conf *model.Config) ([]gorm.Index, error) {
+	return getTableInfo(db).GetTableIndex(conf.GetSchemaName(db), conf.TableName)
+}
+
+// GetForeignKeys get foreign keys between tables in db
+func GetForeignKeys(db *gorm.DB, conf *model.Config) ([]*model.ForeignKey, error) {
+	return getTableInfo(db).GetForeignKeys(conf.GetSchemaName(db))
+}
+
 // GetQueryStructMetaFromObject generate base struct from object
 func GetQueryStructMetaFromObject(obj helper.Object, conf *model.Config) (*QueryStructMeta, error) {
 	err := helper.CheckObject(obj)
@@ -16,12 +16,18 @@ import (
 ** Provided by @qqxhb
  */
 
-func getFields(db *gorm.DB, conf *model.Config, columns []*model.Column) (fields []*model.Field) {
+func getFields(db *gorm.DB, conf *model.Config, columns []*model.Column, enums []*model.Enum) (fields []*model.Field) {
+	dataTypeMap := withEnumDataType(conf.DataTypeMap, enums)
 	for _, col := range columns {
-		col.SetDataTypeMap(conf.DataTypeMap)
+		col.SetDataTypeMap(dataTypeMap)
 		col.WithNS(conf.FieldJSONTagNS)
 
 		m := col.ToField(conf.FieldNullable, conf.FieldCoverable, conf.FieldSignable)
+		for _, e := range enums {
+			if e.Name == col.DatabaseTypeName() && strings.TrimLeft(m.Type, "*") == e.TypeName {
+				m.EnumType = e.TypeName
+			}
+		}
 
 		// Prefer precise field wrapper type based on database type name when generating query fields.
 		// This enables strongly-typed helpers like field.Money, field.Inet, field.JSONB, etc.
@@ -69,6 +75,32 @@ func getFields(db *gorm.DB, conf *model.Config, columns []*model.Column) (fields
 	return fields
 }
 
+// withEnumDataType map enum columns and enum array columns to generated enum types,
+// mappings specified by user take precedence
+func withEnumDataType(
+	dataTypeMap map[string]func(columnType gorm.ColumnType) (dataType string),
+	enums []*model.Enum,
+) map[string]func(columnType gorm.ColumnType) (dataType string) {
+	if len(enums) == 0 {
+		return dataTypeMap
+	}
+
+	m := make(map[string]func(columnType gorm.ColumnType) (dataType string), len(dataTypeMap)+2*len(enums))
+	for k, v := range dataTypeMap {
+		m[k] = v
+	}
+	for _, e := range enums {
+		name, typeName, arrayType := strings.ToLower(e.Name), e.TypeName, e.ArrayType()
+		if _, ok := m[name]; !ok {
+			m[name] = func(gorm.ColumnType) string { return typeName }
+		}
+		if _, ok := m[name+"[]"]; !ok {
+			m[name+"[]"] = func(gorm.ColumnType) string { return arrayType }
+		}
+	}
+	return m
+}
+
 // fieldWrapperForDBType maps PostgreSQL column types to field wrapper names.
 func fieldWrapperForDBType(dbType string) string {
 	switch strings.ToLower(dbType) {
@@ -81,6 +113,14 @@ func fieldWrapperForDBType(dbType string) string {
 		return "XML"
 	case "money":
 		return "Money"
+	case "numeric", "decimal":
+		return "Decimal"
+	case "interval":
+		return "Interval"
+	case "date":
+		return "Date"
+	case "time":
+		return "TimeOfDay"
 
 	// Network
 	case "inet":
@@ -125,6 +165,14 @@ func fieldWrapperForDBType(dbType string) string {
 		return "TSVector"
 	case "tsquery":
 		return "TSQuery"
+
+	// Extensions
+	case "hstore":
+		return "HStore"
+	case "ltree":
+		return "LTree"
+	case "citext":
+		return "CIText"
 	}
 	return ""
 }
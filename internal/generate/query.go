@@ -30,13 +30,16 @@ type QueryStructMeta struct {
 	S               string // the first letter(lower case)of simple Name (receiver)
 	QueryStructName string // internal query struct name
 	ModelStructName string // origin/model struct name
-	TableName       string // table name in db server
+	TableName       string // table name in db server, qualified with schema if Schema is set
+	Schema          string // schema name when generated for specified schema
 	TableComment    string // table comment in db server
 	StructInfo      parser.Param
 	Fields          []*model.Field
 	Source          model.SourceCode
 	ImportPkgPaths  []string
 	ModelMethods    []*parser.Method // user custom method bind to db base struct
+	Enums           []*model.Enum    // enum types used by table columns
+	View            *model.View      // not nil when generated from view or materialized view
 
 	interfaceMode bool
 
@@ -47,6 +50,10 @@ type QueryStructMeta struct {
 	// Usually the model struct name (e.g., "User"). When generating queries in the same
 	// package as models, this can be set to "UserQuery" to avoid name conflicts.
 	TopName string
+	// SchemaGroup is the field name of the schema group in Query (e.g., "Billing"), and GroupField
+	// is the field name of this struct inside the group. Empty when not grouped by schema.
+	SchemaGroup string
+	GroupField  string
 }
 
 // PrimaryField returns the single primary key field if present (and only one), otherwise nil.
@@ -145,6 +152,20 @@ func (b *QueryStructMeta) PrimaryFieldGenType() string {
     return ""
 }
 
+// QueryField path of the query struct in Query, e.g. "User" or "Billing.Invoice"
+func (b *QueryStructMeta) QueryField() string {
+	if b.SchemaGroup != "" {
+		return b.SchemaGroup + "." + b.GroupField
+	}
+	return b.ModelStructName
+}
+
+// ReadOnly reports whether the model is mapped from view, no write method is generated for it
+func (b *QueryStructMeta) ReadOnly() bool { return b.View != nil }
+
+// Materialized reports whether the model is mapped from materialized view
+func (b *QueryStructMeta) Materialized() bool { return b.View != nil && b.View.Materialized }
+
 // parseStruct get all elements of struct with gorm's Parse, ignore unexported elements
 func (b *QueryStructMeta) parseStruct(st interface{}) error {
 	stmt := gorm.Statement{DB: b.db}
@@ -267,6 +288,12 @@ func (b *QueryStructMeta) StructComment() string {
 	if b.TableComment != "" {
 		return b.TableComment
 	}
+	if b.View != nil && b.View.Materialized {
+		return fmt.Sprintf(`mapped from materialized view <%s>`, b.TableName)
+	}
+	if b.View != nil {
+		return fmt.Sprintf(`mapped from view <%s>`, b.TableName)
+	}
 	if b.TableName != "" {
 		return fmt.Sprintf(`mapped from table <%s>`, b.TableName)
 	}
@@ -2,10 +2,15 @@ package generate
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"reflect"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
 
+	"go.ipao.vip/gen/internal/ddl"
 	"go.ipao.vip/gen/internal/model"
 )
 
@@ -14,12 +19,40 @@ type ITableInfo interface {
 	GetTableColumns(schemaName, tableName string) (result []*model.Column, err error)
 
 	GetTableIndex(schemaName, tableName string) (indexes []gorm.Index, err error)
+
+	GetEnumLabels(schemaName, tableName string) (labels []model.EnumLabel, err error)
+
+	GetViews(schemaName string) (views []*model.View, err error)
+
+	GetTables(schemaName string) (tables []string, err error)
+
+	GetForeignKeys(schemaName string) (fks []*model.ForeignKey, err error)
 }
 
 func getTableInfo(db *gorm.DB) ITableInfo {
+	if d, ok := db.Dialector.(*ddl.Dialector); ok {
+		return &ddlTableInfo{tableInfo{db}, d.Schema}
+	}
 	return &tableInfo{db}
 }
 
+// qualifiedName table name qualified with schema, e.g. billing.invoices
+func qualifiedName(schemaName, tableName string) string {
+	if schemaName == "" {
+		return tableName
+	}
+	return schemaName + "." + tableName
+}
+
+// unqualifiedTypeName drop schema of type name, e.g. extensions.ltree[] is ltree[],
+// types of extensions (hstore, ltree, citext) are qualified when their schema is not in search_path
+func unqualifiedTypeName(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
 func getTableComment(db *gorm.DB, tableName string) string {
 	table, err := getTableType(db, tableName)
 	if err != nil || table == nil {
@@ -72,18 +105,180 @@ type tableInfo struct{ *gorm.DB }
 
 // GetTableColumns  struct
 func (t *tableInfo) GetTableColumns(schemaName, tableName string) (result []*model.Column, err error) {
-	types, err := t.Migrator().ColumnTypes(tableName)
+	types, err := t.Migrator().ColumnTypes(qualifiedName(schemaName, tableName))
 	if err != nil {
 		return nil, err
 	}
+	if len(types) == 0 { // materialized views are absent from information_schema
+		types, err = t.getRelationColumns(schemaName, tableName)
+		if err != nil {
+			return nil, err
+		}
+	}
 	for _, column := range types {
+		if c, ok := column.(*migrator.ColumnType); ok {
+			c.DataTypeValue.String = unqualifiedTypeName(c.DataTypeValue.String)
+		}
 		// Postgres-only: always use scan type
 		result = append(result, &model.Column{ColumnType: column, TableName: tableName, UseScanType: true})
 	}
 	return result, nil
 }
 
-// GetTableIndex  index
+// GetTableIndex indexes of table in schema, current schema is used when schemaName is empty.
+// Indexes of primary key and unique constraints are excluded as postgres migrator does,
+// columns are in index key order and expression elements are omitted.
 func (t *tableInfo) GetTableIndex(schemaName, tableName string) (indexes []gorm.Index, err error) {
-	return t.Migrator().GetIndexes(tableName)
+	var rows []struct {
+		IndexName  string
+		IsUnique   bool
+		ColumnName string
+	}
+	err = t.Raw(`SELECT ci.relname AS index_name, i.indisunique AS is_unique, a.attname AS column_name
+FROM pg_index i
+	JOIN pg_class ct ON ct.oid = i.indrelid
+	JOIN pg_namespace n ON n.oid = ct.relnamespace
+	JOIN pg_class ci ON ci.oid = i.indexrelid
+	JOIN unnest(i.indkey::int2[]) WITH ORDINALITY k(attnum, ord) ON true
+	JOIN pg_attribute a ON a.attrelid = ct.oid AND a.attnum = k.attnum
+WHERE ct.relname = ? AND n.nspname = COALESCE(NULLIF(?, ''), CURRENT_SCHEMA())
+	AND NOT EXISTS (SELECT 1 FROM pg_constraint con
+		WHERE con.conrelid = ct.oid AND con.conindid = i.indexrelid AND con.contype IN ('p', 'u', 'x'))
+ORDER BY ci.relname, k.ord`, tableName, schemaName).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*migrator.Index)
+	for _, r := range rows {
+		idx := byName[r.IndexName]
+		if idx == nil {
+			idx = &migrator.Index{
+				TableName:       tableName,
+				NameValue:       r.IndexName,
+				PrimaryKeyValue: sql.NullBool{Valid: true},
+				UniqueValue:     sql.NullBool{Bool: r.IsUnique, Valid: true},
+			}
+			byName[r.IndexName] = idx
+			indexes = append(indexes, idx)
+		}
+		idx.ColumnList = append(idx.ColumnList, r.ColumnName)
+	}
+	return indexes, nil
+}
+
+// GetTables tables in schema, current schema is used when schemaName is empty
+func (t *tableInfo) GetTables(schemaName string) (tables []string, err error) {
+	err = t.Table("information_schema.tables").
+		Where("table_schema = COALESCE(NULLIF(?, ''), CURRENT_SCHEMA()) AND table_type = 'BASE TABLE'", schemaName).
+		Order("table_name").
+		Pluck("table_name", &tables).Error
+	return tables, err
+}
+
+// GetForeignKeys foreign keys of tables in schema, current schema is used when schemaName is empty.
+// Referenced tables may be in other schemas, which is reported by RefSchemaName
+func (t *tableInfo) GetForeignKeys(schemaName string) (fks []*model.ForeignKey, err error) {
+	err = t.Raw(`SELECT c.conname AS name, cl.relname AS table_name, rcl.relname AS ref_table_name,
+	CASE WHEN rn.nspname = n.nspname THEN '' ELSE rn.nspname END AS ref_schema_name,
+	array_to_string(ARRAY(SELECT a.attname FROM unnest(c.conkey) WITH ORDINALITY k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum ORDER BY k.ord), ',') AS columns,
+	array_to_string(ARRAY(SELECT a.attname FROM unnest(c.confkey) WITH ORDINALITY k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.attnum ORDER BY k.ord), ',') AS ref_columns,
+	EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c.conrelid AND i.indisunique AND i.indpred IS NULL
+		AND i.indkey::int2[] @> c.conkey AND i.indkey::int2[] <@ c.conkey) AS is_unique,
+	(SELECT count(*) FROM pg_attribute a WHERE a.attrelid = c.conrelid AND a.attnum > 0 AND NOT a.attisdropped) AS table_columns
+FROM pg_constraint c
+	JOIN pg_class cl ON cl.oid = c.conrelid
+	JOIN pg_namespace n ON n.oid = cl.relnamespace
+	JOIN pg_class rcl ON rcl.oid = c.confrelid
+	JOIN pg_namespace rn ON rn.oid = rcl.relnamespace
+WHERE c.contype = 'f' AND n.nspname = COALESCE(NULLIF(?, ''), CURRENT_SCHEMA())
+ORDER BY cl.relname, c.conname`, schemaName).Scan(&fks).Error
+	return fks, err
+}
+
+// GetViews views and materialized views in schema, current schema is used when schemaName is empty
+func (t *tableInfo) GetViews(schemaName string) (views []*model.View, err error) {
+	err = t.Table("pg_class c").
+		Select("c.relname, c.relkind = 'm' AS materialized").
+		Joins("JOIN pg_namespace n ON n.oid = c.relnamespace").
+		Where("c.relkind IN ('v', 'm') AND n.nspname = COALESCE(NULLIF(?, ''), CURRENT_SCHEMA())", schemaName).
+		Order("c.relname").
+		Scan(&views).Error
+	return views, err
+}
+
+// getRelationColumns read columns from pg_attribute, work for any relation kind
+func (t *tableInfo) getRelationColumns(schemaName, tableName string) (result []gorm.ColumnType, err error) {
+	var columns []struct {
+		Name       string
+		DataType   string
+		ColumnType string
+		Nullable   bool
+		Comment    sql.NullString
+	}
+	err = t.Raw(`SELECT a.attname AS name,
+	CASE WHEN ty.typcategory = 'A' THEN format_type(a.atttypid, NULL) ELSE ty.typname END AS data_type,
+	format_type(a.atttypid, a.atttypmod) AS column_type,
+	NOT a.attnotnull AS nullable,
+	col_description(a.attrelid, a.attnum) AS comment
+FROM pg_attribute a JOIN pg_type ty ON ty.oid = a.atttypid
+WHERE a.attrelid = to_regclass(?) AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`, t.regclass(schemaName, tableName)).Scan(&columns).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range columns {
+		result = append(result, &migrator.ColumnType{
+			NameValue:       sql.NullString{String: c.Name, Valid: true},
+			DataTypeValue:   sql.NullString{String: unqualifiedTypeName(c.DataType), Valid: true},
+			ColumnTypeValue: sql.NullString{String: c.ColumnType, Valid: true},
+			NullableValue:   sql.NullBool{Bool: c.Nullable, Valid: true},
+			CommentValue:    c.Comment,
+			ScanTypeValue:   scanType(c.ColumnType),
+		})
+	}
+	return result, nil
+}
+
+// scanType scan type of pgx stdlib driver, columns read from pg_attribute have no sql.ColumnType to report it
+func scanType(columnType string) reflect.Type {
+	typ, err := ddl.ParseType(columnType)
+	if err != nil {
+		return reflect.TypeOf("")
+	}
+	return typ.ScanType()
+}
+
+// GetEnumLabels labels of enum types used by columns of table, ordered by type name and enumsortorder.
+// Types are resolved by column type itself, so enum of other schema is found too
+func (t *tableInfo) GetEnumLabels(schemaName, tableName string) (labels []model.EnumLabel, err error) {
+	err = t.Raw(`SELECT DISTINCT ty.typname, e.enumlabel, e.enumsortorder
+FROM pg_attribute a
+	JOIN pg_type ty ON ty.oid = a.atttypid OR ty.typarray = a.atttypid
+	JOIN pg_enum e ON e.enumtypid = ty.oid
+WHERE a.attrelid = to_regclass(?) AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY ty.typname, e.enumsortorder`, t.regclass(schemaName, tableName)).Scan(&labels).Error
+	return labels, err
+}
+
+// regclass quoted relation name for to_regclass
+func (t *tableInfo) regclass(schemaName, tableName string) string {
+	relName := t.Statement.Quote(tableName)
+	if schemaName != "" {
+		relName = t.Statement.Quote(schemaName) + "." + relName
+	}
+	return relName
+}
+
+// getTableEnums find enum types used by columns of table
+func getTableEnums(db *gorm.DB, schemaName, tableName string, enumNameNS func(string) string) []*model.Enum {
+	labels, err := getTableInfo(db).GetEnumLabels(schemaName, tableName)
+	if err != nil { // ignore find enum err
+		db.Logger.Warn(context.Background(), "GetEnumLabels for %s,err=%s", tableName, err.Error())
+		return nil
+	}
+	return model.GroupEnumLabels(labels, enumNameNS)
 }
@@ -0,0 +1,96 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"go.ipao.vip/gen/internal/ddl"
+	"go.ipao.vip/gen/internal/model"
+)
+
+// ddlTableInfo table info of schema parsed from DDL, no query is sent
+type ddlTableInfo struct {
+	tableInfo
+	schema *ddl.Schema
+}
+
+// GetTableColumns columns of table, table must be defined in DDL
+func (t *ddlTableInfo) GetTableColumns(schemaName, tableName string) (result []*model.Column, err error) {
+	if t.schema.Table(schemaName, tableName) == nil {
+		return nil, fmt.Errorf("table [%s] not found in DDL", qualifiedName(schemaName, tableName))
+	}
+	return t.tableInfo.GetTableColumns(schemaName, tableName)
+}
+
+// GetTableIndex indexes of table in schema
+func (t *ddlTableInfo) GetTableIndex(schemaName, tableName string) ([]gorm.Index, error) {
+	return t.Migrator().GetIndexes(qualifiedName(t.schema.Resolve(schemaName), tableName))
+}
+
+// GetTables tables in schema, current schema is used when schemaName is empty
+func (t *ddlTableInfo) GetTables(schemaName string) ([]string, error) {
+	return t.schema.TableNames(schemaName), nil
+}
+
+// GetViews views are not parsed from DDL, columns of view depend on its query
+func (t *ddlTableInfo) GetViews(string) ([]*model.View, error) {
+	return nil, errors.New("views are not parsed from DDL, generate views with a database connection")
+}
+
+// GetEnumLabels labels of enum types used by columns of table, ordered by type name and label order.
+// Types are resolved by schema of column type, not schema of table
+func (t *ddlTableInfo) GetEnumLabels(schemaName, tableName string) (labels []model.EnumLabel, err error) {
+	table := t.schema.Table(schemaName, tableName)
+	if table == nil {
+		return nil, nil
+	}
+	types := make(map[string]string) // qualified type name => type name
+	for _, c := range table.Columns {
+		if key := qualifiedName(t.schema.Resolve(c.Type.Schema), c.Type.Name); t.schema.Enums[key] != nil {
+			types[key] = c.Type.Name
+		}
+	}
+	keys := make([]string, 0, len(types))
+	for key := range types {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return types[keys[i]] < types[keys[j]] })
+	for _, key := range keys {
+		for _, label := range t.schema.Enums[key] {
+			labels = append(labels, model.EnumLabel{TypeName: types[key], Label: label})
+		}
+	}
+	return labels, nil
+}
+
+// GetForeignKeys foreign keys of tables in schema, current schema is used when schemaName is empty.
+// Referenced tables may be in other schemas, which is reported by RefSchemaName
+func (t *ddlTableInfo) GetForeignKeys(schemaName string) (fks []*model.ForeignKey, err error) {
+	schemaName = t.schema.Resolve(schemaName)
+	for _, tableName := range t.schema.TableNames(schemaName) {
+		table := t.schema.Table(schemaName, tableName)
+		keys := append([]*ddl.ForeignKey(nil), table.ForeignKeys...)
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+		for _, fk := range keys {
+			var refSchema string
+			if fk.RefSchema != schemaName {
+				refSchema = fk.RefSchema
+			}
+			fks = append(fks, &model.ForeignKey{
+				Name:          fk.Name,
+				TableName:     table.Name,
+				RefTableName:  fk.RefTable,
+				RefSchemaName: refSchema,
+				ColumnList:    strings.Join(fk.Columns, ","),
+				RefColumnList: strings.Join(t.schema.RefColumns(fk), ","),
+				Unique:        table.UniqueColumns(fk.Columns),
+				TableColumns:  len(table.Columns),
+			})
+		}
+	}
+	return fks, nil
+}
@@ -111,7 +111,7 @@ func (g *KeyWord) Contain(text string) bool {
 var (
 	defaultDataType             = "string"
 	dataType        dataTypeMap = map[string]dataTypeMapping{
-		"numeric":    func(string) string { return "int32" },
+		"numeric":    func(string) string { return "types.Decimal" },
 		"integer":    func(string) string { return "int32" },
 		"int":        func(string) string { return "int32" },
 		"smallint":   func(string) string { return "int32" },
@@ -120,7 +120,7 @@ var (
 		"float":      func(string) string { return "float32" },
 		"real":       func(string) string { return "float64" },
 		"double":     func(string) string { return "float64" },
-		"decimal":    func(string) string { return "float64" },
+		"decimal":    func(string) string { return "types.Decimal" },
 		"char":       func(string) string { return "string" },
 		"varchar":    func(string) string { return "string" },
 		"tinytext":   func(string) string { return "string" },
@@ -142,6 +142,9 @@ var (
 		"year":       func(string) string { return "int32" },
 		"bit":        func(string) string { return "[]uint8" },
 		"boolean":    func(string) string { return "bool" },
+		"hstore":     func(string) string { return "types.HStore" },
+		"ltree":      func(string) string { return "types.LTree" },
+		"citext":     func(string) string { return "string" },
 		"tinyint": func(detailType string) string {
 			if strings.HasPrefix(strings.TrimSpace(detailType), "tinyint(1)") {
 				return "bool"
@@ -172,6 +175,7 @@ type Field struct {
 	Tag              field.Tag
 	GORMTag          field.GormTag
 	CustomGenType    string
+	EnumType         string // generated enum type of the column, with package prefix if needed
 	Relation         *field.Relation
 }
 
@@ -203,6 +207,9 @@ func (m *Field) GenType() string {
 	if strings.HasPrefix(typ, "types.Array") {
 		return "Array"
 	}
+	if m.EnumType != "" {
+		return "Enum[" + m.EnumType + "]"
+	}
 
 	switch typ {
 	case "string", "bytes":
@@ -13,6 +13,7 @@ type Config struct {
 	TablePrefix string
 	TableName   string
 	ModelName   string
+	SchemaName  string // schema of the table, TableName will be qualified with it
 
 	ImportPkgPaths []string
 	ModelOpts      []Option
@@ -29,6 +30,7 @@ type NameStrategy struct {
 	TableNameNS func(tableName string) string
 	ModelNameNS func(tableName string) string
 	FileNameNS  func(tableName string) string
+	EnumNameNS  func(enumName string) string
 }
 
 // FieldConfig field configuration
@@ -110,6 +112,9 @@ func (cfg *Config) GetSchemaName(db *gorm.DB) string {
 	if cfg == nil {
 		return ""
 	}
+	if cfg.SchemaName != "" {
+		return cfg.SchemaName
+	}
 
 	for _, opt := range cfg.SchemaNameOpts {
 		if name := opt(db); name != "" {
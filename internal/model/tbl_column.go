@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"go.ipao.vip/gen/field"
@@ -86,6 +87,12 @@ func (c *Column) buildGormTag() field.GormTag {
 		field.TagKeyGormColumn: []string{c.Name()},
 		field.TagKeyGormType:   []string{c.columnType()},
 	}
+	if c.isNumeric() { // precision and scale of numeric(p,s), kept when type tag is removed
+		if precision, scale, ok := c.DecimalSize(); ok && precision > 0 {
+			tag.Set(field.TagKeyGormPrecision, strconv.FormatInt(precision, 10))
+			tag.Set(field.TagKeyGormScale, strconv.FormatInt(scale, 10))
+		}
+	}
 	isPriKey, ok := c.PrimaryKey()
 	isValidPriKey := ok && isPriKey
 	if isValidPriKey {
@@ -169,6 +176,15 @@ func (c *Column) defaultTagValue() string {
 	return value
 }
 
+// isNumeric column of numeric or decimal type
+func (c *Column) isNumeric() bool {
+	switch strings.ToLower(c.DatabaseTypeName()) {
+	case "numeric", "decimal":
+		return true
+	}
+	return false
+}
+
 func (c *Column) columnType() (v string) {
 	if cl, ok := c.ColumnType.ColumnType(); ok {
 		return cl
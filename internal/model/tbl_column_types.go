@@ -11,10 +11,17 @@ var defaultDataTypeMap = map[string]func(columnType gorm.ColumnType) (dataType s
 	"bytea":   func(gorm.ColumnType) string { return "[]byte" },
 	"boolean": func(gorm.ColumnType) string { return "bool" },
 
+	// arbitrary precision numeric
+	"numeric": func(gorm.ColumnType) string { return "types.Decimal" },
+	"decimal": func(gorm.ColumnType) string { return "types.Decimal" },
+
 	// time/date/timestamp (including verbose names)
-	"date":      func(gorm.ColumnType) string { return "types.Date" },
-	"time":      func(gorm.ColumnType) string { return "types.Time" },
-	"timestamp": func(gorm.ColumnType) string { return "time.Time" },
+	"date":        func(gorm.ColumnType) string { return "types.Date" },
+	"time":        func(gorm.ColumnType) string { return "types.Time" },
+	"timetz":      func(gorm.ColumnType) string { return "string" }, // keeps offset, types.Time has none
+	"timestamp":   func(gorm.ColumnType) string { return "time.Time" },
+	"timestamptz": func(gorm.ColumnType) string { return "time.Time" },
+	"interval":    func(gorm.ColumnType) string { return "types.Interval" },
 
 	// JSON/XML/MONEY
 	"json":  func(gorm.ColumnType) string { return "types.JSON" },
@@ -56,7 +63,7 @@ var defaultDataTypeMap = map[string]func(columnType gorm.ColumnType) (dataType s
 	"double precision[]": func(gorm.ColumnType) string { return "types.Array[float64]" },
 	"boolean[]":          func(gorm.ColumnType) string { return "types.Array[bool]" },
 	"uuid[]":             func(gorm.ColumnType) string { return "types.Array[string]" },
-	"numeric[]":          func(gorm.ColumnType) string { return "types.Array[float64]" },
+	"numeric[]":          func(gorm.ColumnType) string { return "types.Array[types.Decimal]" },
 
 	// Ranges
 	"int4range": func(gorm.ColumnType) string { return "types.Int4Range" },
@@ -69,4 +76,19 @@ var defaultDataTypeMap = map[string]func(columnType gorm.ColumnType) (dataType s
 	// Full text search
 	"tsvector": func(gorm.ColumnType) string { return "types.TSVector" },
 	"tsquery":  func(gorm.ColumnType) string { return "types.TSQuery" },
+
+	// Extensions
+	"hstore":   func(gorm.ColumnType) string { return "types.HStore" },
+	"ltree":    func(gorm.ColumnType) string { return "types.LTree" },
+	"citext":   func(gorm.ColumnType) string { return "string" },
+	"citext[]": func(gorm.ColumnType) string { return "types.Array[string]" },
+}
+
+// DefaultDataTypeMap copy of default data type mapping, used to extend defaults instead of replacing them
+func DefaultDataTypeMap() map[string]func(columnType gorm.ColumnType) (dataType string) {
+	m := make(map[string]func(columnType gorm.ColumnType) (dataType string), len(defaultDataTypeMap))
+	for k, v := range defaultDataTypeMap {
+		m[k] = v
+	}
+	return m
 }
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Enum PostgreSQL enum type info
+type Enum struct {
+	Name     string       // enum type name in db server
+	TypeName string       // generated Go type name
+	Values   []*EnumValue // labels ordered by enumsortorder
+}
+
+// EnumValue enum label and its generated constant name
+type EnumValue struct {
+	ConstName string
+	Label     string
+}
+
+// EnumLabel one row of pg_enum
+type EnumLabel struct {
+	TypeName string `gorm:"column:typname"`
+	Label    string `gorm:"column:enumlabel"`
+}
+
+// GroupEnumLabels group labels by enum type name, keep the order of labels
+func GroupEnumLabels(labels []EnumLabel, typeNameNS func(enumName string) string) []*Enum {
+	var enums []*Enum
+	enumMap := make(map[string]*Enum)
+	for _, l := range labels {
+		e, ok := enumMap[l.TypeName]
+		if !ok {
+			e = &Enum{Name: l.TypeName, TypeName: typeNameNS(l.TypeName)}
+			enumMap[l.TypeName] = e
+			enums = append(enums, e)
+		}
+		e.Values = append(e.Values, &EnumValue{Label: l.Label})
+	}
+	for _, e := range enums {
+		e.fillConstNames()
+	}
+	return enums
+}
+
+// fillConstNames generate constant name for each label, e.g. order_status.in_progress => OrderStatusInProgress
+func (e *Enum) fillConstNames() {
+	used := make(map[string]bool, len(e.Values))
+	for _, v := range e.Values {
+		name := e.TypeName + enumLabelName(v.Label)
+		for i := 2; used[name]; i++ {
+			name = e.TypeName + enumLabelName(v.Label) + strconv.Itoa(i)
+		}
+		used[name] = true
+		v.ConstName = name
+	}
+}
+
+// ArrayType generated Go type for array of the enum
+func (e *Enum) ArrayType() string { return "types.Array[" + e.TypeName + "]" }
+
+func enumLabelName(label string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range label {
+		switch {
+		case r >= 'a' && r <= 'z':
+			if upper {
+				r -= 'a' - 'A'
+			}
+			b.WriteRune(r)
+			upper = false
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			upper = false
+		default: // treat any other character as word separator
+			upper = true
+		}
+	}
+	if b.Len() == 0 {
+		return "Empty"
+	}
+	return b.String()
+}
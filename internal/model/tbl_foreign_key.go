@@ -0,0 +1,52 @@
+package model
+
+import "strings"
+
+// ForeignKey foreign key constraint read from pg_constraint
+type ForeignKey struct {
+	Name          string `gorm:"column:name"`
+	TableName     string `gorm:"column:table_name"`
+	RefTableName  string `gorm:"column:ref_table_name"`
+	RefSchemaName string `gorm:"column:ref_schema_name"` // schema of referenced table, empty when same as table
+	ColumnList    string `gorm:"column:columns"`         // comma separated, in constraint order
+	RefColumnList string `gorm:"column:ref_columns"`     // comma separated, in constraint order
+	Unique        bool   `gorm:"column:is_unique"`       // columns are covered by an unique index exactly
+	TableColumns  int    `gorm:"column:table_columns"`
+}
+
+// Columns foreign key columns
+func (fk *ForeignKey) Columns() []string { return strings.Split(fk.ColumnList, ",") }
+
+// RefColumns referenced columns
+func (fk *ForeignKey) RefColumns() []string { return strings.Split(fk.RefColumnList, ",") }
+
+// RefTable referenced table, schema-qualified when it is in another schema
+func (fk *ForeignKey) RefTable() string {
+	if fk.RefSchemaName == "" {
+		return fk.RefTableName
+	}
+	return fk.RefSchemaName + "." + fk.RefTableName
+}
+
+// JoinTables detect pure join tables: tables consist of exactly two single column foreign keys only
+func JoinTables(fks []*ForeignKey) map[string][2]*ForeignKey {
+	byTable := make(map[string][]*ForeignKey)
+	for _, fk := range fks {
+		byTable[fk.TableName] = append(byTable[fk.TableName], fk)
+	}
+
+	joinTables := make(map[string][2]*ForeignKey)
+	for table, keys := range byTable {
+		if len(keys) != 2 || keys[0].TableColumns != 2 {
+			continue
+		}
+		if len(keys[0].Columns()) != 1 || len(keys[1].Columns()) != 1 || keys[0].ColumnList == keys[1].ColumnList {
+			continue
+		}
+		if keys[0].RefTable() == table || keys[1].RefTable() == table {
+			continue
+		}
+		joinTables[table] = [2]*ForeignKey{keys[0], keys[1]}
+	}
+	return joinTables
+}
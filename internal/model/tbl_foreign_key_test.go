@@ -0,0 +1,44 @@
+package model
+
+import "testing"
+
+func TestJoinTables(t *testing.T) {
+	fk := func(table, columns, refSchema, refTable string, tableColumns int) *ForeignKey {
+		return &ForeignKey{TableName: table, ColumnList: columns, RefSchemaName: refSchema, RefTableName: refTable, RefColumnList: "id", TableColumns: tableColumns}
+	}
+	fks := []*ForeignKey{
+		fk("orders", "customer_id", "", "customers", 3),
+		fk("order_products", "order_id", "", "orders", 2),
+		fk("order_products", "product_id", "", "products", 2),
+		fk("user_accounts", "user_id", "", "users", 2), // account in another schema
+		fk("user_accounts", "account_id", "billing", "accounts", 2),
+		fk("friends", "user_id", "", "users", 2), // self-referencing pair
+		fk("friends", "friend_id", "", "users", 2),
+		fk("tree", "id", "", "nodes", 2), // refers to itself
+		fk("tree", "parent_id", "", "tree", 2),
+		fk("order_notes", "order_id", "", "orders", 3), // has extra column
+		fk("order_notes", "note_id", "", "notes", 3),
+		fk("pairs", "a_id", "", "items", 2), // same column twice
+		fk("pairs", "a_id", "", "others", 2),
+	}
+
+	got := JoinTables(fks)
+	want := map[string][2]string{
+		"order_products": {"orders", "products"},
+		"user_accounts":  {"users", "billing.accounts"},
+		"friends":        {"users", "users"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expect join tables %v, got %v", want, got)
+	}
+	for table, refs := range want {
+		keys, ok := got[table]
+		if !ok {
+			t.Errorf("expect join table %s", table)
+			continue
+		}
+		if keys[0].RefTable() != refs[0] || keys[1].RefTable() != refs[1] {
+			t.Errorf("join table %s: expect %v, got [%s %s]", table, refs, keys[0].RefTable(), keys[1].RefTable())
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package model
+
+// View view or materialized view info
+type View struct {
+	Name         string `gorm:"column:relname"`
+	Materialized bool   `gorm:"column:materialized"`
+}
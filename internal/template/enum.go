@@ -0,0 +1,74 @@
+package template
+
+// Enum used as a variable because it cannot load template file after packed, params still can pass file
+const Enum = NotEditMark + `
+package {{.Package}}
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+{{range .Enums}}
+// {{.TypeName}} mapped from PostgreSQL enum <{{.Name}}>
+type {{.TypeName}} string
+
+const (
+	{{- $typeName := .TypeName}}
+	{{- range .Values}}
+	{{.ConstName}} {{$typeName}} = {{printf "%q" .Label}}
+	{{- end}}
+)
+
+// Values returns all labels of <{{.Name}}> in declaration order
+func ({{.TypeName}}) Values() []{{.TypeName}} {
+	return []{{.TypeName}}{ {{- range .Values}}{{.ConstName}}, {{end -}} }
+}
+
+// IsValid reports whether e is a label of <{{.Name}}>
+func (e {{.TypeName}}) IsValid() bool {
+	switch e {
+	case {{range $i, $v := .Values}}{{if $i}}, {{end}}{{$v.ConstName}}{{end}}:
+		return true
+	}
+	return false
+}
+
+// String implements fmt.Stringer
+func (e {{.TypeName}}) String() string { return string(e) }
+
+// GormDataType gorm common data type
+func ({{.TypeName}}) GormDataType() string { return "{{.Name}}" }
+
+// Scan implements sql.Scanner, NULL is scanned as zero value ""
+func (e *{{.TypeName}}) Scan(src interface{}) error {
+	var v {{.TypeName}}
+	switch s := src.(type) {
+	case nil:
+		*e = ""
+		return nil
+	case string:
+		v = {{.TypeName}}(s)
+	case []byte:
+		v = {{.TypeName}}(s)
+	default:
+		return fmt.Errorf("cannot scan %T into {{.TypeName}}", src)
+	}
+	if !v.IsValid() {
+		return fmt.Errorf("invalid {{.Name}} value: %q", string(v))
+	}
+	*e = v
+	return nil
+}
+
+// Value implements driver.Valuer, zero value "" is stored as NULL
+func (e {{.TypeName}}) Value() (driver.Value, error) {
+	if e == "" {
+		return nil, nil
+	}
+	if !e.IsValid() {
+		return nil, fmt.Errorf("invalid {{.Name}} value: %q", string(e))
+	}
+	return string(e), nil
+}
+{{end}}
+`
@@ -26,9 +26,19 @@ func ({{.S}} {{.QueryStructName}}Do) Clauses(conds ...clause.Expression) {{.Retu
 	return {{.S}}.withDO({{.S}}.DO.Clauses(conds...))
 }
 
+{{if not .ReadOnly}}
 func ({{.S}} {{.QueryStructName}}Do) Returning(value interface{}, columns ...string) {{.ReturnObject}} {
 	return {{.S}}.withDO({{.S}}.DO.Returning(value, columns...))
 }
+{{end}}
+
+func ({{.S}} {{.QueryStructName}}Do) With(name string, q gen.SubQuery) {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.With(name, q))
+}
+
+func ({{.S}} {{.QueryStructName}}Do) WithRecursive(name string, anchor, recursive gen.SubQuery) {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.WithRecursive(name, anchor, recursive))
+}
 
 func ({{.S}} {{.QueryStructName}}Do) Not(conds ...gen.Condition) {{.ReturnObject}} {
 	return {{.S}}.withDO({{.S}}.DO.Not(conds...))
@@ -54,6 +64,10 @@ func ({{.S}} {{.QueryStructName}}Do) Distinct(cols ...field.Expr) {{.ReturnObjec
 	return {{.S}}.withDO({{.S}}.DO.Distinct(cols...))
 }
 
+func ({{.S}} {{.QueryStructName}}Do) DistinctOn(cols ...field.Expr) {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.DistinctOn(cols...))
+}
+
 func ({{.S}} {{.QueryStructName}}Do) Omit(cols ...field.Expr) {{.ReturnObject}} {
 	return {{.S}}.withDO({{.S}}.DO.Omit(cols...))
 }
@@ -70,6 +84,14 @@ func ({{.S}} {{.QueryStructName}}Do) RightJoin(table schema.Tabler, on ...field.
 	return {{.S}}.withDO({{.S}}.DO.RightJoin(table, on...))
 }
 
+func ({{.S}} {{.QueryStructName}}Do) JoinLateral(q gen.SubQuery, alias string, on ...field.Expr) {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.JoinLateral(q, alias, on...))
+}
+
+func ({{.S}} {{.QueryStructName}}Do) LeftJoinLateral(q gen.SubQuery, alias string, on ...field.Expr) {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.LeftJoinLateral(q, alias, on...))
+}
+
 func ({{.S}} {{.QueryStructName}}Do) Group(cols ...field.Expr) {{.ReturnObject}} {
 	return {{.S}}.withDO({{.S}}.DO.Group(cols...))
 }
@@ -94,6 +116,37 @@ func ({{.S}} {{.QueryStructName}}Do) Unscoped() {{.ReturnObject}} {
 	return {{.S}}.withDO({{.S}}.DO.Unscoped())
 }
 
+{{if not .ReadOnly}}
+func ({{.S}} {{.QueryStructName}}Do) ForUpdate() {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.ForUpdate())
+}
+
+func ({{.S}} {{.QueryStructName}}Do) ForNoKeyUpdate() {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.ForNoKeyUpdate())
+}
+
+func ({{.S}} {{.QueryStructName}}Do) ForShare() {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.ForShare())
+}
+
+func ({{.S}} {{.QueryStructName}}Do) ForKeyShare() {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.ForKeyShare())
+}
+
+func ({{.S}} {{.QueryStructName}}Do) SkipLocked() {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.SkipLocked())
+}
+
+func ({{.S}} {{.QueryStructName}}Do) NoWait() {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.NoWait())
+}
+
+func ({{.S}} {{.QueryStructName}}Do) Of(tables ...schema.Tabler) {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.Of(tables...))
+}
+{{end}}
+
+{{if not .ReadOnly}}
 func ({{.S}} {{.QueryStructName}}Do) Create(values ...*{{.StructPkgPrefix}}{{.StructInfo.Type}}) error {
 	if len(values) == 0 {
 		return nil
@@ -114,6 +167,23 @@ func ({{.S}} {{.QueryStructName}}Do) Save(values ...*{{.StructPkgPrefix}}{{.Stru
 	return {{.S}}.DO.Save(values)
 }
 
+// Upsert insert values with typed ON CONFLICT handling, e.g.
+// Upsert(values...).OnConflict(col).DoUpdate(cols...).Returning(cols...).Exec()
+func ({{.S}} {{.QueryStructName}}Do) Upsert(values ...*{{.StructPkgPrefix}}{{.StructInfo.Type}}) *gen.Upsert {
+	return {{.S}}.DO.Upsert(values)
+}
+
+// InsertFrom insert rows selected by q, e.g.
+// InsertFrom([]field.Expr{col1, col2}, q).OnConflict(col1).DoNothing().Exec()
+func ({{.S}} {{.QueryStructName}}Do) InsertFrom(columns []field.Expr, q gen.SubQuery) *gen.InsertSelect {
+	return {{.S}}.DO.InsertFrom(columns, q)
+}
+
+func ({{.S}} {{.QueryStructName}}Do) DeleteUsing(tables ...schema.Tabler) {{.ReturnObject}} {
+	return {{.S}}.withDO({{.S}}.DO.DeleteUsing(tables...))
+}
+{{end}}
+
 func ({{.S}} {{.QueryStructName}}Do) First() (*{{.StructPkgPrefix}}{{.StructInfo.Type}}, error) {
 	if result, err := {{.S}}.DO.First(); err != nil {
 		return nil, err
@@ -186,6 +256,7 @@ func ({{.S}} {{.QueryStructName}}Do) FirstOrInit() (*{{.StructPkgPrefix}}{{.Stru
 	}
 }
 
+{{if not .ReadOnly}}
 func ({{.S}} {{.QueryStructName}}Do) FirstOrCreate() (*{{.StructPkgPrefix}}{{.StructInfo.Type}}, error) {
 	if result, err := {{.S}}.DO.FirstOrCreate(); err != nil {
 		return nil, err
@@ -193,6 +264,7 @@ func ({{.S}} {{.QueryStructName}}Do) FirstOrCreate() (*{{.StructPkgPrefix}}{{.St
 		return result.(*{{.StructPkgPrefix}}{{.StructInfo.Type}}), nil
 	}
 }
+{{end}}
 
 func ({{.S}} {{.QueryStructName}}Do) FindByPage(offset int, limit int) (result []*{{.StructPkgPrefix}}{{.StructInfo.Type}}, count int64, err error) {
 	result, err = {{.S}}.Offset(offset).Limit(limit).Find()
@@ -219,10 +291,21 @@ func ({{.S}} {{.QueryStructName}}Do) ScanByPage(result interface{}, offset int,
 	return
 }
 
+// FindByCursor keyset pagination after cursor, empty cursor for the first page.
+// Primary key is appended to order as tiebreaker, page holds cursors of next and previous pages.
+func ({{.S}} {{.QueryStructName}}Do) FindByCursor(cursor string, limit int, order ...field.Expr) (result []*{{.StructPkgPrefix}}{{.StructInfo.Type}}, page gen.CursorPage, err error) {
+	results, page, err := {{.S}}.DO.FindByCursor(cursor, limit, order...)
+	if err != nil {
+		return nil, page, err
+	}
+	return results.([]*{{.StructPkgPrefix}}{{.StructInfo.Type}}), page, nil
+}
+
 func ({{.S}} {{.QueryStructName}}Do) Scan(result interface{}) (err error) {
 	return {{.S}}.DO.Scan(result)
 }
 
+{{if not .ReadOnly}}
 func ({{.S}} {{.QueryStructName}}Do) Delete(models ...*{{.StructPkgPrefix}}{{.StructInfo.Type}}) (result gen.ResultInfo, err error) {
 	return {{.S}}.DO.Delete(models)
 }
@@ -245,6 +328,7 @@ func ({{.S}} {{.QueryStructName}}Do) Dec(column field.Expr, step int64) (gen.Res
     e := field.NewUnsafeFieldRaw("?-?", column.RawExpr(), step)
     return {{.S}}.DO.UpdateColumn(column, e)
 }
+{{end}}
 
 // Sum returns SUM(column) for current scope.
 func ({{.S}} {{.QueryStructName}}Do) Sum(column field.Expr) (float64, error) {
@@ -341,6 +425,7 @@ func ({{.S}} {{.QueryStructName}}Do) GetByIDs(ids ...{{.PrimaryGoType}}) ([]*{{.
     return {{.S}}.Where(pk.In(ids...)).Find()
 }
 
+{{if not .ReadOnly}}
 // DeleteByID deletes records by primary key.
 func ({{.S}} {{.QueryStructName}}Do) DeleteByID(id {{.PrimaryGoType}}) (gen.ResultInfo, error) {
     pk := field.New{{.PrimaryFieldGenType}}({{.S}}.TableName(), "{{.PrimaryFieldColumn}}")
@@ -356,8 +441,9 @@ func ({{.S}} {{.QueryStructName}}Do) DeleteByIDs(ids ...{{.PrimaryGoType}}) (gen
     return {{.S}}.Where(pk.In(ids...)).Delete()
 }
 {{end}}
+{{end}}
 
-{{if .HasSoftDelete}}
+{{if and .HasSoftDelete (not .ReadOnly)}}
 // RestoreWhere sets deleted_at to NULL for rows matching current scope + conds.
 func ({{.S}} {{.QueryStructName}}Do) RestoreWhere(conds ...gen.Condition) (gen.ResultInfo, error) {
     col := field.NewField({{.S}}.TableName(), "deleted_at")
@@ -374,6 +460,17 @@ func ({{.S}} {{.QueryStructName}}Do) RestoreByID(id {{.PrimaryGoType}}) (gen.Res
 {{end}}
 {{end}}
 
+{{if .Materialized}}
+// Refresh refreshes the materialized view, concurrently requires a unique index on it.
+func ({{.S}} {{.QueryStructName}}Do) Refresh(ctx context.Context, concurrently bool) error {
+	stmt := "REFRESH MATERIALIZED VIEW "
+	if concurrently {
+		stmt += "CONCURRENTLY "
+	}
+	return {{.S}}.UnderlyingDB().WithContext(ctx).Exec(stmt + {{.S}}.Quote({{.S}}.TableName())).Error
+}
+{{end}}
+
 func ({{.S}} *{{.QueryStructName}}Do) withDO(do gen.Dao) (*{{.QueryStructName}}Do) {
 	{{.S}}.DO = *do.(*gen.DO)
 	return {{.S}}
@@ -31,35 +31,42 @@ type {{.ModelStructName}} struct {
 	`{{end}}
 }
 
+{{if .Schema -}}
+// TableName {{.ModelStructName}}'s schema-qualified table name
+func (*{{.ModelStructName}}) TableName() string { return TableName{{.ModelStructName}} }
+{{- end}}
+
+{{if not .ReadOnly -}}
 // Quick operations without importing query package
 // Update applies changed fields to the database using the default DB.
-func (m *{{.ModelStructName}}) Update(ctx context.Context) (gen.ResultInfo, error) { return Q.{{.ModelStructName}}.WithContext(ctx).Updates(m) }
+func (m *{{.ModelStructName}}) Update(ctx context.Context) (gen.ResultInfo, error) { return Q.{{.QueryField}}.WithContext(ctx).Updates(m) }
 
 // Save upserts the model using the default DB.
-func (m *{{.ModelStructName}}) Save(ctx context.Context) error { return Q.{{.ModelStructName}}.WithContext(ctx).Save(m) }
+func (m *{{.ModelStructName}}) Save(ctx context.Context) error { return Q.{{.QueryField}}.WithContext(ctx).Save(m) }
 
 // Create inserts the model using the default DB.
-func (m *{{.ModelStructName}}) Create(ctx context.Context) error { return Q.{{.ModelStructName}}.WithContext(ctx).Create(m) }
+func (m *{{.ModelStructName}}) Create(ctx context.Context) error { return Q.{{.QueryField}}.WithContext(ctx).Create(m) }
 
 // Delete removes the row represented by the model using the default DB.
-func (m *{{.ModelStructName}}) Delete(ctx context.Context) (gen.ResultInfo, error) { return Q.{{.ModelStructName}}.WithContext(ctx).Delete(m) }
+func (m *{{.ModelStructName}}) Delete(ctx context.Context) (gen.ResultInfo, error) { return Q.{{.QueryField}}.WithContext(ctx).Delete(m) }
 
 // ForceDelete permanently deletes the row (ignores soft delete) using the default DB.
 func (m *{{.ModelStructName}}) ForceDelete(ctx context.Context) (gen.ResultInfo, error) {
-    return Q.{{.ModelStructName}}.WithContext(ctx).Unscoped().Delete(m)
+    return Q.{{.QueryField}}.WithContext(ctx).Unscoped().Delete(m)
 }
 
 {{if and .HasPrimaryKey .HasSoftDelete -}}
 // Restore sets deleted_at to NULL for this model's primary key using the default DB.
 func (m *{{.ModelStructName}}) Restore(ctx context.Context) (gen.ResultInfo, error) {
-    return Q.{{.ModelStructName}}.WithContext(ctx).RestoreByID(m.{{.PrimaryFieldName}})
+    return Q.{{.QueryField}}.WithContext(ctx).RestoreByID(m.{{.PrimaryFieldName}})
 }
 {{- end}}
+{{- end}}
 
 {{if .HasPrimaryKey -}}
 // Reload reloads the model from database by its primary key and overwrites current fields.
 func (m *{{.ModelStructName}}) Reload(ctx context.Context) error {
-    fresh, err := Q.{{.ModelStructName}}.WithContext(ctx).GetByID(m.{{.PrimaryFieldName}})
+    fresh, err := Q.{{.QueryField}}.WithContext(ctx).GetByID(m.{{.PrimaryFieldName}})
     if err != nil { return err }
     *m = *fresh
     return nil
@@ -10,10 +10,16 @@ var (
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
+	{{range $g := .SchemaGroups}}{{if $g.PkgName -}}
+	{{$g.PkgName}}.SetDefault(db, opts...)
+	{{end}}{{end -}}
 	*Q = *Use(db,opts...)
 	{{range $name,$d :=.Data -}}
-	{{$d.TopName}} = &Q.{{$d.ModelStructName}}
+	{{$d.TopName}} = &Q.{{$d.QueryField}}
 	{{end -}}
+	{{range $g := .SchemaGroups}}{{if $g.PkgName -}}
+	Q.{{$g.Name}} = {{$g.PkgName}}.Q
+	{{end}}{{end -}}
 }
 
 `
@@ -23,27 +29,65 @@ const QueryMethod = `
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
 		db: db,
-		{{range $name,$d :=.Data -}}
+		{{range $d :=.DefaultSchemaData -}}
 		{{$d.ModelStructName}}: new{{$d.ModelStructName}}(db,opts...),
 		{{end -}}
+		{{range $g :=.SchemaGroups -}}
+		{{if $g.PkgName -}}
+		{{$g.Name}}: {{$g.PkgName}}.Use(db,opts...),
+		{{else -}}
+		{{$g.Name}}: {{$g.TypeName}}{
+			{{range $d :=$g.Data -}}
+			{{$d.GroupField}}: new{{$d.ModelStructName}}(db,opts...),
+			{{end -}}
+		},
+		{{end -}}
+		{{end -}}
 	}
 }
 
 type Query struct{
 	db *gorm.DB
 
-	{{range $name,$d :=.Data -}}
+	{{range $d :=.DefaultSchemaData -}}
 	{{$d.ModelStructName}} {{$d.QueryStructName}}
+	{{end -}}
+	{{range $g :=.SchemaGroups -}}
+	{{$g.Name}} {{if $g.PkgName}}*{{$g.PkgName}}.Query{{else}}{{$g.TypeName}}{{end}}
+	{{end}}
+}
+{{range $g :=.SchemaGroups}}{{if not $g.PkgName}}
+// {{$g.TypeName}} query structs of schema <{{$g.Schema}}>
+type {{$g.TypeName}} struct{
+	{{range $d :=$g.Data -}}
+	{{$d.GroupField}} {{$d.QueryStructName}}
 	{{end}}
 }
 
+type {{$g.TypeName}}Ctx struct{
+	{{range $d :=$g.Data -}}
+	{{$d.GroupField}} {{$d.ReturnObject}}
+	{{end}}
+}
+{{end}}{{end}}
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
 		db: db,
-		{{range $name,$d :=.Data -}}
+		{{range $d :=.DefaultSchemaData -}}
 		{{$d.ModelStructName}}: q.{{$d.ModelStructName}}.clone(db),
+		{{end -}}
+		{{range $g :=.SchemaGroups -}}
+		{{if $g.PkgName -}}
+		{{$g.Name}}: q.{{$g.Name}}.ReplaceDB(db),
+		{{else -}}
+		{{$g.Name}}: {{$g.TypeName}}{
+			{{range $d :=$g.Data -}}
+			{{$d.GroupField}}: q.{{$d.QueryField}}.clone(db),
+			{{end -}}
+		},
+		{{end -}}
 		{{end}}
 	}
 }
@@ -59,22 +103,48 @@ func (q *Query) WriteDB() *Query {
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
 		db: db,
-		{{range $name,$d :=.Data -}}
+		{{range $d :=.DefaultSchemaData -}}
 		{{$d.ModelStructName}}: q.{{$d.ModelStructName}}.replaceDB(db),
+		{{end -}}
+		{{range $g :=.SchemaGroups -}}
+		{{if $g.PkgName -}}
+		{{$g.Name}}: q.{{$g.Name}}.ReplaceDB(db),
+		{{else -}}
+		{{$g.Name}}: {{$g.TypeName}}{
+			{{range $d :=$g.Data -}}
+			{{$d.GroupField}}: q.{{$d.QueryField}}.replaceDB(db),
+			{{end -}}
+		},
+		{{end -}}
 		{{end}}
 	}
 }
 
 type queryCtx struct{ 
-	{{range $name,$d :=.Data -}}
+	{{range $d :=.DefaultSchemaData -}}
 	{{$d.ModelStructName}} {{$d.ReturnObject}}
+	{{end -}}
+	{{range $g :=.SchemaGroups -}}
+	{{if not $g.PkgName -}}
+	{{$g.Name}} {{$g.TypeName}}Ctx
+	{{end -}}
 	{{end}}
 }
 
+// WithContext query structs with context, use Query.<Schema>.WithContext for schema sub package
 func (q *Query) WithContext(ctx context.Context) *queryCtx  {
 	return &queryCtx{
-		{{range $name,$d :=.Data -}}
+		{{range $d :=.DefaultSchemaData -}}
 		{{$d.ModelStructName}}: q.{{$d.ModelStructName}}.WithContext(ctx),
+		{{end -}}
+		{{range $g :=.SchemaGroups -}}
+		{{if not $g.PkgName -}}
+		{{$g.Name}}: {{$g.TypeName}}Ctx{
+			{{range $d :=$g.Data -}}
+			{{$d.GroupField}}: q.{{$d.QueryField}}.WithContext(ctx),
+			{{end -}}
+		},
+		{{end -}}
 		{{end}}
 	}
 }
@@ -164,7 +234,7 @@ func Test_WithContext(t *testing.T) {
 
 	for _, ctx := range []context.Context{
 		{{range $name,$d :=.Data -}}
-		qCtx.{{$d.ModelStructName}}.UnderlyingDB().Statement.Context,
+		qCtx.{{$d.QueryField}}.UnderlyingDB().Statement.Context,
 		{{end}}
 	} {
 		if v := ctx.Value(key); v != value {
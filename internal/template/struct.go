@@ -136,7 +136,12 @@ func ({{.S}} *{{.QueryStructName}}) GetFieldByName(fieldName string) (field.Orde
 		`{{- $relation := .Relation }}{{- $relationship := $relation.RelationshipName}}` +
 		relationStruct + relationTx +
 		`{{end}}{{end}}`
-	defineMethodStruct = `type {{.QueryStructName}}Do struct { gen.DO }`
+	defineMethodStruct = `type {{.QueryStructName}}Do struct {
+	gen.DO
+	{{- if .ReadOnly}}
+	gen.ReadOnly // view is read-only, write methods of gen.DO are not accessible
+	{{- end}}
+}`
 
 	fillFieldMapMethod = `
 func ({{.S}} *{{.QueryStructName}}) fillFieldMap() {
@@ -163,16 +168,21 @@ type I{{.ModelStructName}}Do interface {
 	Session(config *gorm.Session) I{{.ModelStructName}}Do
 	Columns(cols ...field.Expr) gen.Columns
 	Clauses(conds ...clause.Expression) I{{.ModelStructName}}Do
+	With(name string, q gen.SubQuery) I{{.ModelStructName}}Do
+	WithRecursive(name string, anchor, recursive gen.SubQuery) I{{.ModelStructName}}Do
 	Not(conds ...gen.Condition) I{{.ModelStructName}}Do
 	Or(conds ...gen.Condition) I{{.ModelStructName}}Do
 	Select(conds ...field.Expr) I{{.ModelStructName}}Do
 	Where(conds ...gen.Condition) I{{.ModelStructName}}Do
 	Order(conds ...field.Expr) I{{.ModelStructName}}Do
 	Distinct(cols ...field.Expr) I{{.ModelStructName}}Do
+	DistinctOn(cols ...field.Expr) I{{.ModelStructName}}Do
 	Omit(cols ...field.Expr) I{{.ModelStructName}}Do
 	Join(table schema.Tabler, on ...field.Expr) I{{.ModelStructName}}Do
 	LeftJoin(table schema.Tabler, on ...field.Expr) I{{.ModelStructName}}Do
 	RightJoin(table schema.Tabler, on ...field.Expr) I{{.ModelStructName}}Do
+	JoinLateral(q gen.SubQuery, alias string, on ...field.Expr) I{{.ModelStructName}}Do
+	LeftJoinLateral(q gen.SubQuery, alias string, on ...field.Expr) I{{.ModelStructName}}Do
 	Group(cols ...field.Expr) I{{.ModelStructName}}Do
 	Having(conds ...gen.Condition) I{{.ModelStructName}}Do
 	Limit(limit int) I{{.ModelStructName}}Do
@@ -180,9 +190,19 @@ type I{{.ModelStructName}}Do interface {
 	Count() (count int64, err error)
 	Scopes(funcs ...func(gen.Dao) gen.Dao) I{{.ModelStructName}}Do
 	Unscoped() I{{.ModelStructName}}Do
+{{- if not .ReadOnly}}
+	ForUpdate() I{{.ModelStructName}}Do
+	ForNoKeyUpdate() I{{.ModelStructName}}Do
+	ForShare() I{{.ModelStructName}}Do
+	ForKeyShare() I{{.ModelStructName}}Do
+	SkipLocked() I{{.ModelStructName}}Do
+	NoWait() I{{.ModelStructName}}Do
+	Of(tables ...schema.Tabler) I{{.ModelStructName}}Do
 	Create(values ...*{{.StructPkgPrefix}}{{.StructInfo.Type}}) error
 	CreateInBatches(values []*{{.StructPkgPrefix}}{{.StructInfo.Type}}, batchSize int) error
 	Save(values ...*{{.StructPkgPrefix}}{{.StructInfo.Type}}) error
+	Upsert(values ...*{{.StructPkgPrefix}}{{.StructInfo.Type}}) *gen.Upsert
+{{- end}}
 	First() (*{{.StructPkgPrefix}}{{.StructInfo.Type}}, error)
 	Take() (*{{.StructPkgPrefix}}{{.StructInfo.Type}}, error)
 	Last() (*{{.StructPkgPrefix}}{{.StructInfo.Type}}, error)
@@ -190,6 +210,7 @@ type I{{.ModelStructName}}Do interface {
 	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*{{.StructPkgPrefix}}{{.StructInfo.Type}}, err error)
 	FindInBatches(result *[]*{{.StructPkgPrefix}}{{.StructInfo.Type}}, batchSize int, fc func(tx gen.Dao, batch int) error) error
 	Pluck(column field.Expr, dest interface{}) error
+{{- if not .ReadOnly}}
 	Delete(...*{{.StructPkgPrefix}}{{.StructInfo.Type}}) (info gen.ResultInfo, err error)
 	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
 	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
@@ -198,27 +219,37 @@ type I{{.ModelStructName}}Do interface {
 	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
 	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
 	UpdateFrom(q gen.SubQuery) gen.Dao
+	InsertFrom(columns []field.Expr, q gen.SubQuery) *gen.InsertSelect
+	DeleteUsing(tables ...schema.Tabler) I{{.ModelStructName}}Do
+{{- end}}
 	Attrs(attrs ...field.AssignExpr) I{{.ModelStructName}}Do
 	Assign(attrs ...field.AssignExpr) I{{.ModelStructName}}Do
 	Joins(fields ...field.RelationField) I{{.ModelStructName}}Do
 	Preload(fields ...field.RelationField) I{{.ModelStructName}}Do
 	FirstOrInit() (*{{.StructInfo.Package}}.{{.StructInfo.Type}}, error)
+{{- if not .ReadOnly}}
 	FirstOrCreate() (*{{.StructInfo.Package}}.{{.StructInfo.Type}}, error)
+{{- end}}
 	FindByPage(offset int, limit int) (result []*{{.StructInfo.Package}}.{{.StructInfo.Type}}, count int64, err error)
 	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	FindByCursor(cursor string, limit int, order ...field.Expr) (result []*{{.StructInfo.Package}}.{{.StructInfo.Type}}, page gen.CursorPage, err error)
 	Rows() (*sql.Rows, error)
 	Row() *sql.Row
 	Scan(result interface{}) (err error)
 	Exists(conds ...gen.Condition) (bool, error)
 	// Aggregates and counters
+{{- if not .ReadOnly}}
 	Inc(column field.Expr, step int64) (info gen.ResultInfo, err error)
 	Dec(column field.Expr, step int64) (info gen.ResultInfo, err error)
+{{- end}}
 	Sum(column field.Expr) (float64, error)
 	Avg(column field.Expr) (float64, error)
 	Min(column field.Expr) (float64, error)
 	Max(column field.Expr) (float64, error)
 	PluckMap(key, val field.Expr) (map[interface{}]interface{}, error)
+{{- if not .ReadOnly}}
 	Returning(value interface{}, columns ...string) I{{.ModelStructName}}Do
+{{- end}}
 	UnderlyingDB() *gorm.DB
 	schema.Tabler
 
@@ -230,12 +261,18 @@ type I{{.ModelStructName}}Do interface {
 	PluckIDs() ([]{{.PrimaryGoType}}, error)
 	GetByID(id {{.PrimaryGoType}}) (*{{.StructInfo.Package}}.{{.StructInfo.Type}}, error)
 	GetByIDs(ids ...{{.PrimaryGoType}}) ([]*{{.StructInfo.Package}}.{{.StructInfo.Type}}, error)
+{{- if not .ReadOnly}}
 	DeleteByID(id {{.PrimaryGoType}}) (info gen.ResultInfo, err error)
 	DeleteByIDs(ids ...{{.PrimaryGoType}}) (info gen.ResultInfo, err error)
+{{- end}}
 {{end}}
 
+{{if .Materialized}}
+	Refresh(ctx context.Context, concurrently bool) error
+{{else if not .ReadOnly}}
 	ForceDelete() (info gen.ResultInfo, err error)
-{{if .HasSoftDelete}}
+{{end}}
+{{if and .HasSoftDelete (not .ReadOnly)}}
 	RestoreWhere(conds ...gen.Condition) (info gen.ResultInfo, err error)
 {{if .HasPrimaryKey}}
 	RestoreByID(id {{.PrimaryGoType}}) (info gen.ResultInfo, err error)
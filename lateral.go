@@ -0,0 +1,237 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/field"
+	"go.ipao.vip/gen/helper"
+)
+
+// fromItem FROM item which is not a sub query, i.e. CTE reference and set-returning function
+type fromItem interface {
+	fromExpr() clause.Expression
+}
+
+// JoinLateral JOIN LATERAL (q) AS alias ON conds, ON true when conds is empty.
+// q may reference columns of preceding tables, e.g. top N rows per group
+//
+//	o.JoinLateral(o2.Where(o2.UserID.EqCol(u.ID)).Order(o2.CreatedAt.Desc()).Limit(3), "recent")
+func (d *DO) JoinLateral(q SubQuery, alias string, conds ...field.Expr) Dao {
+	return d.joinLateral(q, alias, clause.InnerJoin, conds)
+}
+
+// LeftJoinLateral LEFT JOIN LATERAL (q) AS alias ON conds, ON true when conds is empty
+func (d *DO) LeftJoinLateral(q SubQuery, alias string, conds ...field.Expr) Dao {
+	return d.joinLateral(q, alias, clause.LeftJoin, conds)
+}
+
+func (d *DO) joinLateral(q SubQuery, alias string, joinType clause.JoinType, conds []field.Expr) Dao {
+	var table clause.Expression
+	switch src := q.(type) {
+	case funcSource:
+		fn := src.funcTable()
+		if fn.err != nil {
+			return d.withError(fn.err)
+		}
+		if alias != "" {
+			fn.alias = alias
+		}
+		table = clause.Expr{SQL: "LATERAL ?", Vars: []interface{}{fn}}
+	case fromItem:
+		return d.withError(errors.New("JoinLateral requires sub query or function"))
+	default:
+		if alias == "" {
+			return d.withError(errors.New("JoinLateral requires alias of sub query"))
+		}
+		do := q.underlyingDO()
+		table = clause.Expr{
+			SQL:  "LATERAL (?) AS ?",
+			Vars: []interface{}{do.db.Table(do.TableName()), clause.Table{Name: alias}},
+		}
+	}
+
+	on := clause.Where{Exprs: toExpression(conds...)}
+	if len(conds) == 0 {
+		on.Exprs = []clause.Expression{clause.Expr{SQL: "true"}}
+	}
+
+	from := getFromClause(d.db)
+	from.Joins = append(from.Joins, clause.Join{
+		Expression: helper.NewJoinTblExpr(clause.Join{Type: joinType, ON: on}, table),
+	})
+	return d.getInstance(d.db.Clauses(from))
+}
+
+// ======================== set-returning functions ========================
+
+// funcSource set-returning function, implemented by FuncTable and its typed wrappers
+type funcSource interface {
+	funcTable() FuncTable
+}
+
+// FuncTable set-returning function used as table in Table, Join and JoinLateral,
+// fields of it are created by Field or field.NewXxx(alias, column)
+//
+//	items := gen.JSONBArrayElements("item", o.Items)
+//	orders, err := o.Join(items, items.Value().HasKey("sku")).Find()
+type FuncTable struct {
+	alias   string
+	expr    clause.Expr
+	columns []RecordColumn // column definition list, type is empty for column alias
+	err     error
+}
+
+var (
+	_ SubQuery = FuncTable{}
+	_ fromItem = FuncTable{}
+)
+
+// As rename alias of function table
+func (t FuncTable) As(alias string) FuncTable {
+	t.alias = alias
+	return t
+}
+
+// TableName return alias of function table
+func (t FuncTable) TableName() string { return t.alias }
+
+// Alias return alias of function table, used by Join
+func (t FuncTable) Alias() string { return t.alias }
+
+// Field column of function result
+func (t FuncTable) Field(column string) field.Field { return field.NewField(t.alias, column) }
+
+// Build implements clause.Expression, e.g. unnest(?) AS "alias"("value")
+func (t FuncTable) Build(builder clause.Builder) {
+	t.expr.Build(builder)
+	builder.WriteString(" AS ")
+	builder.WriteQuoted(t.alias)
+	if len(t.columns) == 0 {
+		return
+	}
+	builder.WriteByte('(')
+	for i, column := range t.columns {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteQuoted(column.Name)
+		if column.Type != "" {
+			builder.WriteString(" " + column.Type)
+		}
+	}
+	builder.WriteByte(')')
+}
+
+func (t FuncTable) funcTable() FuncTable { return t }
+
+func (t FuncTable) fromExpr() clause.Expression { return t }
+
+func (t FuncTable) underlyingDB() *gorm.DB { return nil }
+
+func (t FuncTable) underlyingDO() *DO { return &DO{tableName: t.alias} }
+
+// BeCond implements Condition
+func (t FuncTable) BeCond() interface{} { return nil }
+
+// CondError implements Condition, a function table is not a condition
+func (t FuncTable) CondError() error {
+	return errors.New("function table " + t.alias + " cannot be used as condition")
+}
+
+// Err error of function table, e.g. invalid column definition
+func (t FuncTable) Err() error { return t.err }
+
+// valueColumn name of the only column of value functions
+const valueColumn = "value"
+
+func valueFuncTable(alias, sql string, vars ...interface{}) FuncTable {
+	return FuncTable{alias: alias, expr: clause.Expr{SQL: sql, Vars: vars}, columns: []RecordColumn{{Name: valueColumn}}}
+}
+
+// UnnestTable unnest(array) AS alias(value)
+type UnnestTable struct{ FuncTable }
+
+// Unnest expand array column to rows
+func Unnest(alias string, array field.Expr) UnnestTable {
+	return UnnestTable{valueFuncTable(alias, "unnest(?)", array.RawExpr())}
+}
+
+// Value element of array
+func (t UnnestTable) Value() field.Field { return field.NewField(t.alias, valueColumn) }
+
+// JSONBElementsTable jsonb_array_elements(col) AS alias(value)
+type JSONBElementsTable struct{ FuncTable }
+
+// JSONBArrayElements expand jsonb array column to rows of jsonb
+func JSONBArrayElements(alias string, col field.Expr) JSONBElementsTable {
+	return JSONBElementsTable{valueFuncTable(alias, "jsonb_array_elements(?)", col.RawExpr())}
+}
+
+// Value element of jsonb array
+func (t JSONBElementsTable) Value() field.JSONB { return field.NewJSONB(t.alias, valueColumn) }
+
+// JSONBElementsTextTable jsonb_array_elements_text(col) AS alias(value)
+type JSONBElementsTextTable struct{ FuncTable }
+
+// JSONBArrayElementsText expand jsonb array column to rows of text
+func JSONBArrayElementsText(alias string, col field.Expr) JSONBElementsTextTable {
+	return JSONBElementsTextTable{valueFuncTable(alias, "jsonb_array_elements_text(?)", col.RawExpr())}
+}
+
+// Value element of jsonb array as text
+func (t JSONBElementsTextTable) Value() field.String { return field.NewString(t.alias, valueColumn) }
+
+// SeriesTable generate_series(start, stop, step) AS alias(value)
+type SeriesTable struct{ FuncTable }
+
+// GenerateSeries series of integers from start to stop, step is 1 by default
+func GenerateSeries(alias string, start, stop int64, step ...int64) SeriesTable {
+	by := int64(1)
+	if len(step) > 0 {
+		by = step[0]
+	}
+	return SeriesTable{valueFuncTable(alias, "generate_series(?::bigint, ?::bigint, ?::bigint)", start, stop, by)}
+}
+
+// Value integer of series
+func (t SeriesTable) Value() field.Int64 { return field.NewInt64(t.alias, valueColumn) }
+
+// TimeSeriesTable generate_series(start, stop, step) AS alias(value) of timestamps
+type TimeSeriesTable struct{ FuncTable }
+
+// GenerateTimeSeries series of timestamps from start to stop, step is interval like "1 day"
+func GenerateTimeSeries(alias string, start, stop time.Time, step string) TimeSeriesTable {
+	return TimeSeriesTable{valueFuncTable(alias, "generate_series(?::timestamptz, ?::timestamptz, ?::interval)", start, stop, step)}
+}
+
+// Value timestamp of series
+func (t TimeSeriesTable) Value() field.Time { return field.NewTime(t.alias, valueColumn) }
+
+// RecordColumn column definition of record functions, e.g. {Name: "qty", Type: "int"}
+type RecordColumn struct {
+	Name string
+	Type string
+}
+
+// RecordsetTable jsonb_to_recordset(col) AS alias(name type, ...)
+type RecordsetTable struct{ FuncTable }
+
+// JSONBToRecordset expand jsonb array of objects to rows with columns,
+// fields are created by Field or field.NewXxx(alias, column.Name)
+func JSONBToRecordset(alias string, col field.Expr, columns ...RecordColumn) RecordsetTable {
+	t := FuncTable{alias: alias, expr: clause.Expr{SQL: "jsonb_to_recordset(?)", Vars: []interface{}{col.RawExpr()}}, columns: columns}
+	if len(columns) == 0 {
+		t.err = errors.New("jsonb_to_recordset requires column definition")
+	}
+	for _, c := range columns {
+		if !field.IsTypeName(c.Type) {
+			t.err = fmt.Errorf("invalid type %q of record column %s", c.Type, c.Name)
+		}
+	}
+	return RecordsetTable{t}
+}
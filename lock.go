@@ -0,0 +1,110 @@
+package gen
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrLockWithoutTransaction row locking is used outside of transaction, the lock would be released immediately
+var ErrLockWithoutTransaction = errors.New("row locking requires transaction from Query.Transaction or Begin")
+
+// errLockOptionWithoutLock SkipLocked, NoWait or Of is used without ForUpdate, ForShare...
+var errLockOptionWithoutLock = errors.New("SkipLocked, NoWait and Of must be used after ForUpdate, ForNoKeyUpdate, ForShare or ForKeyShare")
+
+const (
+	lockingStrengthNoKeyUpdate = "NO KEY UPDATE"
+	lockingStrengthKeyShare    = "KEY SHARE"
+)
+
+// ForUpdate FOR UPDATE, must be called in transaction.
+// Only DO built from the tx of Query.Transaction or Begin is accepted, the check is done when
+// row locking is added, a DO kept after its transaction has ended fails when the query is executed.
+//
+//	q.Transaction(func(tx *query.Query) error {
+//		o := tx.Order
+//		orders, err := o.WithContext(ctx).Where(o.Status.Eq("pending")).ForUpdate().SkipLocked().Of(o).Limit(10).Find()
+//		...
+//	})
+func (d *DO) ForUpdate() Dao { return d.lock(clause.LockingStrengthUpdate) }
+
+// ForNoKeyUpdate FOR NO KEY UPDATE, not blocking inserts of rows referencing locked rows, must be called in transaction
+func (d *DO) ForNoKeyUpdate() Dao { return d.lock(lockingStrengthNoKeyUpdate) }
+
+// ForShare FOR SHARE, must be called in transaction
+func (d *DO) ForShare() Dao { return d.lock(clause.LockingStrengthShare) }
+
+// ForKeyShare FOR KEY SHARE, must be called in transaction
+func (d *DO) ForKeyShare() Dao { return d.lock(lockingStrengthKeyShare) }
+
+// SkipLocked SKIP LOCKED of row locking, locked rows are skipped
+func (d *DO) SkipLocked() Dao {
+	return d.withLock(func(l *rowLock) { l.options = clause.LockingOptionsSkipLocked })
+}
+
+// NoWait NOWAIT of row locking, fail instead of waiting for locked rows
+func (d *DO) NoWait() Dao {
+	return d.withLock(func(l *rowLock) { l.options = clause.LockingOptionsNoWait })
+}
+
+// Of OF tables of row locking, only rows of tables are locked, e.g. Of(q.Order),
+// tables are referenced by alias or by name without schema as OF requires
+func (d *DO) Of(tables ...schema.Tabler) Dao {
+	return d.withLock(func(l *rowLock) {
+		for _, table := range tables {
+			_, name := splitTableName(table.TableName())
+			if al, ok := table.(interface{ Alias() string }); ok && al.Alias() != "" {
+				name = al.Alias()
+			}
+			l.tables = append(l.tables, clause.Table{Name: name})
+		}
+	})
+}
+
+func (d *DO) lock(strength string) Dao {
+	if _, ok := d.db.Statement.ConnPool.(gorm.TxCommitter); !ok {
+		return d.withError(ErrLockWithoutTransaction)
+	}
+	return d.getInstance(d.db.Clauses(rowLock{strength: strength}))
+}
+
+func (d *DO) withLock(modify func(*rowLock)) Dao {
+	l, ok := d.db.Statement.Clauses[rowLock{}.Name()].Expression.(rowLock)
+	if !ok {
+		return d.withError(errLockOptionWithoutLock)
+	}
+	l.tables = append([]clause.Table{}, l.tables...)
+	modify(&l)
+	return d.getInstance(d.db.Clauses(l))
+}
+
+// rowLock FOR strength [OF tables] [options], clause.Locking with multiple tables
+type rowLock struct {
+	strength string
+	tables   []clause.Table
+	options  string
+}
+
+// Name implements clause.Interface
+func (rowLock) Name() string { return "FOR" }
+
+// Build implements clause.Expression
+func (l rowLock) Build(builder clause.Builder) {
+	builder.WriteString(l.strength)
+	for i, table := range l.tables {
+		if i == 0 {
+			builder.WriteString(" OF ")
+		} else {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(table)
+	}
+	if l.options != "" {
+		builder.WriteString(" " + l.options)
+	}
+}
+
+// MergeClause implements clause.Interface, the last row locking takes effect
+func (l rowLock) MergeClause(c *clause.Clause) { c.Expression = l }
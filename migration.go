@@ -0,0 +1,590 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"go.ipao.vip/gen/field"
+	"go.ipao.vip/gen/internal/ddl"
+	"go.ipao.vip/gen/internal/generate"
+	"go.ipao.vip/gen/internal/model"
+)
+
+// Migration DDL statements migrating database to models, Down reverts Up
+type Migration struct {
+	Up   []string
+	Down []string
+}
+
+// Empty report whether database is up to date
+func (m *Migration) Empty() bool { return len(m.Up) == 0 }
+
+// migrationStep statement with its reverting statement
+type migrationStep struct{ up, down string }
+
+// migration phases, statements are ordered by phase so that columns exist before indexes on them are created
+const (
+	phaseCreateTable = iota
+	phaseDropIndex
+	phaseAddColumn
+	phaseAlterColumn
+	phaseDropColumn
+	phaseCreateIndex
+	phaseComment
+	phaseCount
+)
+
+// DiffMigration compare models with tables in database, e.g. models of GenerateModelFrom,
+// and return statements adding, dropping and altering columns, indexes and comments.
+func (g *Generator) DiffMigration(metas ...*generate.QueryStructMeta) (*Migration, error) {
+	var steps [phaseCount][]migrationStep
+	for _, meta := range metas {
+		if meta == nil {
+			continue
+		}
+		if err := g.diffTable(&steps, meta); err != nil {
+			return nil, fmt.Errorf("diff table %s fail: %w", meta.TableName, err)
+		}
+	}
+
+	m := &Migration{}
+	for _, phase := range steps {
+		for _, step := range phase {
+			m.Up = append(m.Up, step.up)
+			m.Down = append(m.Down, step.down)
+		}
+	}
+	for i, j := 0, len(m.Down)-1; i < j; i, j = i+1, j-1 {
+		m.Down[i], m.Down[j] = m.Down[j], m.Down[i]
+	}
+	return m, nil
+}
+
+// GenerateMigration write migration of models into dir as <version>_<name>.up.sql and <version>_<name>.down.sql,
+// nothing is written when database is up to date.
+//
+//	user := g.GenerateModelFrom(userObject)
+//	files, err := g.GenerateMigration("migrations", "add_user_email", user)
+func (g *Generator) GenerateMigration(dir, name string, metas ...*generate.QueryStructMeta) (files []string, err error) {
+	m, err := g.DiffMigration(metas...)
+	if err != nil {
+		return nil, err
+	}
+	if m.Empty() {
+		g.info("database is up to date, no migration generated")
+		return nil, nil
+	}
+
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("create migration dir(%s) fail: %w", dir, err)
+	}
+	if name == "" {
+		name = "migration"
+	}
+	prefix := filepath.Join(dir, time.Now().UTC().Format("20060102150405")+"_"+name)
+	for suffix, stmts := range map[string][]string{".up.sql": m.Up, ".down.sql": m.Down} {
+		file := prefix + suffix
+		if err = os.WriteFile(file, migrationContent(stmts), 0o640); err != nil {
+			return nil, err
+		}
+		g.info(fmt.Sprintf("generate migration file: %s", file))
+		files = append(files, file)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(files))) // up first
+	return files, nil
+}
+
+// migrationContent statements of migration file
+func migrationContent(stmts []string) []byte {
+	var b strings.Builder
+	b.WriteString("-- Code generated by go.ipao.vip/gen. Review before applying.\n")
+	for _, stmt := range stmts {
+		if strings.Contains(stmt, " CONCURRENTLY ") {
+			b.WriteString("-- CREATE/DROP INDEX CONCURRENTLY cannot run inside a transaction block.\n")
+			break
+		}
+	}
+	for _, stmt := range stmts {
+		b.WriteString("\n" + stmt + ";\n")
+	}
+	return []byte(b.String())
+}
+
+// migrationColumn column wanted by model
+type migrationColumn struct {
+	name       string
+	sqlType    string // type in DDL, e.g. bigserial, varchar(255)
+	typ        string // normalized type, e.g. bigint, character varying(255)
+	notNull    bool
+	primaryKey bool
+	autoInc    bool
+	def        string
+	comment    string
+}
+
+// migrationIndex index wanted by model or existing in database
+type migrationIndex struct {
+	name    string
+	unique  bool
+	using   string
+	where   string
+	columns []string // columns with optional sort, e.g. "created_at" DESC
+	keys    []string // column names, sorted
+}
+
+func (g *Generator) diffTable(steps *[phaseCount][]migrationStep, meta *generate.QueryStructMeta) error {
+	columns, indexes, err := g.migrationColumns(meta)
+	if err != nil {
+		return err
+	}
+	table := g.db.Statement.Quote(meta.TableName)
+	add := func(phase int, up, down string) {
+		steps[phase] = append(steps[phase], migrationStep{up: up, down: down})
+	}
+
+	migrator := g.db.Migrator()
+	if !migrator.HasTable(meta.TableName) {
+		defs := make([]string, 0, len(columns)+1)
+		var primaryKeys []string
+		for _, c := range columns {
+			defs = append(defs, g.columnDef(c.name, c.sqlType, c.notNull && !c.primaryKey, c.def))
+			if c.primaryKey {
+				primaryKeys = append(primaryKeys, g.db.Statement.Quote(c.name))
+			}
+		}
+		if len(primaryKeys) > 0 {
+			defs = append(defs, "PRIMARY KEY ("+strings.Join(primaryKeys, ", ")+")")
+		}
+		add(phaseCreateTable, "CREATE TABLE "+table+" (\n\t"+strings.Join(defs, ",\n\t")+"\n)", "DROP TABLE "+table)
+		for _, idx := range indexes {
+			add(phaseCreateIndex, g.createIndex(meta.TableName, idx), g.dropIndex(meta.TableName, idx.name))
+		}
+		if meta.TableComment != "" {
+			add(phaseComment, "COMMENT ON TABLE "+table+" IS "+quoteLiteral(meta.TableComment), "COMMENT ON TABLE "+table+" IS NULL")
+		}
+		for _, c := range columns {
+			if c.comment != "" {
+				add(phaseComment, g.commentColumn(meta.TableName, c.name, c.comment), g.commentColumn(meta.TableName, c.name, ""))
+			}
+		}
+		return nil
+	}
+
+	columnTypes, err := migrator.ColumnTypes(meta.TableName)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]gorm.ColumnType, len(columnTypes))
+	for _, ct := range columnTypes {
+		existing[ct.Name()] = ct
+	}
+
+	alter := func(column, action, revert string) {
+		prefix := "ALTER TABLE " + table + " ALTER COLUMN " + g.db.Statement.Quote(column) + " "
+		add(phaseAlterColumn, prefix+action, prefix+revert)
+	}
+	wanted := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		wanted[c.name] = true
+		ct, ok := existing[c.name]
+		if !ok {
+			add(phaseAddColumn,
+				"ALTER TABLE "+table+" ADD COLUMN "+g.columnDef(c.name, c.sqlType, c.notNull, c.def),
+				"ALTER TABLE "+table+" DROP COLUMN "+g.db.Statement.Quote(c.name))
+			if c.comment != "" {
+				add(phaseComment, g.commentColumn(meta.TableName, c.name, c.comment), g.commentColumn(meta.TableName, c.name, ""))
+			}
+			continue
+		}
+
+		columnType, _ := ct.ColumnType()
+		liveType := normalizeType(columnType)
+		if liveType != c.typ {
+			alter(c.name,
+				"TYPE "+c.typ+" USING "+g.db.Statement.Quote(c.name)+"::"+c.typ,
+				"TYPE "+liveType+" USING "+g.db.Statement.Quote(c.name)+"::"+liveType)
+		}
+		if nullable, ok := ct.Nullable(); ok && nullable == c.notNull {
+			if c.notNull {
+				alter(c.name, "SET NOT NULL", "DROP NOT NULL")
+			} else {
+				alter(c.name, "DROP NOT NULL", "SET NOT NULL")
+			}
+		}
+		if autoInc, _ := ct.AutoIncrement(); !autoInc && !c.autoInc {
+			liveDef, _ := ct.DefaultValue()
+			if liveDef != trimDefault(c.def) {
+				alter(c.name, setDefault(c.def), setDefault(liveDef))
+			}
+		}
+		if liveComment, _ := ct.Comment(); liveComment != c.comment {
+			add(phaseComment, g.commentColumn(meta.TableName, c.name, c.comment), g.commentColumn(meta.TableName, c.name, liveComment))
+		}
+	}
+
+	for _, ct := range columnTypes {
+		if wanted[ct.Name()] {
+			continue
+		}
+		notNull := false
+		if nullable, ok := ct.Nullable(); ok {
+			notNull = !nullable
+		}
+		sqlType, _ := ct.ColumnType()
+		if autoInc, _ := ct.AutoIncrement(); autoInc {
+			sqlType = serialType(sqlType)
+		}
+		def, _ := ct.DefaultValue()
+		add(phaseDropColumn,
+			"ALTER TABLE "+table+" DROP COLUMN "+g.db.Statement.Quote(ct.Name()),
+			"ALTER TABLE "+table+" ADD COLUMN "+g.columnDef(ct.Name(), sqlType, notNull, def))
+		if comment, _ := ct.Comment(); comment != "" {
+			add(phaseComment, g.commentColumn(meta.TableName, ct.Name(), ""), g.commentColumn(meta.TableName, ct.Name(), comment))
+		}
+	}
+
+	if meta.TableComment != "" {
+		var liveComment string
+		if tableType, err := migrator.TableType(meta.TableName); err == nil && tableType != nil {
+			liveComment, _ = tableType.Comment()
+		}
+		if liveComment != meta.TableComment {
+			add(phaseComment, "COMMENT ON TABLE "+table+" IS "+quoteLiteral(meta.TableComment),
+				"COMMENT ON TABLE "+table+" IS "+quoteLiteral(liveComment))
+		}
+	}
+
+	schemaName, tableName := splitTableName(meta.TableName)
+	liveIndexes, err := generate.GetIndexes(g.db, &model.Config{SchemaName: schemaName, TableName: tableName})
+	if err != nil {
+		return err
+	}
+	live := make(map[string]*migrationIndex, len(liveIndexes))
+	for _, li := range liveIndexes {
+		idx := &migrationIndex{name: li.Name()}
+		idx.unique, _ = li.Unique()
+		for _, column := range li.Columns() {
+			idx.columns = append(idx.columns, g.db.Statement.Quote(column))
+		}
+		idx.keys = sortedCopy(li.Columns())
+		live[idx.name] = idx
+	}
+	for _, idx := range indexes {
+		old := live[idx.name]
+		delete(live, idx.name)
+		if old != nil {
+			if old.unique == idx.unique && strings.Join(old.keys, ",") == strings.Join(idx.keys, ",") {
+				continue
+			}
+			add(phaseDropIndex, g.dropIndex(meta.TableName, old.name), g.createIndex(meta.TableName, old))
+		}
+		add(phaseCreateIndex, g.createIndex(meta.TableName, idx), g.dropIndex(meta.TableName, idx.name))
+	}
+	names := make([]string, 0, len(live))
+	for name := range live {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(phaseDropIndex, g.dropIndex(meta.TableName, name), g.createIndex(meta.TableName, live[name]))
+	}
+	return nil
+}
+
+// migrationColumns columns and indexes declared by gorm tags of model fields
+func (g *Generator) migrationColumns(meta *generate.QueryStructMeta) (columns []*migrationColumn, indexes []*migrationIndex, err error) {
+	_, tableName := splitTableName(meta.TableName)
+	indexMap := make(map[string]*migrationIndex)
+	priorities := make(map[*migrationIndex]map[string]int)
+	for _, f := range meta.Fields {
+		tag := fieldGORMTag(f)
+		if f.IsRelation() || tag == "-" || strings.HasPrefix(tag, "-:") {
+			continue
+		}
+		settings := schema.ParseTagSetting(tag, ";")
+		if settings["FOREIGNKEY"] != "" || settings["MANY2MANY"] != "" || settings["POLYMORPHIC"] != "" {
+			continue
+		}
+
+		c := &migrationColumn{
+			name:       settings["COLUMN"],
+			primaryKey: hasTagKey(settings, "PRIMARYKEY") || hasTagKey(settings, "PRIMARY_KEY"),
+			def:        settings["DEFAULT"],
+			comment:    f.ColumnComment,
+		}
+		if c.name == "" {
+			c.name = f.ColumnName
+		}
+		if c.name == "" {
+			c.name = g.db.NamingStrategy.ColumnName(tableName, f.Name)
+		}
+		if comment, ok := settings["COMMENT"]; ok {
+			c.comment = strings.Trim(comment, "'")
+		}
+		c.notNull = hasTagKey(settings, "NOT NULL") || hasTagKey(settings, "NOTNULL") || c.primaryKey
+		if v, ok := settings["AUTOINCREMENT"]; ok && !strings.EqualFold(v, "false") {
+			c.autoInc = true
+			c.def = ""
+		}
+		if c.sqlType, err = g.migrationType(f, settings, c.autoInc); err != nil {
+			return nil, nil, err
+		}
+		c.typ = normalizeType(c.sqlType)
+		columns = append(columns, c)
+
+		for _, part := range strings.Split(tag, ";") {
+			key, value, _ := strings.Cut(part, ":")
+			key = strings.ToUpper(strings.TrimSpace(key))
+			if key != "INDEX" && key != "UNIQUEINDEX" {
+				continue
+			}
+			name, options, _ := strings.Cut(value, ",")
+			if name == "" {
+				name = g.db.NamingStrategy.IndexName(tableName, f.Name)
+			}
+			opts := schema.ParseTagSetting(options, ",")
+			idx := indexMap[name]
+			if idx == nil {
+				idx = &migrationIndex{name: name}
+				indexMap[name] = idx
+				priorities[idx] = make(map[string]int)
+				indexes = append(indexes, idx)
+			}
+			if key == "UNIQUEINDEX" || hasTagKey(opts, "UNIQUE") || strings.EqualFold(opts["CLASS"], "UNIQUE") {
+				idx.unique = true
+			}
+			if using := opts["TYPE"] + opts["USING"]; using != "" {
+				idx.using = using
+			}
+			if opts["WHERE"] != "" {
+				idx.where = opts["WHERE"]
+			}
+			column := g.db.Statement.Quote(c.name)
+			if opts["SORT"] != "" {
+				column += " " + strings.ToUpper(opts["SORT"])
+			}
+			priority, err := strconv.Atoi(opts["PRIORITY"])
+			if err != nil {
+				priority = 10 // default priority of gorm
+			}
+			priorities[idx][column] = priority
+			idx.columns = append(idx.columns, column)
+			idx.keys = append(idx.keys, c.name)
+		}
+	}
+
+	for _, idx := range indexes {
+		p := priorities[idx]
+		sort.SliceStable(idx.columns, func(i, j int) bool { return p[idx.columns[i]] < p[idx.columns[j]] })
+		sort.Strings(idx.keys)
+	}
+	return columns, indexes, nil
+}
+
+// migrationType column type of field, from type tag or go type like gorm AutoMigrate
+func (g *Generator) migrationType(f *model.Field, settings map[string]string, autoInc bool) (string, error) {
+	sf := &schema.Field{AutoIncrement: autoInc}
+	sf.Size, _ = strconv.Atoi(settings["SIZE"])
+	sf.Precision, _ = strconv.Atoi(settings["PRECISION"])
+	sf.Scale, _ = strconv.Atoi(settings["SCALE"])
+
+	if typ, ok := settings["TYPE"]; ok && typ != "" {
+		switch dataType := schema.DataType(strings.ToLower(typ)); dataType {
+		case schema.Bool, schema.Int, schema.Uint, schema.Float, schema.String, schema.Time, schema.Bytes:
+			sf.DataType = dataType
+			if sf.Size == 0 && (dataType == schema.Int || dataType == schema.Uint || dataType == schema.Float) {
+				sf.Size = 64
+			}
+		default:
+			sf.DataType = schema.DataType(typ)
+		}
+	} else {
+		dataType, ok := goDataTypes[strings.TrimPrefix(f.Type, "*")]
+		if !ok {
+			return "", fmt.Errorf("unknown column type of field %s(%s), set type in gorm tag", f.Name, f.Type)
+		}
+		sf.DataType = dataType.typ
+		if sf.Size == 0 {
+			sf.Size = dataType.size
+		}
+	}
+	sf.GORMDataType = sf.DataType
+
+	if sf.DataType == schema.Float && sf.Precision == 0 { // gorm maps to numeric, use float types of same size
+		if sf.Size == 32 {
+			return "real", nil
+		}
+		return "double precision", nil
+	}
+	return g.db.Dialector.DataTypeOf(sf), nil
+}
+
+// goDataTypes gorm data types of go types
+var goDataTypes = map[string]struct {
+	typ  schema.DataType
+	size int
+}{
+	"bool":            {schema.Bool, 0},
+	"int":             {schema.Int, 64},
+	"int8":            {schema.Int, 8},
+	"int16":           {schema.Int, 16},
+	"int32":           {schema.Int, 32},
+	"int64":           {schema.Int, 64},
+	"uint":            {schema.Uint, 64},
+	"uint8":           {schema.Uint, 8},
+	"uint16":          {schema.Uint, 16},
+	"uint32":          {schema.Uint, 32},
+	"uint64":          {schema.Uint, 64},
+	"float32":         {schema.Float, 32},
+	"float64":         {schema.Float, 64},
+	"string":          {schema.String, 0},
+	"[]byte":          {schema.Bytes, 0},
+	"json.RawMessage": {schema.Bytes, 0},
+	"time.Time":       {schema.Time, 0},
+	"gorm.DeletedAt":  {schema.Time, 0},
+	"sql.NullTime":    {schema.Time, 0},
+	"sql.NullString":  {schema.String, 0},
+	"sql.NullBool":    {schema.Bool, 0},
+	"sql.NullInt16":   {schema.Int, 16},
+	"sql.NullInt32":   {schema.Int, 32},
+	"sql.NullInt64":   {schema.Int, 64},
+	"sql.NullFloat64": {schema.Float, 64},
+}
+
+// columnDef column definition of CREATE TABLE and ADD COLUMN
+func (g *Generator) columnDef(name, sqlType string, notNull bool, def string) string {
+	s := g.db.Statement.Quote(name) + " " + sqlType
+	if notNull {
+		s += " NOT NULL"
+	}
+	if def != "" {
+		s += " DEFAULT " + defaultLiteral(def)
+	}
+	return s
+}
+
+func (g *Generator) commentColumn(table, column, comment string) string {
+	if comment == "" {
+		return "COMMENT ON COLUMN " + g.db.Statement.Quote(table+"."+column) + " IS NULL"
+	}
+	return "COMMENT ON COLUMN " + g.db.Statement.Quote(table+"."+column) + " IS " + quoteLiteral(comment)
+}
+
+func (g *Generator) createIndex(table string, idx *migrationIndex) string {
+	var b strings.Builder
+	b.WriteString("CREATE ")
+	if idx.unique {
+		b.WriteString("UNIQUE ")
+	}
+	b.WriteString("INDEX CONCURRENTLY IF NOT EXISTS " + g.db.Statement.Quote(idx.name) + " ON " + g.db.Statement.Quote(table))
+	if idx.using != "" {
+		b.WriteString(" USING " + idx.using)
+	}
+	b.WriteString(" (" + strings.Join(idx.columns, ", ") + ")")
+	if idx.where != "" {
+		b.WriteString(" WHERE " + idx.where)
+	}
+	return b.String()
+}
+
+// dropIndex index is dropped in schema of table
+func (g *Generator) dropIndex(table, name string) string {
+	if schemaName, _ := splitTableName(table); schemaName != "" {
+		name = schemaName + "." + name
+	}
+	return "DROP INDEX CONCURRENTLY IF EXISTS " + g.db.Statement.Quote(name)
+}
+
+// fieldGORMTag gorm tag of field, raw tag of object field or tag built from table column
+func fieldGORMTag(f *model.Field) string {
+	if tag, ok := f.Tag[field.TagKeyGorm]; ok {
+		return tag
+	}
+	return f.GORMTag.Build()
+}
+
+func hasTagKey(settings map[string]string, key string) bool {
+	_, ok := settings[key]
+	return ok
+}
+
+// splitTableName schema and table of optionally schema-qualified table name
+func splitTableName(name string) (schemaName, table string) {
+	if schemaName, table, ok := strings.Cut(name, "."); ok {
+		return schemaName, table
+	}
+	return "", name
+}
+
+// normalizeType type as format_type() reports it, e.g. varchar(255) -> character varying(255)
+func normalizeType(sqlType string) string {
+	typ, err := ddl.ParseType(sqlType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(sqlType))
+	}
+	typ.Serial = false
+	return typ.Format()
+}
+
+// serialType serial type of integer column with sequence default
+func serialType(sqlType string) string {
+	switch normalizeType(sqlType) {
+	case "smallint":
+		return "smallserial"
+	case "integer":
+		return "serial"
+	case "bigint":
+		return "bigserial"
+	}
+	return sqlType
+}
+
+// setDefault SET DEFAULT or DROP DEFAULT of ALTER COLUMN
+func setDefault(def string) string {
+	if def == "" {
+		return "DROP DEFAULT"
+	}
+	return "SET DEFAULT " + defaultLiteral(def)
+}
+
+var (
+	defaultCastRegexp   = regexp.MustCompile(`^(.*?)(?:::.*)?$`)
+	defaultNumberRegexp = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// trimDefault default value without cast and quotes, as postgres migrator reports it
+func trimDefault(def string) string {
+	return strings.Trim(defaultCastRegexp.ReplaceAllString(def, "$1"), "'")
+}
+
+// defaultLiteral default value in DDL, values except numbers, booleans and expressions are quoted
+func defaultLiteral(def string) string {
+	switch upper := strings.ToUpper(def); {
+	case strings.HasPrefix(def, "'"), strings.Contains(def, "("), defaultNumberRegexp.MatchString(def),
+		upper == "TRUE", upper == "FALSE", upper == "NULL", strings.HasPrefix(upper, "CURRENT_"):
+		return def
+	}
+	return quoteLiteral(def)
+}
+
+func quoteLiteral(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func sortedCopy(s []string) []string {
+	s = append([]string(nil), s...)
+	sort.Strings(s)
+	return s
+}
@@ -0,0 +1,90 @@
+package gen
+
+import (
+	"gorm.io/gorm/schema"
+
+	"go.ipao.vip/gen/field"
+)
+
+// ReadOnly embedded next to DO in query structs generated for views.
+// Its methods share names with the write and row locking methods of DO, which makes them
+// ambiguous selectors, so calling Create/Save/Update/Delete/ForUpdate on a view fails to compile.
+type ReadOnly struct{}
+
+// Create ...
+func (ReadOnly) Create(interface{}) error { return ErrReadOnly }
+
+// CreateInBatches ...
+func (ReadOnly) CreateInBatches(interface{}, int) error { return ErrReadOnly }
+
+// Save ...
+func (ReadOnly) Save(interface{}) error { return ErrReadOnly }
+
+// FirstOrCreate ...
+func (ReadOnly) FirstOrCreate() (interface{}, error) { return nil, ErrReadOnly }
+
+// Update ...
+func (ReadOnly) Update(field.Expr, interface{}) (ResultInfo, error) { return ResultInfo{}, ErrReadOnly }
+
+// UpdateSimple ...
+func (ReadOnly) UpdateSimple(...field.AssignExpr) (ResultInfo, error) {
+	return ResultInfo{}, ErrReadOnly
+}
+
+// Updates ...
+func (ReadOnly) Updates(interface{}) (ResultInfo, error) { return ResultInfo{}, ErrReadOnly }
+
+// UpdateColumn ...
+func (ReadOnly) UpdateColumn(field.Expr, interface{}) (ResultInfo, error) {
+	return ResultInfo{}, ErrReadOnly
+}
+
+// UpdateColumnSimple ...
+func (ReadOnly) UpdateColumnSimple(...field.AssignExpr) (ResultInfo, error) {
+	return ResultInfo{}, ErrReadOnly
+}
+
+// UpdateColumns ...
+func (ReadOnly) UpdateColumns(interface{}) (ResultInfo, error) { return ResultInfo{}, ErrReadOnly }
+
+// Upsert ...
+func (ReadOnly) Upsert(interface{}) *Upsert { return nil }
+
+// UpdateFrom ...
+func (ReadOnly) UpdateFrom(SubQuery) Dao { return nil }
+
+// InsertFrom ...
+func (ReadOnly) InsertFrom([]field.Expr, SubQuery) *InsertSelect { return nil }
+
+// DeleteUsing ...
+func (ReadOnly) DeleteUsing(...schema.Tabler) Dao { return nil }
+
+// Delete ...
+func (ReadOnly) Delete(...interface{}) (ResultInfo, error) { return ResultInfo{}, ErrReadOnly }
+
+// UpdateQuery ...
+func (ReadOnly) UpdateQuery(...field.AssignExpr) SubQuery { return nil }
+
+// DeleteQuery ...
+func (ReadOnly) DeleteQuery() SubQuery { return nil }
+
+// ForUpdate ...
+func (ReadOnly) ForUpdate() Dao { return nil }
+
+// ForNoKeyUpdate ...
+func (ReadOnly) ForNoKeyUpdate() Dao { return nil }
+
+// ForShare ...
+func (ReadOnly) ForShare() Dao { return nil }
+
+// ForKeyShare ...
+func (ReadOnly) ForKeyShare() Dao { return nil }
+
+// SkipLocked ...
+func (ReadOnly) SkipLocked() Dao { return nil }
+
+// NoWait ...
+func (ReadOnly) NoWait() Dao { return nil }
+
+// Of ...
+func (ReadOnly) Of(...schema.Tabler) Dao { return nil }
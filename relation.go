@@ -0,0 +1,217 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"gorm.io/gorm/schema"
+
+	"go.ipao.vip/gen/field"
+	"go.ipao.vip/gen/internal/generate"
+	"go.ipao.vip/gen/internal/model"
+)
+
+// RelationInfo relation inferred from foreign key, passed to relation name strategy
+type RelationInfo struct {
+	Relationship field.RelationshipType
+	Table        string   // table of the model owning the relation field
+	RefTable     string   // table of the related model, schema-qualified when it is in another schema
+	Columns      []string // foreign key columns, belong to JoinTable for Many2Many
+	JoinTable    string   // join table, only set for Many2Many
+	FieldName    string   // default field name, e.g. Customer, Orders
+}
+
+// foreignKeyRelations relation fields inferred from foreign keys between tables, grouped by table name
+//
+// Tables referenced in other schemas are related to the models named as GenerateModelIn does with the SchemaPrefix layout,
+// e.g. BillingCustomer for billing.customers, only the referencing side gets the relation field.
+//
+// BelongsTo field is named after foreign key column, e.g. Sender for sender_id; columns without _id suffix are
+// named with column and model, e.g. CreatedByUser for created_by, when the same parent is referenced more than once
+func (g *Generator) foreignKeyRelations(tableList []string) map[string][]ModelOpt {
+	fks, err := generate.GetForeignKeys(g.db, g.genModelConfig("", "", nil))
+	if err != nil { // ignore find foreign key err
+		g.db.Logger.Warn(context.Background(), "GetForeignKeys fail: %s", err)
+		return nil
+	}
+
+	tables := make(map[string]bool, len(tableList))
+	for _, table := range tableList {
+		tables[table] = true
+	}
+	modelName := func(table string) string {
+		if !tables[table] || (g.tableNameNS != nil && g.tableNameNS(table) == "") {
+			return ""
+		}
+		if g.modelNameNS != nil {
+			return g.modelNameNS(table)
+		}
+		return g.db.NamingStrategy.SchemaName(table)
+	}
+	refModelName := func(fk *model.ForeignKey) string {
+		if fk.RefSchemaName == "" {
+			return modelName(fk.RefTableName)
+		}
+		if g.SchemaLayout != SchemaPrefix { // models of sub package are not importable from here
+			g.info(fmt.Sprintf("ignore relation of table <%s> to <%s> in another schema package", fk.TableName, fk.RefTable()))
+			return ""
+		}
+		if g.tableNameNS != nil && g.tableNameNS(fk.RefTableName) == "" {
+			return ""
+		}
+		group, name := g.schemaModelName(fk.RefSchemaName, fk.RefTableName) // named as GenerateModelIn does
+		return group + name
+	}
+
+	// foreign keys from the same table to the same parent need distinguished names on parent side
+	pairs := make(map[[2]string]int)
+	for _, fk := range fks {
+		pairs[[2]string{fk.TableName, fk.RefTable()}]++
+	}
+	joinTables := model.JoinTables(fks)
+
+	relations := make(map[string][]ModelOpt)
+	names := make(map[string]map[string]bool)
+	add := func(info RelationInfo, refModel string, tag field.GormTag) {
+		fieldName := info.FieldName
+		if g.relationNS != nil {
+			fieldName = g.relationNS(info)
+		}
+		if fieldName == "" {
+			return
+		}
+		if names[info.Table] == nil {
+			names[info.Table] = make(map[string]bool)
+		}
+		if names[info.Table][fieldName] {
+			g.info(fmt.Sprintf("ignore duplicate relation field %s of table <%s>", fieldName, info.Table))
+			return
+		}
+		names[info.Table][fieldName] = true
+
+		config := &field.RelateConfig{
+			GORMTag:       tag,
+			RelatePointer: info.Relationship == field.BelongsTo || info.Relationship == field.HasOne,
+		}
+		relations[info.Table] = append(relations[info.Table],
+			relateModel(info.Relationship, fieldName, filepath.Base(g.ModelPkgPath), refModel, config))
+	}
+
+	for _, fk := range fks {
+		child, parent := modelName(fk.TableName), refModelName(fk)
+		if child == "" || parent == "" {
+			continue
+		}
+		fkFields, refFields := g.columnFieldNames(fk.Columns()), g.columnFieldNames(fk.RefColumns())
+		tag := field.GormTag{}
+		tag.Set("foreignKey", strings.Join(fkFields, ","))
+		tag.Set("references", strings.Join(refFields, ","))
+
+		multiple := pairs[[2]string{fk.TableName, fk.RefTable()}] > 1
+		role, belongsTo := "", parent
+		if cols := fk.Columns(); len(cols) == 1 && strings.HasSuffix(cols[0], "_id") {
+			role = g.columnFieldNames([]string{strings.TrimSuffix(cols[0], "_id")})[0]
+			belongsTo = role
+		} else if multiple { // column field keeps its own name, e.g. CreatedBy and CreatedByUser
+			role = strings.Join(fkFields, "")
+			belongsTo = role + parent
+		}
+		add(RelationInfo{
+			Relationship: field.BelongsTo,
+			Table:        fk.TableName,
+			RefTable:     fk.RefTable(),
+			Columns:      fk.Columns(),
+			FieldName:    belongsTo,
+		}, parent, tag)
+
+		if _, ok := joinTables[fk.TableName]; ok || fk.RefSchemaName != "" { // Many2Many instead, or parent not generated here
+			continue
+		}
+		info := RelationInfo{
+			Relationship: field.HasMany,
+			Table:        fk.RefTableName,
+			RefTable:     fk.TableName,
+			Columns:      fk.Columns(),
+			FieldName:    inflection.Plural(child),
+		}
+		if fk.Unique {
+			info.Relationship, info.FieldName = field.HasOne, child
+		}
+		if multiple {
+			info.FieldName = role + info.FieldName // e.g. SenderMessages, CreatedByDocuments
+		}
+		add(info, child, tag)
+	}
+
+	for _, joinTable := range sortedKeys(joinTables) {
+		keys := joinTables[joinTable]
+		for i, fk := range keys {
+			ref := keys[1-i]
+			if fk.RefSchemaName != "" { // owner not generated here
+				continue
+			}
+			owner, other := modelName(fk.RefTableName), refModelName(ref)
+			if owner == "" || other == "" {
+				continue
+			}
+			tag := field.GormTag{}
+			tag.Set("many2many", joinTable)
+			tag.Set("foreignKey", g.columnFieldNames(fk.RefColumns())[0])
+			tag.Set("joinForeignKey", g.columnFieldNames(fk.Columns())[0])
+			tag.Set("references", g.columnFieldNames(ref.RefColumns())[0])
+			tag.Set("joinReferences", g.columnFieldNames(ref.Columns())[0])
+			add(RelationInfo{
+				Relationship: field.Many2Many,
+				Table:        fk.RefTableName,
+				RefTable:     ref.RefTable(),
+				Columns:      append(fk.Columns(), ref.Columns()...),
+				JoinTable:    joinTable,
+				FieldName:    inflection.Plural(other),
+			}, other, tag)
+			if fk.RefTable() == ref.RefTable() { // self-referencing, one side is enough
+				break
+			}
+		}
+	}
+	return relations
+}
+
+// columnFieldNames struct field names of columns, named as generated model fields
+func (g *Generator) columnFieldNames(columns []string) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		if ns, ok := g.db.NamingStrategy.(schema.NamingStrategy); ok {
+			ns.SingularTable = true
+			names[i] = ns.SchemaName(ns.TablePrefix + column)
+		} else {
+			names[i] = g.db.NamingStrategy.SchemaName(column)
+		}
+	}
+	return names
+}
+
+// relateModel relate to model generated in the same run
+func relateModel(relationship field.RelationshipType, fieldName, modelPkg, modelName string, config *field.RelateConfig) model.CreateFieldOpt {
+	return func(*model.Field) *model.Field {
+		return &model.Field{
+			Name:     fieldName,
+			Type:     config.RelateFieldPrefix(relationship) + modelName,
+			Tag:      config.GetTag(fieldName),
+			GORMTag:  config.GORMTag,
+			Relation: field.NewRelationWithType(relationship, fieldName, modelPkg+"."+modelName),
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
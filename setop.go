@@ -0,0 +1,217 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/field"
+)
+
+// SetQuery combined result of set operation, created by Union, UnionAll, Intersect and Except,
+// used as SubQuery in Table
+//
+//	Table(gen.Union(u.Select(u.ID).Where(u.Age.Gt(18)), a.Select(a.UserID)).Order(u.ID).Limit(10).As("t")).Find()
+type SetQuery struct {
+	op      string
+	queries []SubQuery
+	orders  []field.Expr
+	limit   int
+	offset  int
+	alias   string
+	err     error
+}
+
+var _ SubQuery = new(SetQuery)
+
+// Union (a) UNION (b), duplicate rows are removed
+func Union(a, b SubQuery, others ...SubQuery) *SetQuery { return newSetQuery("UNION", a, b, others) }
+
+// UnionAll (a) UNION ALL (b)
+func UnionAll(a, b SubQuery, others ...SubQuery) *SetQuery {
+	return newSetQuery("UNION ALL", a, b, others)
+}
+
+// Intersect (a) INTERSECT (b), rows in every query
+func Intersect(a, b SubQuery, others ...SubQuery) *SetQuery {
+	return newSetQuery("INTERSECT", a, b, others)
+}
+
+// Except (a) EXCEPT (b), rows of a not in b
+func Except(a, b SubQuery, others ...SubQuery) *SetQuery { return newSetQuery("EXCEPT", a, b, others) }
+
+func newSetQuery(op string, a, b SubQuery, others []SubQuery) *SetQuery {
+	s := &SetQuery{op: op, queries: append([]SubQuery{a, b}, others...)}
+
+	arity := -1
+	for _, q := range s.queries {
+		n, err := columnCount(q)
+		if err != nil {
+			s.err = err
+			return s
+		}
+		if n < 0 {
+			continue
+		}
+		if arity >= 0 && n != arity {
+			s.err = fmt.Errorf("each %s query must have the same number of columns: %d and %d", op, arity, n)
+			return s
+		}
+		arity = n
+	}
+	return s
+}
+
+// columnCount number of selected columns, -1 when it's unknown
+func columnCount(q SubQuery) (int, error) {
+	if s, ok := q.(*SetQuery); ok {
+		if s.err != nil {
+			return 0, s.err
+		}
+		return columnCount(s.queries[0])
+	}
+
+	db := q.underlyingDB()
+	if db == nil {
+		return 0, errors.New("CTE reference cannot be used in set operation")
+	}
+	if db.Error != nil {
+		return 0, db.Error
+	}
+	stmt := db.Statement
+	if len(stmt.Selects) > 0 {
+		return len(stmt.Selects), nil
+	}
+	if c, ok := stmt.Clauses["SELECT"]; ok {
+		if s, ok := c.Expression.(clause.Select); ok && len(s.Columns) > 0 {
+			return len(s.Columns), nil
+		}
+		if e, ok := c.Expression.(clause.Expr); ok {
+			return topLevelItems(e.SQL), nil
+		}
+	}
+	if stmt.Schema != nil {
+		return len(stmt.Schema.DBNames), nil
+	}
+	return -1, nil
+}
+
+// topLevelItems number of comma separated items outside of parentheses
+func topLevelItems(sql string) int {
+	n, depth := 1, 0
+	for _, c := range sql {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Order ORDER BY of combined result, columns are referenced by output column name
+func (s *SetQuery) Order(columns ...field.Expr) *SetQuery {
+	s.orders = append(s.orders, columns...)
+	return s
+}
+
+// Limit LIMIT of combined result
+func (s *SetQuery) Limit(limit int) *SetQuery {
+	s.limit = limit
+	return s
+}
+
+// Offset OFFSET of combined result
+func (s *SetQuery) Offset(offset int) *SetQuery {
+	s.offset = offset
+	return s
+}
+
+// As alias of combined result used as table
+func (s *SetQuery) As(alias string) *SetQuery {
+	s.alias = alias
+	return s
+}
+
+// Err error of set operation, e.g. queries with different number of columns
+func (s *SetQuery) Err() error { return s.err }
+
+func (s *SetQuery) underlyingDB() *gorm.DB {
+	db := s.queries[0].underlyingDB()
+	if db == nil {
+		db = s.queries[1].underlyingDB()
+	}
+	if db == nil {
+		return nil
+	}
+	db = db.Session(&gorm.Session{NewDB: true})
+	if s.err != nil {
+		_ = db.AddError(s.err)
+		return db
+	}
+
+	var sql strings.Builder
+	vars := make([]interface{}, 0, len(s.queries)+len(s.orders)+2)
+	for i, q := range s.queries {
+		if i > 0 {
+			sql.WriteString(" " + s.op + " ")
+		}
+		sql.WriteString("(?)")
+		vars = append(vars, q.underlyingDB())
+	}
+	if len(s.orders) > 0 {
+		sql.WriteString(" ORDER BY ")
+		for i, order := range s.orders {
+			if i > 0 {
+				sql.WriteByte(',')
+			}
+			sql.WriteByte('?')
+			vars = append(vars, outputColumn(order.RawExpr()))
+		}
+	}
+	if s.limit > 0 {
+		sql.WriteString(" LIMIT ?")
+		vars = append(vars, s.limit)
+	}
+	if s.offset > 0 {
+		sql.WriteString(" OFFSET ?")
+		vars = append(vars, s.offset)
+	}
+	return db.Raw(sql.String(), vars...)
+}
+
+// outputColumn column without table, ORDER BY of set operation only refers to output columns
+func outputColumn(e interface{}) interface{} {
+	switch v := e.(type) {
+	case clause.Column:
+		v.Table = ""
+		return v
+	case clause.Expr:
+		vars := make([]interface{}, len(v.Vars))
+		for i, value := range v.Vars {
+			vars[i] = outputColumn(value)
+		}
+		v.Vars = vars
+		return v
+	default:
+		return e
+	}
+}
+
+func (s *SetQuery) underlyingDO() *DO { return &DO{db: s.underlyingDB(), alias: s.alias} }
+
+// BeCond implements Condition
+func (s *SetQuery) BeCond() interface{} { return nil }
+
+// CondError implements Condition, a set operation is not a condition
+func (s *SetQuery) CondError() error {
+	return errors.New(s.op + " cannot be used as condition")
+}
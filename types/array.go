@@ -264,6 +264,10 @@ func parseInto[T any](dst *T, token string) error {
 }
 
 func elementDBType[T any]() string {
+	// element types naming their own db type (e.g. generated enum types)
+	if dt, ok := any(*new(T)).(interface{ GormDataType() string }); ok && dt.GormDataType() != "" {
+		return dt.GormDataType() + "[]"
+	}
 	typ := reflect.TypeOf(*new(T))
 	switch typ.Kind() {
 	case reflect.String:
@@ -0,0 +1,364 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// RoundingMode rounding mode of Decimal.Round and Decimal.Div
+type RoundingMode int
+
+const (
+	// RoundHalfUp round half away from zero, 2.5 -> 3, -2.5 -> -3
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven round half to even (banker's rounding), 2.5 -> 2, 3.5 -> 4
+	RoundHalfEven
+	// RoundHalfDown round half toward zero, 2.5 -> 2, -2.5 -> -2
+	RoundHalfDown
+	// RoundDown truncate toward zero, 2.9 -> 2, -2.9 -> -2
+	RoundDown
+	// RoundUp round away from zero, 2.1 -> 3, -2.1 -> -3
+	RoundUp
+	// RoundCeiling round toward positive infinity, 2.1 -> 3, -2.9 -> -2
+	RoundCeiling
+	// RoundFloor round toward negative infinity, 2.9 -> 2, -2.1 -> -3
+	RoundFloor
+)
+
+// ErrDivisionByZero division of Decimal by zero
+var ErrDivisionByZero = errors.New("decimal division by zero")
+
+// limits of PostgreSQL numeric, larger inputs are rejected before they are expanded
+const (
+	maxDecimalIntDigits  = 131072 // digits before decimal point
+	maxDecimalFracDigits = 16383  // digits after decimal point
+)
+
+// Decimal arbitrary-precision decimal mapped to PostgreSQL numeric, value is coef * 10^-scale.
+// The zero value is 0, Decimal is immutable and safe to copy.
+// Special values NaN, Infinity and -Infinity of numeric are not supported and fail to parse or scan.
+type Decimal struct {
+	coef  *big.Int // nil is zero
+	scale int32    // digits after decimal point, never negative
+}
+
+// NewDecimal parse decimal from string, e.g. "12.30", "-0.5", "1e-3"
+func NewDecimal(s string) (Decimal, error) {
+	var d Decimal
+	return d, d.parse(s)
+}
+
+// MustDecimal parse decimal from string, panic on invalid input
+func MustDecimal(s string) Decimal {
+	d, err := NewDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewDecimalFromInt decimal of coef * 10^-scale, e.g. NewDecimalFromInt(1234, 2) is 12.34
+func NewDecimalFromInt(coef int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: new(big.Int).Mul(big.NewInt(coef), pow10(-scale))}
+	}
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// NewDecimalFromFloat decimal of shortest representation of f, NaN and Inf are not supported
+func NewDecimalFromFloat(f float64) (Decimal, error) {
+	return NewDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func (d *Decimal) parse(s string) error {
+	str := strings.TrimSpace(s)
+	exp := int64(0)
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid decimal %q", s)
+		}
+		str, exp = str[:i], e
+	}
+	neg := strings.HasPrefix(str, "-")
+	if neg || strings.HasPrefix(str, "+") {
+		str = str[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	digits := intPart + fracPart
+	if digits == "" {
+		return fmt.Errorf("invalid decimal %q", s)
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+	scale := int64(len(fracPart)) - exp
+	significant := int64(len(strings.TrimLeft(digits, "0")))
+	if significant-scale > maxDecimalIntDigits || scale > maxDecimalFracDigits {
+		return fmt.Errorf("decimal %q out of range", s)
+	}
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		coef.Neg(coef)
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+	*d = Decimal{coef: coef, scale: int32(scale)}
+	return nil
+}
+
+// pow10 10^n
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale coefficient at scale, scale must not be less than d.scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.coefficient()
+	}
+	return new(big.Int).Mul(d.coefficient(), pow10(scale-d.scale))
+}
+
+// align coefficients of d and other at the larger scale
+func (d Decimal) align(other Decimal) (*big.Int, *big.Int, int32) {
+	scale := d.scale
+	if other.scale > scale {
+		scale = other.scale
+	}
+	return d.rescale(scale), other.rescale(scale), scale
+}
+
+// Add d + other, scale of result is the larger scale
+func (d Decimal) Add(other Decimal) Decimal {
+	x, y, scale := d.align(other)
+	return Decimal{coef: new(big.Int).Add(x, y), scale: scale}
+}
+
+// Sub d - other, scale of result is the larger scale
+func (d Decimal) Sub(other Decimal) Decimal {
+	x, y, scale := d.align(other)
+	return Decimal{coef: new(big.Int).Sub(x, y), scale: scale}
+}
+
+// Mul d * other, scale of result is the sum of scales
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.coefficient(), other.coefficient()), scale: d.scale + other.scale}
+}
+
+// Div d / other rounded at scale with mode
+//
+//	price.Div(types.NewDecimalFromInt(3, 0), 2, types.RoundHalfEven)
+func (d Decimal) Div(other Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if other.Sign() == 0 {
+		return Decimal{}, ErrDivisionByZero
+	}
+	if scale < 0 {
+		return Decimal{}, fmt.Errorf("invalid decimal scale %d", scale)
+	}
+	// d/other*10^scale = d.coef*10^(scale-d.scale+other.scale) / other.coef
+	num, den := new(big.Int).Set(d.coefficient()), new(big.Int).Set(other.coefficient())
+	if shift := int64(scale) - int64(d.scale) + int64(other.scale); shift >= 0 {
+		num.Mul(num, pow10(int32(shift)))
+	} else {
+		den.Mul(den, pow10(int32(-shift)))
+	}
+	return Decimal{coef: roundQuo(num, den, mode), scale: scale}, nil
+}
+
+// Round round d at scale with mode, digits are padded when scale is larger than d.Scale
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+	if scale >= d.scale {
+		return Decimal{coef: d.rescale(scale), scale: scale}
+	}
+	return Decimal{coef: roundQuo(d.coefficient(), pow10(d.scale-scale), mode), scale: scale}
+}
+
+// roundQuo num/den rounded to integer with mode
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	sign := int64(num.Sign() * den.Sign()) // direction away from zero
+	r.Abs(r)
+	half := r.Lsh(r, 1).Cmp(new(big.Int).Abs(den)) // compare 2|r| with |den|
+
+	var away bool
+	switch mode {
+	case RoundHalfUp:
+		away = half >= 0
+	case RoundHalfEven:
+		away = half > 0 || (half == 0 && q.Bit(0) == 1)
+	case RoundHalfDown:
+		away = half > 0
+	case RoundUp:
+		away = true
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	}
+	if away {
+		q.Add(q, big.NewInt(sign))
+	}
+	return q
+}
+
+// Neg -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.coefficient()), scale: d.scale}
+}
+
+// Abs |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.coefficient()), scale: d.scale}
+}
+
+// Cmp compare d with other, -1 if less, 0 if equal, 1 if greater; trailing zeros are ignored
+func (d Decimal) Cmp(other Decimal) int {
+	x, y, _ := d.align(other)
+	return x.Cmp(y)
+}
+
+// Equal d equals to other numerically, e.g. 1.50 equals to 1.5
+func (d Decimal) Equal(other Decimal) bool { return d.Cmp(other) == 0 }
+
+// Sign -1 if d < 0, 0 if d == 0, 1 if d > 0
+func (d Decimal) Sign() int { return d.coefficient().Sign() }
+
+// IsZero d == 0
+func (d Decimal) IsZero() bool { return d.Sign() == 0 }
+
+// Scale digits after decimal point
+func (d Decimal) Scale() int32 { return d.scale }
+
+// Precision number of significant digits, as precision of numeric(p,s)
+func (d Decimal) Precision() int {
+	n := len(new(big.Int).Abs(d.coefficient()).String())
+	if n < int(d.scale) {
+		return int(d.scale)
+	}
+	return n
+}
+
+// FitsIn d can be stored in numeric(precision, scale) without rounding
+func (d Decimal) FitsIn(precision, scale int) bool {
+	r := d.Round(int32(scale), RoundDown)
+	return r.Equal(d) && len(new(big.Int).Abs(r.coefficient()).String()) <= precision
+}
+
+// Coefficient unscaled value, d = Coefficient * 10^-Scale
+func (d Decimal) Coefficient() *big.Int { return new(big.Int).Set(d.coefficient()) }
+
+// Rat exact value as big.Rat
+func (d Decimal) Rat() *big.Rat { return new(big.Rat).SetFrac(d.coefficient(), pow10(d.scale)) }
+
+// Float64 nearest float64 value, exact reports whether it is exact
+func (d Decimal) Float64() (f float64, exact bool) { return d.Rat().Float64() }
+
+// String plain decimal notation keeping scale, e.g. "12.30"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.coefficient()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+func (Decimal) GormDataType() string { return "numeric" }
+
+// GormDBDataType NUMERIC(precision,scale) from precision and scale tag, NUMERIC when precision is not set
+func (Decimal) GormDBDataType(_ *gorm.DB, field *schema.Field) string {
+	if field != nil && field.Precision > 0 {
+		return fmt.Sprintf("NUMERIC(%d,%d)", field.Precision, field.Scale)
+	}
+	return "NUMERIC"
+}
+
+// Scan implements sql.Scanner, NULL scans as 0, use Null[Decimal] to tell NULL apart
+func (d *Decimal) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case int64:
+		*d = NewDecimalFromInt(v, 0)
+		return nil
+	case float64:
+		r, err := NewDecimalFromFloat(v)
+		if err != nil {
+			return err
+		}
+		*d = r
+		return nil
+	}
+	s, ok := toString(value)
+	if !ok {
+		return fmt.Errorf("unsupported decimal scan type %T", value)
+	}
+	return d.parse(s)
+}
+
+func (d Decimal) Value() (driver.Value, error) { return d.String(), nil }
+
+// GormValuer
+func (d Decimal) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	v, _ := d.Value()
+	return gorm.Expr("?", v)
+}
+
+// MarshalJSON encode as JSON string to keep precision, e.g. "12.30"
+func (d Decimal) MarshalJSON() ([]byte, error) { return []byte(strconv.Quote(d.String())), nil }
+
+// UnmarshalJSON decode from JSON string or number, null keeps d unchanged
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		s, err := strconv.Unquote(string(b))
+		if err != nil {
+			return err
+		}
+		return d.parse(s)
+	}
+	return d.parse(string(b))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(b []byte) error { return d.parse(string(b)) }
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecimal_Parse(t *testing.T) {
+	for input, want := range map[string]string{
+		"12.30":   "12.30",
+		"-0.5":    "-0.5",
+		"+7":      "7",
+		".25":     "0.25",
+		"1e3":     "1000",
+		"1.5E-3":  "0.0015",
+		" 0.000 ": "0.000",
+	} {
+		d, err := NewDecimal(input)
+		if err != nil || d.String() != want {
+			t.Errorf("parse %q: expect %s, got %s %v", input, want, d, err)
+		}
+	}
+	for _, input := range []string{"", "-", "1.2.3", "abc", "1e", "--1", "NaN", "Infinity", "1e20000000", "1e-20000000", "1e131072", "1e-16384", "0e2000000000"} {
+		if _, err := NewDecimal(input); err == nil {
+			t.Errorf("parse %q: expect error", input)
+		}
+	}
+	for _, input := range []string{"1e131071", "1e-16383", "0.001e131074", "0e131072"} {
+		if _, err := NewDecimal(input); err != nil {
+			t.Errorf("parse %q: unexpected error %s", input, err)
+		}
+	}
+	if s := (Decimal{}).String(); s != "0" {
+		t.Errorf("zero value: expect 0, got %s", s)
+	}
+	if s := NewDecimalFromInt(-5, 3).String(); s != "-0.005" {
+		t.Errorf("from int: expect -0.005, got %s", s)
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a, b := MustDecimal("0.1"), MustDecimal("0.2")
+	if s := a.Add(b).String(); s != "0.3" {
+		t.Errorf("0.1+0.2: expect 0.3, got %s", s)
+	}
+	if s := MustDecimal("10").Sub(MustDecimal("0.25")).String(); s != "9.75" {
+		t.Errorf("10-0.25: expect 9.75, got %s", s)
+	}
+	if s := MustDecimal("1.5").Mul(MustDecimal("-2.25")).String(); s != "-3.375" {
+		t.Errorf("1.5*-2.25: expect -3.375, got %s", s)
+	}
+	if q, err := MustDecimal("10").Div(MustDecimal("3"), 4, RoundHalfUp); err != nil || q.String() != "3.3333" {
+		t.Errorf("10/3: expect 3.3333, got %s %v", q, err)
+	}
+	if q, err := MustDecimal("2").Div(MustDecimal("0.03"), 2, RoundDown); err != nil || q.String() != "66.66" {
+		t.Errorf("2/0.03: expect 66.66, got %s %v", q, err)
+	}
+	if _, err := a.Div(Decimal{}, 2, RoundHalfUp); err != ErrDivisionByZero {
+		t.Errorf("division by zero: expect ErrDivisionByZero, got %v", err)
+	}
+	if !MustDecimal("1.50").Equal(MustDecimal("1.5")) || MustDecimal("-1").Cmp(MustDecimal("0.1")) != -1 {
+		t.Errorf("compare decimals with different scales")
+	}
+}
+
+func TestDecimal_Round(t *testing.T) {
+	testcases := []struct {
+		value string
+		mode  RoundingMode
+		want  string
+	}{
+		{"2.5", RoundHalfUp, "3"},
+		{"-2.5", RoundHalfUp, "-3"},
+		{"2.5", RoundHalfEven, "2"},
+		{"3.5", RoundHalfEven, "4"},
+		{"-2.5", RoundHalfDown, "-2"},
+		{"2.51", RoundHalfDown, "3"},
+		{"2.9", RoundDown, "2"},
+		{"-2.1", RoundUp, "-3"},
+		{"-2.9", RoundCeiling, "-2"},
+		{"2.1", RoundCeiling, "3"},
+		{"-2.1", RoundFloor, "-3"},
+		{"2.9", RoundFloor, "2"},
+		{"2", RoundHalfUp, "2"},
+	}
+	for _, tc := range testcases {
+		if s := MustDecimal(tc.value).Round(0, tc.mode).String(); s != tc.want {
+			t.Errorf("round %s with mode %d: expect %s, got %s", tc.value, tc.mode, tc.want, s)
+		}
+	}
+	if s := MustDecimal("1.2").Round(3, RoundHalfUp).String(); s != "1.200" {
+		t.Errorf("round to larger scale: expect 1.200, got %s", s)
+	}
+	if d := MustDecimal("123.45"); !d.FitsIn(5, 2) || d.FitsIn(4, 2) || d.FitsIn(6, 1) {
+		t.Errorf("123.45 should only fit in numeric(5,2) and wider")
+	}
+}
+
+func TestDecimal_ScanValueJSON(t *testing.T) {
+	var d Decimal
+	for value, want := range map[interface{}]string{"12.30": "12.30", int64(42): "42", 0.125: "0.125"} {
+		if err := d.Scan(value); err != nil || d.String() != want {
+			t.Errorf("scan %v: expect %s, got %s %v", value, want, d, err)
+		}
+	}
+	if err := d.Scan([]byte("-1.05")); err != nil || d.String() != "-1.05" {
+		t.Errorf("scan bytes: expect -1.05, got %s %v", d, err)
+	}
+	if err := d.Scan(nil); err != nil || !d.IsZero() {
+		t.Errorf("scan nil: expect 0, got %s %v", d, err)
+	}
+	if err := d.Scan(true); err == nil {
+		t.Errorf("scan bool: expect error")
+	}
+	if v, _ := MustDecimal("9.90").Value(); v != "9.90" {
+		t.Errorf("value: expect 9.90, got %v", v)
+	}
+
+	var out struct{ Amount, Rate Decimal }
+	if err := json.Unmarshal([]byte(`{"Amount":"19.99","Rate":0.075}`), &out); err != nil {
+		t.Fatalf("unmarshal fail: %s", err)
+	}
+	b, _ := json.Marshal(out)
+	if string(b) != `{"Amount":"19.99","Rate":"0.075"}` {
+		t.Errorf("marshal: got %s", b)
+	}
+
+	var arr Array[Decimal]
+	if err := arr.Scan("{1.10,-2.5}"); err != nil || len(arr) != 2 || arr[0].String() != "1.10" {
+		t.Errorf("scan array: got %v %v", arr, err)
+	}
+	if v, _ := arr.Value(); v != `{"1.10","-2.5"}` {
+		t.Errorf("array value: got %v", v)
+	}
+	if typ := arr.GormDataType(); typ != "numeric[]" {
+		t.Errorf("array type: expect numeric[], got %s", typ)
+	}
+}
@@ -0,0 +1,208 @@
+package types
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// HStore PostgreSQL hstore (extension), nil value is SQL NULL of the key
+type HStore map[string]*string
+
+func (HStore) GormDataType() string                          { return "hstore" }
+func (HStore) GormDBDataType(*gorm.DB, *schema.Field) string { return "HSTORE" }
+
+func (h *HStore) Scan(value interface{}) error {
+	if value == nil {
+		*h = nil
+		return nil
+	}
+	s, ok := toString(value)
+	if !ok {
+		return fmt.Errorf("unsupported hstore scan type %T", value)
+	}
+	m, err := parseHStore(s)
+	if err != nil {
+		return err
+	}
+	*h = m
+	return nil
+}
+
+// Value hstore text format with keys sorted, e.g. "a"=>"1", "b"=>NULL
+func (h HStore) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	return h.String(), nil
+}
+
+func (h HStore) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	v, _ := h.Value()
+	return gorm.Expr("?", v)
+}
+
+func (h HStore) String() string {
+	var b strings.Builder
+	for i, k := range h.Keys() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteHStore(k))
+		b.WriteString("=>")
+		if v := h[k]; v != nil {
+			b.WriteString(quoteHStore(*v))
+		} else {
+			b.WriteString("NULL")
+		}
+	}
+	return b.String()
+}
+
+// Constructors
+func NewHStore(pairs map[string]string) HStore {
+	h := make(HStore, len(pairs))
+	for k, v := range pairs {
+		h.Set(k, v)
+	}
+	return h
+}
+
+// Get value of key, ok is false when key is absent or its value is NULL
+func (h HStore) Get(key string) (value string, ok bool) {
+	if v := h[key]; v != nil {
+		return *v, true
+	}
+	return "", false
+}
+
+// Has key exists, even with NULL value
+func (h HStore) Has(key string) bool {
+	_, ok := h[key]
+	return ok
+}
+
+// Keys sorted keys
+func (h HStore) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Edit helpers
+func (h *HStore) Set(key, value string) { h.set(key, &value) }
+func (h *HStore) SetNull(key string)    { h.set(key, nil) }
+func (h HStore) Delete(keys ...string) {
+	for _, k := range keys {
+		delete(h, k)
+	}
+}
+
+func (h *HStore) set(key string, value *string) {
+	if *h == nil {
+		*h = HStore{}
+	}
+	(*h)[key] = value
+}
+
+func quoteHStore(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// parseHStore parse hstore text format, quoted or bare keys and values separated by "=>" and ","
+func parseHStore(s string) (HStore, error) {
+	h := HStore{}
+	p := hstoreParser{s: s}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return h, nil
+		}
+		key, quoted, err := p.token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid hstore %q: %w", s, err)
+		}
+		if !quoted && strings.EqualFold(key, "NULL") {
+			return nil, fmt.Errorf("invalid hstore %q: NULL key", s)
+		}
+		p.skipSpace()
+		if !strings.HasPrefix(p.s[p.pos:], "=>") {
+			return nil, fmt.Errorf("invalid hstore %q: expect => at %d", s, p.pos)
+		}
+		p.pos += 2
+		p.skipSpace()
+		value, quoted, err := p.token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid hstore %q: %w", s, err)
+		}
+		if !quoted && strings.EqualFold(value, "NULL") {
+			h[key] = nil
+		} else {
+			h[key] = &value
+		}
+		p.skipSpace()
+		if p.eof() {
+			return h, nil
+		}
+		if p.s[p.pos] != ',' {
+			return nil, fmt.Errorf("invalid hstore %q: expect , at %d", s, p.pos)
+		}
+		p.pos++
+	}
+}
+
+type hstoreParser struct {
+	s   string
+	pos int
+}
+
+func (p *hstoreParser) eof() bool { return p.pos >= len(p.s) }
+
+func (p *hstoreParser) skipSpace() {
+	for !p.eof() && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *hstoreParser) token() (tok string, quoted bool, err error) {
+	if p.eof() {
+		return "", false, fmt.Errorf("unexpected end")
+	}
+	var b strings.Builder
+	if p.s[p.pos] == '"' {
+		for p.pos++; !p.eof(); p.pos++ {
+			switch c := p.s[p.pos]; c {
+			case '\\':
+				if p.pos++; p.eof() {
+					return "", true, fmt.Errorf("unexpected end")
+				}
+				b.WriteByte(p.s[p.pos])
+			case '"':
+				p.pos++
+				return b.String(), true, nil
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", true, fmt.Errorf("unterminated quote")
+	}
+	for ; !p.eof() && strings.IndexByte(" \t\r\n,=", p.s[p.pos]) < 0; p.pos++ {
+		if p.s[p.pos] == '\\' && p.pos+1 < len(p.s) {
+			p.pos++
+		}
+		b.WriteByte(p.s[p.pos])
+	}
+	if b.Len() == 0 {
+		return "", false, fmt.Errorf("empty token at %d", p.pos)
+	}
+	return b.String(), false, nil
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHStore_Scan(t *testing.T) {
+	var h HStore
+	if err := h.Scan([]byte(`"a"=>"1", "b"=>NULL, "q \"x\""=>"c:\\tmp", k=>v, "n"=>"NULL"`)); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if v, ok := h.Get("a"); !ok || v != "1" {
+		t.Errorf("a: got %q %v", v, ok)
+	}
+	if _, ok := h.Get("b"); ok || !h.Has("b") {
+		t.Errorf("b: expect NULL value, got %v", h["b"])
+	}
+	if v, _ := h.Get(`q "x"`); v != `c:\tmp` {
+		t.Errorf("escaped: got %q", v)
+	}
+	if v, _ := h.Get("k"); v != "v" {
+		t.Errorf("bare: got %q", v)
+	}
+	if v, ok := h.Get("n"); !ok || v != "NULL" {
+		t.Errorf("quoted NULL: got %q %v", v, ok)
+	}
+
+	want := `"a"=>"1", "b"=>NULL, "k"=>"v", "n"=>"NULL", "q \"x\""=>"c:\\tmp"`
+	if v, err := h.Value(); err != nil || v != want {
+		t.Errorf("value: expect %s, got %v %v", want, v, err)
+	}
+	var scanned HStore
+	if err := scanned.Scan(want); err != nil || scanned.String() != want {
+		t.Errorf("rescan: got %s %v", scanned, err)
+	}
+
+	for _, input := range []string{`"a"`, `"a"=>`, `"a"=>"1" "b"=>"2"`, `NULL=>"1"`, `"a=>"1"`} {
+		if err := h.Scan(input); err == nil {
+			t.Errorf("scan %q: expect error", input)
+		}
+	}
+	if err := h.Scan(nil); err != nil || h != nil {
+		t.Errorf("scan nil: got %v %v", h, err)
+	}
+	if v, _ := h.Value(); v != nil {
+		t.Errorf("value of nil: got %v", v)
+	}
+
+	h.Set("x", "1")
+	h.SetNull("y")
+	b, _ := json.Marshal(h)
+	if string(b) != `{"x":"1","y":null}` {
+		t.Errorf("marshal: got %s", b)
+	}
+}
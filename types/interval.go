@@ -0,0 +1,318 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	microsPerSecond = int64(time.Second / time.Microsecond)
+	microsPerMinute = 60 * microsPerSecond
+	microsPerHour   = 60 * microsPerMinute
+	microsPerDay    = 24 * microsPerHour
+)
+
+// Interval PostgreSQL interval, kept as months, days and microseconds like PostgreSQL does,
+// because length of month and day (daylight saving) depends on the time it is added to
+type Interval struct {
+	Months       int32
+	Days         int32
+	Microseconds int64
+}
+
+// NewInterval interval of months, days and duration
+//
+//	types.NewInterval(1, 15, 2*time.Hour) // 1 mon 15 days 02:00:00
+func NewInterval(months, days int32, d time.Duration) Interval {
+	return Interval{Months: months, Days: days, Microseconds: d.Microseconds()}
+}
+
+// IntervalOf interval of duration, e.g. IntervalOf(90*time.Minute) is 01:30:00
+func IntervalOf(d time.Duration) Interval { return Interval{Microseconds: d.Microseconds()} }
+
+// ParseInterval parse interval in PostgreSQL format, e.g. "1 year 2 mons 3 days 04:05:06",
+// "3 days 2 hours", "-00:00:01.5", or ISO 8601 format, e.g. "P1Y2M3DT4H5M6S"
+func ParseInterval(s string) (Interval, error) {
+	var i Interval
+	return i, i.parse(s)
+}
+
+func (Interval) GormDataType() string                          { return "interval" }
+func (Interval) GormDBDataType(*gorm.DB, *schema.Field) string { return "INTERVAL" }
+
+func (i *Interval) Scan(value interface{}) error {
+	s, ok := toString(value)
+	if !ok {
+		return fmt.Errorf("unsupported interval scan type %T", value)
+	}
+	return i.parse(s)
+}
+
+func (i Interval) Value() (driver.Value, error) { return i.String(), nil }
+
+// GormValuer
+func (i Interval) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	v, _ := i.Value()
+	return gorm.Expr("?", v)
+}
+
+// MarshalJSON encode as JSON string in PostgreSQL format
+func (i Interval) MarshalJSON() ([]byte, error) { return []byte(strconv.Quote(i.String())), nil }
+
+// UnmarshalJSON decode from JSON string, null keeps i unchanged
+func (i *Interval) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		return nil
+	}
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid interval %s", b)
+	}
+	return i.parse(s)
+}
+
+// String interval in PostgreSQL output format (IntervalStyle postgres), e.g. "1 year 2 mons 3 days 04:05:06"
+func (i Interval) String() string {
+	var parts []string
+	negative := false
+	unit := func(n int64, singular, plural string) {
+		if n == 0 {
+			return
+		}
+		name := plural
+		if n == 1 {
+			name = singular
+		}
+		s := strconv.FormatInt(n, 10)
+		if negative && n > 0 {
+			s = "+" + s
+		}
+		parts = append(parts, s+" "+name)
+		negative = negative || n < 0
+	}
+	unit(int64(i.Months/12), "year", "years")
+	unit(int64(i.Months%12), "mon", "mons")
+	unit(int64(i.Days), "day", "days")
+
+	if i.Microseconds != 0 || len(parts) == 0 {
+		us, sign := i.Microseconds, ""
+		if us < 0 {
+			us, sign = -us, "-"
+		} else if negative {
+			sign = "+"
+		}
+		clock := fmt.Sprintf("%s%02d:%02d:%02d", sign, us/microsPerHour, us%microsPerHour/microsPerMinute, us%microsPerMinute/microsPerSecond)
+		if frac := us % microsPerSecond; frac != 0 {
+			clock += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+		}
+		parts = append(parts, clock)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (i *Interval) parse(s string) error {
+	str := strings.ToLower(strings.TrimSpace(s))
+	if str == "" {
+		return fmt.Errorf("invalid interval %q", s)
+	}
+	var (
+		r   Interval
+		err error
+	)
+	if strings.HasPrefix(str, "p") || strings.HasPrefix(str, "-p") {
+		r, err = parseISOInterval(str)
+	} else {
+		r, err = parsePostgresInterval(str)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid interval %q: %w", s, err)
+	}
+	*i = r
+	return nil
+}
+
+// intervalUnits months, days and microseconds of unit
+var intervalUnits = map[string]Interval{
+	"millennium": {Months: 12000}, "century": {Months: 1200}, "decade": {Months: 120},
+	"year": {Months: 12}, "mon": {Months: 1}, "month": {Months: 1},
+	"week": {Days: 7}, "day": {Days: 1},
+	"hour": {Microseconds: microsPerHour}, "minute": {Microseconds: microsPerMinute}, "min": {Microseconds: microsPerMinute},
+	"second": {Microseconds: microsPerSecond}, "sec": {Microseconds: microsPerSecond},
+	"millisecond": {Microseconds: 1000}, "microsecond": {Microseconds: 1},
+}
+
+func parsePostgresInterval(str string) (Interval, error) {
+	str = strings.TrimSpace(strings.TrimPrefix(str, "@"))
+	ago := strings.HasSuffix(str, " ago")
+	str = strings.TrimSuffix(str, " ago")
+
+	var r Interval
+	fields := strings.Fields(str)
+	for k := 0; k < len(fields); k++ {
+		token := fields[k]
+		if strings.Contains(token, ":") {
+			us, err := parseClock(token)
+			if err != nil {
+				return r, err
+			}
+			r.Microseconds += us
+			continue
+		}
+		if k+1 >= len(fields) {
+			return r, fmt.Errorf("missing unit of %s", token)
+		}
+		k++
+		name := strings.TrimSuffix(fields[k], "s")
+		u, ok := intervalUnits[name]
+		if !ok {
+			return r, fmt.Errorf("unknown unit %s", fields[k])
+		}
+		if err := r.addUnit(token, u); err != nil {
+			return r, err
+		}
+	}
+	if ago {
+		r = r.Neg()
+	}
+	return r, nil
+}
+
+// addUnit add n units, fraction of months and days is carried to days and microseconds like PostgreSQL
+func (i *Interval) addUnit(n string, u Interval) error {
+	f, err := strconv.ParseFloat(n, 64)
+	if err != nil {
+		return err
+	}
+	months := f * float64(u.Months)
+	days := f*float64(u.Days) + (months-float64(int64(months)))*30
+	i.Months += int32(months)
+	i.Days += int32(days)
+	i.Microseconds += int64(f*float64(u.Microseconds)) + int64((days-float64(int64(days)))*float64(microsPerDay))
+	return nil
+}
+
+// parseClock [-+]hh:mm[:ss[.ffffff]]
+func parseClock(token string) (int64, error) {
+	sign := int64(1)
+	if strings.HasPrefix(token, "-") {
+		sign = -1
+	}
+	parts := strings.Split(strings.TrimLeft(token, "+-"), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %s", token)
+	}
+	var us int64
+	for k, unit := range []int64{microsPerHour, microsPerMinute} {
+		if k >= len(parts) {
+			break
+		}
+		n, err := strconv.ParseInt(parts[k], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		us += n * unit
+	}
+	if len(parts) == 3 {
+		sec, frac, _ := strings.Cut(parts[2], ".")
+		n, err := strconv.ParseInt(sec, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		us += n * microsPerSecond
+		if frac != "" {
+			frac = (frac + "000000")[:6]
+			f, err := strconv.ParseInt(frac, 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			us += f
+		}
+	}
+	return sign * us, nil
+}
+
+// parseISOInterval ISO 8601 duration, e.g. P1Y2M3DT4H5M6.5S, P-1Y-2M, PT-1H
+func parseISOInterval(str string) (Interval, error) {
+	var r Interval
+	negative := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "-"), "p")
+	date, clock, _ := strings.Cut(str, "t")
+	for _, part := range []struct {
+		s     string
+		units map[byte]Interval
+	}{
+		{date, map[byte]Interval{'y': intervalUnits["year"], 'm': intervalUnits["month"], 'w': intervalUnits["week"], 'd': intervalUnits["day"]}},
+		{clock, map[byte]Interval{'h': intervalUnits["hour"], 'm': intervalUnits["minute"], 's': intervalUnits["second"]}},
+	} {
+		s := part.s
+		for s != "" {
+			k := strings.IndexFunc(s, func(c rune) bool { return c >= 'a' && c <= 'z' })
+			if k <= 0 {
+				return r, fmt.Errorf("invalid ISO 8601 duration")
+			}
+			u, ok := part.units[s[k]]
+			if !ok {
+				return r, fmt.Errorf("unknown designator %c", s[k])
+			}
+			if err := r.addUnit(s[:k], u); err != nil {
+				return r, err
+			}
+			s = s[k+1:]
+		}
+	}
+	if negative {
+		r = r.Neg()
+	}
+	return r, nil
+}
+
+// Interval operations
+
+// Add i + other, each part is added separately
+func (i Interval) Add(other Interval) Interval {
+	return Interval{Months: i.Months + other.Months, Days: i.Days + other.Days, Microseconds: i.Microseconds + other.Microseconds}
+}
+
+// Sub i - other, each part is subtracted separately
+func (i Interval) Sub(other Interval) Interval { return i.Add(other.Neg()) }
+
+// Neg -i
+func (i Interval) Neg() Interval {
+	return Interval{Months: -i.Months, Days: -i.Days, Microseconds: -i.Microseconds}
+}
+
+// IsZero all parts are zero
+func (i Interval) IsZero() bool { return i == Interval{} }
+
+// AddTo t + i, months and days are added by calendar like PostgreSQL, e.g. 2024-01-31 + 1 mon is 2024-02-29
+func (i Interval) AddTo(t time.Time) time.Time {
+	y, m, d := t.Date()
+	months := int(m) - 1 + int(i.Months)
+	year, month := y+months/12, months%12
+	if month < 0 {
+		year, month = year-1, month+12
+	}
+	// clamp day to end of month instead of overflowing into next month like time.AddDate
+	if last := time.Date(year, time.Month(month+2), 0, 0, 0, 0, 0, t.Location()).Day(); d > last {
+		d = last
+	}
+	hour, min, sec := t.Clock()
+	t = time.Date(year, time.Month(month+1), d+int(i.Days), hour, min, sec, t.Nanosecond(), t.Location())
+	return t.Add(time.Duration(i.Microseconds) * time.Microsecond)
+}
+
+// Duration approximate duration with 30-day months and 24-hour days, as justify_interval does
+func (i Interval) Duration() time.Duration {
+	days := int64(i.Months)*30 + int64(i.Days)
+	return time.Duration(days*microsPerDay+i.Microseconds) * time.Microsecond
+}
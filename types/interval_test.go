@@ -0,0 +1,76 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestInterval_Parse(t *testing.T) {
+	for input, want := range map[string]Interval{
+		"1 year 2 mons 3 days 04:05:06": {Months: 14, Days: 3, Microseconds: 4*microsPerHour + 5*microsPerMinute + 6*microsPerSecond},
+		"-1 days +02:00:00":             {Days: -1, Microseconds: 2 * microsPerHour},
+		"-00:00:01.5":                   {Microseconds: -1500000},
+		"00:00:00":                      {},
+		"3 days 2 hours":                {Days: 3, Microseconds: 2 * microsPerHour},
+		"1.5 months":                    {Months: 1, Days: 15},
+		"2 weeks":                       {Days: 14},
+		"@ 1 day ago":                   {Days: -1},
+		"P1Y2M3DT4H5M6.5S":              {Months: 14, Days: 3, Microseconds: 4*microsPerHour + 5*microsPerMinute + 6500000},
+		"PT-90M":                        {Microseconds: -90 * microsPerMinute},
+	} {
+		got, err := ParseInterval(input)
+		if err != nil || got != want {
+			t.Errorf("parse %q: expect %+v, got %+v %v", input, want, got, err)
+		}
+	}
+	for _, input := range []string{"", "1", "1 fortnight", "1:2:3:4", "Pxyz", "abc day"} {
+		if _, err := ParseInterval(input); err == nil {
+			t.Errorf("parse %q: expect error", input)
+		}
+	}
+}
+
+func TestInterval_String(t *testing.T) {
+	for want, i := range map[string]Interval{
+		"1 year 2 mons 3 days 04:05:06": {Months: 14, Days: 3, Microseconds: 4*microsPerHour + 5*microsPerMinute + 6*microsPerSecond},
+		"-1 days +02:00:00":             {Days: -1, Microseconds: 2 * microsPerHour},
+		"-2 years -1 mons":              {Months: -25},
+		"00:00:00.25":                   {Microseconds: 250000},
+		"00:00:00":                      {},
+		"36:00:00":                      IntervalOf(36 * time.Hour),
+	} {
+		if got := i.String(); got != want {
+			t.Errorf("string of %+v: expect %q, got %q", i, want, got)
+		}
+		var scanned Interval
+		if err := scanned.Scan([]byte(i.String())); err != nil || scanned != i {
+			t.Errorf("scan %q: expect %+v, got %+v %v", i.String(), i, scanned, err)
+		}
+	}
+
+	b, _ := json.Marshal(struct{ Timeout Interval }{NewInterval(0, 1, 30*time.Minute)})
+	if string(b) != `{"Timeout":"1 day 00:30:00"}` {
+		t.Errorf("marshal: got %s", b)
+	}
+}
+
+func TestInterval_AddTo(t *testing.T) {
+	start := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		interval Interval
+		want     time.Time
+	}{
+		{NewInterval(1, 0, 0), time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC)},
+		{NewInterval(-2, 0, 0), time.Date(2023, 11, 30, 10, 0, 0, 0, time.UTC)},
+		{NewInterval(13, 1, time.Hour), time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC)},
+		{IntervalOf(-90 * time.Minute), time.Date(2024, 1, 31, 8, 30, 0, 0, time.UTC)},
+	} {
+		if got := tc.interval.AddTo(start); !got.Equal(tc.want) {
+			t.Errorf("%s + %s: expect %s, got %s", start, tc.interval, tc.want, got)
+		}
+	}
+	if d := NewInterval(1, 1, time.Hour).Duration(); d != 31*24*time.Hour+time.Hour {
+		t.Errorf("duration: got %s", d)
+	}
+}
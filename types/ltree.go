@@ -0,0 +1,119 @@
+package types
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// LTree PostgreSQL ltree (extension), label path separated by dots, e.g. Top.Science.Astronomy
+type LTree string
+
+func (LTree) GormDataType() string                          { return "ltree" }
+func (LTree) GormDBDataType(*gorm.DB, *schema.Field) string { return "LTREE" }
+
+func (l *LTree) Scan(value interface{}) error {
+	s, ok := toString(value)
+	if !ok {
+		return fmt.Errorf("unsupported ltree scan type %T", value)
+	}
+	*l = LTree(s)
+	return nil
+}
+func (l LTree) Value() (driver.Value, error) { return string(l), nil }
+
+func (l LTree) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	v, _ := l.Value()
+	return gorm.Expr("?", v)
+}
+
+// Constructors
+
+// NewLTree path of labels, label only contains letters, digits, underscore and hyphen
+func NewLTree(labels ...string) (LTree, error) {
+	for _, label := range labels {
+		if !isLTreeLabel(label) {
+			return "", fmt.Errorf("invalid ltree label %q", label)
+		}
+	}
+	return LTree(strings.Join(labels, ".")), nil
+}
+
+// ParseLTree validate path text
+func ParseLTree(s string) (LTree, error) {
+	if s == "" {
+		return "", nil
+	}
+	return NewLTree(strings.Split(s, ".")...)
+}
+
+// Labels labels of path, empty path has no label
+func (l LTree) Labels() []string {
+	if l == "" {
+		return nil
+	}
+	return strings.Split(string(l), ".")
+}
+
+// NLevel number of labels, like nlevel(ltree)
+func (l LTree) NLevel() int { return len(l.Labels()) }
+
+// Subpath labels from offset with length like subpath(ltree, offset, len),
+// negative offset counts from the end and negative length leaves that many labels off the end
+func (l LTree) Subpath(offset, length int) LTree {
+	labels := l.Labels()
+	n := len(labels)
+	if offset < 0 {
+		offset += n
+	}
+	end := n
+	if length < 0 {
+		end = n + length
+	} else if offset+length < n {
+		end = offset + length
+	}
+	if offset < 0 || offset >= end {
+		return ""
+	}
+	return LTree(strings.Join(labels[offset:end], "."))
+}
+
+// Parent path without last label, empty for root
+func (l LTree) Parent() LTree { return l.Subpath(0, -1) }
+
+// Child path with labels appended
+func (l LTree) Child(labels ...string) (LTree, error) {
+	child, err := NewLTree(labels...)
+	if err != nil || l == "" {
+		return child, err
+	}
+	if child == "" {
+		return l, nil
+	}
+	return l + "." + child, nil
+}
+
+// IsAncestorOf l is ancestor of other or equal, like ltree @> ltree
+func (l LTree) IsAncestorOf(other LTree) bool {
+	return l == "" || other == l || strings.HasPrefix(string(other), string(l)+".")
+}
+
+// IsDescendantOf l is descendant of other or equal, like ltree <@ ltree
+func (l LTree) IsDescendantOf(other LTree) bool { return other.IsAncestorOf(l) }
+
+func isLTreeLabel(label string) bool {
+	if label == "" {
+		return false
+	}
+	for _, c := range label {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package types
+
+import "testing"
+
+func TestLTree(t *testing.T) {
+	l, err := NewLTree("Top", "Science", "Astronomy")
+	if err != nil || l != "Top.Science.Astronomy" {
+		t.Fatalf("new: got %q %v", l, err)
+	}
+	for _, tc := range []struct {
+		offset, length int
+		want           LTree
+	}{
+		{0, 2, "Top.Science"},
+		{1, 5, "Science.Astronomy"},
+		{-1, 1, "Astronomy"},
+		{0, -1, "Top.Science"},
+		{3, 1, ""},
+	} {
+		if got := l.Subpath(tc.offset, tc.length); got != tc.want {
+			t.Errorf("subpath(%d,%d): expect %q, got %q", tc.offset, tc.length, tc.want, got)
+		}
+	}
+	if l.NLevel() != 3 || l.Parent() != "Top.Science" || LTree("").NLevel() != 0 {
+		t.Errorf("nlevel/parent: got %d %q", l.NLevel(), l.Parent())
+	}
+	if !LTree("Top.Science").IsAncestorOf(l) || LTree("Top.Sci").IsAncestorOf(l) || !l.IsDescendantOf("Top") {
+		t.Errorf("ancestor check failed")
+	}
+	if child, err := l.Child("Stars"); err != nil || child != "Top.Science.Astronomy.Stars" {
+		t.Errorf("child: got %q %v", child, err)
+	}
+	for _, s := range []string{"Top..Science", "Top.Sci ence", "Top.Science."} {
+		if _, err := ParseLTree(s); err == nil {
+			t.Errorf("parse %q: expect error", s)
+		}
+	}
+}
@@ -1,19 +1,75 @@
 package types
 
 import (
+	"bytes"
 	"context"
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
-// Money stored as string to preserve formatting/precision.
-type Money string
+// ErrMoneyOverflow result of money arithmetic is out of range of int64 minor units
+var ErrMoneyOverflow = errors.New("money out of range")
+
+// MoneyLocale output format of PostgreSQL money, decided by lc_monetary of the database
+type MoneyLocale struct {
+	Symbol       string // currency symbol, e.g. "$", "€"
+	DecimalPoint string // e.g. "." or ","
+	ThousandsSep string // e.g. "," or "."
+	FracDigits   int    // digits of minor units, e.g. 2 for cents, 0 for yen
+	SymbolAfter  bool   // symbol is placed after amount, e.g. "1.234,56 €"
+}
+
+var (
+	MoneyLocaleUS = MoneyLocale{Symbol: "$", DecimalPoint: ".", ThousandsSep: ",", FracDigits: 2}
+	MoneyLocaleGB = MoneyLocale{Symbol: "£", DecimalPoint: ".", ThousandsSep: ",", FracDigits: 2}
+	MoneyLocaleDE = MoneyLocale{Symbol: "€", DecimalPoint: ",", ThousandsSep: ".", FracDigits: 2, SymbolAfter: true}
+	MoneyLocaleFR = MoneyLocale{Symbol: "€", DecimalPoint: ",", ThousandsSep: "\u202f", FracDigits: 2, SymbolAfter: true} // narrow no-break space
+	MoneyLocaleJP = MoneyLocale{Symbol: "￥", DecimalPoint: ".", ThousandsSep: ",", FracDigits: 0}
+	MoneyLocaleCN = MoneyLocale{Symbol: "￥", DecimalPoint: ".", ThousandsSep: ",", FracDigits: 2}
+)
+
+var moneyLocales = map[string]MoneyLocale{
+	"c": MoneyLocaleUS, "posix": MoneyLocaleUS, "en_us": MoneyLocaleUS, "en_gb": MoneyLocaleGB,
+	"de_de": MoneyLocaleDE, "fr_fr": MoneyLocaleFR, "ja_jp": MoneyLocaleJP, "zh_cn": MoneyLocaleCN,
+}
+
+// LookupMoneyLocale known format of lc_monetary from `SHOW lc_monetary`, e.g. "de_DE.UTF-8"
+func LookupMoneyLocale(lcMonetary string) (MoneyLocale, bool) {
+	name, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(lcMonetary)), ".")
+	l, ok := moneyLocales[name]
+	return l, ok
+}
+
+// moneyLocale MoneyLocale of the process shared by all connections
+var moneyLocale atomic.Value
+
+func init() { moneyLocale.Store(MoneyLocaleUS) }
+
+// SetMoneyLocale set format used to scan and print money, it must match lc_monetary of the database,
+// MoneyLocaleUS by default. The locale is process-wide: set it once at init before Money is used,
+// values scanned or printed while it changes may mix the formats.
+func SetMoneyLocale(l MoneyLocale) { moneyLocale.Store(l) }
+
+// currentMoneyLocale locale set by SetMoneyLocale
+func currentMoneyLocale() MoneyLocale { return moneyLocale.Load().(MoneyLocale) }
+
+// Money PostgreSQL money, kept as integer minor units (e.g. cents) so arithmetic is exact.
+// The zero value is 0.
+type Money struct {
+	minor int64
+}
 
 func (Money) GormDataType() string                          { return "money" }
 func (Money) GormDBDataType(*gorm.DB, *schema.Field) string { return "MONEY" }
@@ -22,10 +78,15 @@ func (m *Money) Scan(value interface{}) error {
 	if !ok {
 		return fmt.Errorf("unsupported money scan type %T", value)
 	}
-	*m = Money(s)
-	return nil
+	return m.parse(s, currentMoneyLocale())
+}
+
+// Value plain amount with decimal point of the locale, accepted by money input of any lc_monetary
+func (m Money) Value() (driver.Value, error) {
+	l := currentMoneyLocale()
+	l.Symbol, l.ThousandsSep = "", ""
+	return m.format(l), nil
 }
-func (m Money) Value() (driver.Value, error) { return string(m), nil }
 
 // GormValuer
 func (m Money) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
@@ -33,184 +94,294 @@ func (m Money) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
 	return gorm.Expr("?", v)
 }
 
+// MarshalJSON encode as JSON string with "." decimal point, e.g. "1234.56"
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.format(m.plainLocale()))), nil
+}
+
+// UnmarshalJSON decode from JSON string or number with "." decimal point, null keeps m unchanged
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		return nil
+	}
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return err
+		}
+	}
+	return m.parse(s, m.plainLocale())
+}
+
 // Constructors
-func NewMoney(s string) Money { return Money(s) }
+
+// NewMoney parse money in format of current locale, e.g. "$1,234.56", "-12.30", "(5.00)"
+func NewMoney(s string) (Money, error) {
+	var m Money
+	return m, m.parse(s, currentMoneyLocale())
+}
+
+// MustMoney parse money in format of current locale, panic on invalid input
+func MustMoney(s string) Money {
+	m, err := NewMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// NewMoneyFromMinor money of minor units, e.g. NewMoneyFromMinor(1999) is $19.99
+func NewMoneyFromMinor(minor int64) Money { return Money{minor: minor} }
+
+// NewMoneyFromDecimal money of decimal amount rounded to minor units with mode
+func NewMoneyFromDecimal(d Decimal, mode RoundingMode) (Money, error) {
+	coef := d.Round(int32(currentMoneyLocale().FracDigits), mode).coefficient()
+	if !coef.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{minor: coef.Int64()}, nil
+}
 
 // Edit helpers
-func (m *Money) Set(s string) { *m = Money(s) }
 
-// Money operations
+// Set parse s in format of current locale
+func (m *Money) Set(s string) error { return m.parse(s, currentMoneyLocale()) }
+
+// parse amount of locale l, sign and parentheses are only allowed around the amount,
+// e.g. "-$1,234.56", "1.234,56 €-", "($5.00)", and the amount only consists of digits and separators
+func (m *Money) parse(s string, l MoneyLocale) error {
+	str := strings.TrimSpace(s)
+	if l.Symbol != "" {
+		str = strings.ReplaceAll(str, l.Symbol, "")
+	}
+	str = strings.TrimFunc(str, isMoneyAffix)
+	neg := false
+	if strings.HasPrefix(str, "(") && strings.HasSuffix(str, ")") {
+		neg, str = true, strings.TrimFunc(str[1:len(str)-1], isMoneyAffix)
+	}
+	switch {
+	case strings.HasPrefix(str, "-"):
+		neg, str = true, strings.TrimFunc(str[1:], isMoneyAffix)
+	case strings.HasPrefix(str, "+"):
+		str = strings.TrimFunc(str[1:], isMoneyAffix)
+	case strings.HasSuffix(str, "-"):
+		neg, str = true, strings.TrimFunc(str[:len(str)-1], isMoneyAffix)
+	}
+	// thousands separator of space is printed as space, no-break space or narrow no-break space by locales
+	spaceSep := l.ThousandsSep != "" && strings.TrimFunc(l.ThousandsSep, unicode.IsSpace) == ""
+
+	var intPart, fracPart strings.Builder
+	inFrac := false
+	for i := 0; i < len(str); {
+		rest := str[i:]
+		switch {
+		case l.DecimalPoint != "" && strings.HasPrefix(rest, l.DecimalPoint):
+			if inFrac {
+				return fmt.Errorf("invalid money %q", s)
+			}
+			inFrac = true
+			i += len(l.DecimalPoint)
+			continue
+		case l.ThousandsSep != "" && strings.HasPrefix(rest, l.ThousandsSep) && !inFrac && intPart.Len() > 0:
+			i += len(l.ThousandsSep)
+			continue
+		}
 
-// IsValid checks if the money value is a valid money format
-func (m Money) IsValid() bool {
-	s := string(m)
-	if s == "" {
-		return false
+		r, size := utf8.DecodeRuneInString(rest)
+		switch {
+		case r >= '0' && r <= '9':
+			if inFrac {
+				fracPart.WriteRune(r)
+			} else {
+				intPart.WriteRune(r)
+			}
+		case spaceSep && unicode.IsSpace(r) && !inFrac && intPart.Len() > 0:
+		default:
+			return fmt.Errorf("invalid money %q", s)
+		}
+		i += size
 	}
 
-	// Remove currency symbol and whitespace for validation
-	s = strings.TrimSpace(s)
-	if strings.HasPrefix(s, "$") {
-		s = strings.TrimPrefix(s, "$")
+	if intPart.Len()+fracPart.Len() == 0 {
+		return fmt.Errorf("invalid money %q", s)
+	}
+	if fracPart.Len() > l.FracDigits {
+		return fmt.Errorf("money %q has more than %d fractional digits", s, l.FracDigits)
 	}
-	if strings.HasPrefix(s, "£") {
-		s = strings.TrimPrefix(s, "£")
+	digits := intPart.String() + fracPart.String() + strings.Repeat("0", l.FracDigits-fracPart.Len())
+	if neg {
+		digits = "-" + digits
 	}
-	if strings.HasPrefix(s, "€") {
-		s = strings.TrimPrefix(s, "€")
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return ErrMoneyOverflow
 	}
+	m.minor = minor
+	return nil
+}
 
-	s = strings.TrimSpace(s)
-	s = strings.ReplaceAll(s, ",", "") // Remove thousands separators
+// isMoneyAffix space or currency symbol around amount
+func isMoneyAffix(r rune) bool { return unicode.IsSpace(r) || unicode.Is(unicode.Sc, r) }
 
-	_, err := strconv.ParseFloat(s, 64)
-	return err == nil
+// plainLocale format without symbol and grouping, "." as decimal point
+func (Money) plainLocale() MoneyLocale {
+	return MoneyLocale{DecimalPoint: ".", FracDigits: currentMoneyLocale().FracDigits}
 }
 
-// ToFloat64 converts the money value to float64
-func (m Money) ToFloat64() (float64, error) {
-	s := string(m)
-	if s == "" {
-		return 0, fmt.Errorf("empty money value")
+func (m Money) format(l MoneyLocale) string {
+	minor := new(big.Int).Abs(big.NewInt(m.minor)).String()
+	if pad := l.FracDigits + 1 - len(minor); pad > 0 {
+		minor = strings.Repeat("0", pad) + minor
 	}
+	intPart, fracPart := minor[:len(minor)-l.FracDigits], minor[len(minor)-l.FracDigits:]
 
-	// Remove currency symbols and formatting
-	s = strings.TrimSpace(s)
-	if strings.HasPrefix(s, "$") {
-		s = strings.TrimPrefix(s, "$")
+	var b strings.Builder
+	if m.minor < 0 {
+		b.WriteByte('-')
+	}
+	if !l.SymbolAfter {
+		b.WriteString(l.Symbol)
+	}
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(l.ThousandsSep)
+		}
+		b.WriteRune(c)
 	}
-	if strings.HasPrefix(s, "£") {
-		s = strings.TrimPrefix(s, "£")
+	if l.FracDigits > 0 {
+		b.WriteString(l.DecimalPoint + fracPart)
 	}
-	if strings.HasPrefix(s, "€") {
-		s = strings.TrimPrefix(s, "€")
+	if l.SymbolAfter && l.Symbol != "" {
+		b.WriteString(" " + l.Symbol)
 	}
+	return b.String()
+}
+
+// Money operations
 
-	s = strings.TrimSpace(s)
-	s = strings.ReplaceAll(s, ",", "") // Remove thousands separators
+// String money in format of current locale, e.g. "$1,234.56"
+func (m Money) String() string { return m.format(currentMoneyLocale()) }
 
-	return strconv.ParseFloat(s, 64)
+// Minor amount in minor units, e.g. cents
+func (m Money) Minor() int64 { return m.minor }
+
+// Decimal exact amount as Decimal, e.g. 1234.56
+func (m Money) Decimal() Decimal {
+	return NewDecimalFromInt(m.minor, int32(currentMoneyLocale().FracDigits))
 }
 
 // IsZero checks if the money value is zero
-func (m Money) IsZero() bool {
-	val, err := m.ToFloat64()
-	if err != nil {
-		return false
-	}
-	return val == 0.0
-}
+func (m Money) IsZero() bool { return m.minor == 0 }
 
 // IsPositive checks if the money value is positive
-func (m Money) IsPositive() bool {
-	val, err := m.ToFloat64()
-	if err != nil {
-		return false
-	}
-	return val > 0.0
-}
+func (m Money) IsPositive() bool { return m.minor > 0 }
 
 // IsNegative checks if the money value is negative
-func (m Money) IsNegative() bool {
-	val, err := m.ToFloat64()
-	if err != nil {
-		return false
-	}
-	return val < 0.0
-}
+func (m Money) IsNegative() bool { return m.minor < 0 }
 
 // Abs returns the absolute value of the money
 func (m Money) Abs() Money {
-	val, err := m.ToFloat64()
-	if err != nil {
-		return m
-	}
-
-	if val < 0 {
-		// Extract the format and make it positive
-		s := string(m)
-		if strings.Contains(s, "-") {
-			s = strings.ReplaceAll(s, "-", "")
-		}
-		return Money(s)
+	if m.minor < 0 {
+		return Money{minor: -m.minor}
 	}
-
 	return m
 }
 
 // Compare compares two money values (-1 if less, 0 if equal, 1 if greater)
-func (m Money) Compare(other Money) (int, error) {
-	val1, err := m.ToFloat64()
-	if err != nil {
-		return 0, err
-	}
-
-	val2, err := other.ToFloat64()
-	if err != nil {
-		return 0, err
-	}
-
-	if val1 < val2 {
-		return -1, nil
-	} else if val1 > val2 {
-		return 1, nil
+func (m Money) Compare(other Money) int {
+	switch {
+	case m.minor < other.minor:
+		return -1
+	case m.minor > other.minor:
+		return 1
 	}
-	return 0, nil
+	return 0
 }
 
 // Add adds another money value to this one
 func (m Money) Add(other Money) (Money, error) {
-	val1, err := m.ToFloat64()
-	if err != nil {
-		return "", err
-	}
-
-	val2, err := other.ToFloat64()
-	if err != nil {
-		return "", err
+	if (other.minor > 0 && m.minor > math.MaxInt64-other.minor) ||
+		(other.minor < 0 && m.minor < math.MinInt64-other.minor) {
+		return Money{}, ErrMoneyOverflow
 	}
-
-	result := val1 + val2
-	return Money(fmt.Sprintf("%.2f", result)), nil
+	return Money{minor: m.minor + other.minor}, nil
 }
 
 // Subtract subtracts another money value from this one
 func (m Money) Subtract(other Money) (Money, error) {
-	val1, err := m.ToFloat64()
-	if err != nil {
-		return "", err
+	if (other.minor < 0 && m.minor > math.MaxInt64+other.minor) ||
+		(other.minor > 0 && m.minor < math.MinInt64+other.minor) {
+		return Money{}, ErrMoneyOverflow
 	}
+	return Money{minor: m.minor - other.minor}, nil
+}
 
-	val2, err := other.ToFloat64()
-	if err != nil {
-		return "", err
+// Multiply multiplies the money value by an integer factor, e.g. quantity
+func (m Money) Multiply(factor int64) (Money, error) {
+	r := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(factor))
+	if !r.IsInt64() {
+		return Money{}, ErrMoneyOverflow
 	}
+	return Money{minor: r.Int64()}, nil
+}
 
-	result := val1 - val2
-	return Money(fmt.Sprintf("%.2f", result)), nil
+// MultiplyDecimal multiplies the money value by a decimal factor, e.g. tax rate, rounded to minor units with mode
+func (m Money) MultiplyDecimal(factor Decimal, mode RoundingMode) (Money, error) {
+	return NewMoneyFromDecimal(m.Decimal().Mul(factor), mode)
 }
 
-// Multiply multiplies the money value by a factor
-func (m Money) Multiply(factor float64) (Money, error) {
-	val, err := m.ToFloat64()
-	if err != nil {
-		return "", err
+// Allocate splits the money by ratios without losing minor units,
+// the remainder is spread one minor unit at a time from the first part
+//
+//	types.MustMoney("100.00").Allocate(1, 1, 1) // 33.34, 33.33, 33.33
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	var total int64
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("invalid allocation ratio %d", ratio)
+		}
+		total += int64(ratio)
+	}
+	if total == 0 {
+		return nil, errors.New("allocation ratios must not be all zero")
 	}
 
-	result := val * factor
-	return Money(fmt.Sprintf("%.2f", result)), nil
+	parts := make([]Money, len(ratios))
+	remainder := m.minor
+	for i, ratio := range ratios {
+		share := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(int64(ratio)))
+		parts[i].minor = share.Quo(share, big.NewInt(total)).Int64()
+		remainder -= parts[i].minor
+	}
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if ratios[i] > 0 {
+			parts[i].minor += unit
+			remainder -= unit
+		}
+	}
+	return parts, nil
 }
 
-// Clone creates a copy of the money value
-func (m Money) Clone() Money {
-	return Money(string(m))
+// Split splits the money into n parts differing by at most one minor unit
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of parts %d", n)
+	}
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
 }
 
+// Clone creates a copy of the money value
+func (m Money) Clone() Money { return m }
+
 // Equals checks if two money values are equal
-func (m Money) Equals(other Money) bool {
-	cmp, err := m.Compare(other)
-	if err != nil {
-		return false
-	}
-	return cmp == 0
-}
+func (m Money) Equals(other Money) bool { return m.minor == other.minor }
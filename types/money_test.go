@@ -0,0 +1,120 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func withMoneyLocale(t *testing.T, l MoneyLocale) {
+	old := currentMoneyLocale()
+	SetMoneyLocale(l)
+	t.Cleanup(func() { SetMoneyLocale(old) })
+}
+
+func TestMoney_Parse(t *testing.T) {
+	testcases := []struct {
+		locale MoneyLocale
+		input  string
+		minor  int64
+		output string
+	}{
+		{MoneyLocaleUS, "$1,234.56", 123456, "$1,234.56"},
+		{MoneyLocaleUS, "-$1,234.56", -123456, "-$1,234.56"},
+		{MoneyLocaleUS, "($5.00)", -500, "-$5.00"},
+		{MoneyLocaleUS, "12.3", 1230, "$12.30"},
+		{MoneyLocaleUS, "$0.05", 5, "$0.05"},
+		{MoneyLocaleUS, "$92,233,720,368,547,758.07", 9223372036854775807, "$92,233,720,368,547,758.07"},
+		{MoneyLocaleDE, "1.234,56 €", 123456, "1.234,56 €"},
+		{MoneyLocaleDE, "-1.234,56 €", -123456, "-1.234,56 €"},
+		{MoneyLocaleFR, "1 234,56 €", 123456, "1\u202f234,56 €"},
+		{MoneyLocaleJP, "￥1,235", 1235, "￥1,235"},
+		{MoneyLocaleUS, "$ 1,234.56-", -123456, "-$1,234.56"},
+		{MoneyLocaleUS, "+12.30", 1230, "$12.30"},
+		{MoneyLocaleDE, "1.234,56 €-", -123456, "-1.234,56 €"},
+		{MoneyLocaleFR, "-1\u00a0234,56 €", -123456, "-1\u202f234,56 €"},
+	}
+	for _, tc := range testcases {
+		withMoneyLocale(t, tc.locale)
+		var m Money
+		if err := m.Scan([]byte(tc.input)); err != nil || m.Minor() != tc.minor || m.String() != tc.output {
+			t.Errorf("scan %q: expect %d %s, got %d %s %v", tc.input, tc.minor, tc.output, m.Minor(), m, err)
+		}
+	}
+
+	withMoneyLocale(t, MoneyLocaleUS)
+	for _, input := range []string{"", "$", "abc", "1.2.3", "1.234", "$92,233,720,368,547,758.08", "1-2", "1 2 3", "--1", "-(1.00)-", "$1.00 $2.00", ",100"} {
+		if _, err := NewMoney(input); err == nil {
+			t.Errorf("parse %q: expect error", input)
+		}
+	}
+	if l, ok := LookupMoneyLocale("de_DE.UTF-8"); !ok || l != MoneyLocaleDE {
+		t.Errorf("lookup de_DE.UTF-8: got %+v %v", l, ok)
+	}
+}
+
+func TestMoney_Value(t *testing.T) {
+	withMoneyLocale(t, MoneyLocaleDE)
+	if v, _ := NewMoneyFromMinor(-123456).Value(); v != "-1234,56" {
+		t.Errorf("value in de_DE: expect -1234,56, got %v", v)
+	}
+
+	withMoneyLocale(t, MoneyLocaleUS)
+	if v, _ := NewMoneyFromMinor(123456).Value(); v != "1234.56" {
+		t.Errorf("value: expect 1234.56, got %v", v)
+	}
+	var out struct{ Price, Fee Money }
+	if err := json.Unmarshal([]byte(`{"Price":"19.99","Fee":0.5}`), &out); err != nil {
+		t.Fatalf("unmarshal fail: %s", err)
+	}
+	if b, _ := json.Marshal(out); string(b) != `{"Price":"19.99","Fee":"0.50"}` {
+		t.Errorf("marshal: got %s", b)
+	}
+
+	var arr Array[Money]
+	if err := arr.Scan(`{"$1,000.00","-$0.01"}`); err != nil || len(arr) != 2 || arr[1].Minor() != -1 {
+		t.Errorf("scan array: got %v %v", arr, err)
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	withMoneyLocale(t, MoneyLocaleUS)
+
+	// float64 loses cents on large sums, minor units do not
+	sum := Money{}
+	for i := 0; i < 1000; i++ {
+		var err error
+		if sum, err = sum.Add(MustMoney("$90,071,992,547.41")); err != nil {
+			t.Fatalf("add fail: %s", err)
+		}
+	}
+	if sum.String() != "$90,071,992,547,410.00" {
+		t.Errorf("sum: got %s", sum)
+	}
+	if _, err := NewMoneyFromMinor(1<<63 - 1).Add(NewMoneyFromMinor(1)); err != ErrMoneyOverflow {
+		t.Errorf("add overflow: expect ErrMoneyOverflow, got %v", err)
+	}
+	if d, _ := MustMoney("$10.00").Subtract(MustMoney("$10.01")); d.String() != "-$0.01" {
+		t.Errorf("subtract: got %s", d)
+	}
+	if p, _ := MustMoney("$19.99").Multiply(3); p.String() != "$59.97" {
+		t.Errorf("multiply: got %s", p)
+	}
+	if tax, _ := MustMoney("$19.99").MultiplyDecimal(MustDecimal("0.0825"), RoundHalfUp); tax.String() != "$1.65" {
+		t.Errorf("multiply decimal: got %s", tax)
+	}
+
+	parts, err := MustMoney("$100.00").Split(3)
+	if err != nil || len(parts) != 3 || parts[0].String() != "$33.34" || parts[1].String() != "$33.33" || parts[2].String() != "$33.33" {
+		t.Errorf("split: got %v %v", parts, err)
+	}
+	parts, _ = MustMoney("-$0.05").Allocate(1, 0, 1)
+	if parts[0].Minor() != -3 || parts[1].Minor() != 0 || parts[2].Minor() != -2 {
+		t.Errorf("allocate negative: got %v", parts)
+	}
+	if _, err := MustMoney("$1").Allocate(0, 0); err == nil {
+		t.Errorf("allocate all zero ratios: expect error")
+	}
+	if MustMoney("$1.00").Compare(MustMoney("$0.99")) != 1 || !MustMoney("-$1").Abs().Equals(MustMoney("$1")) {
+		t.Errorf("compare money")
+	}
+}
@@ -0,0 +1,129 @@
+package gen
+
+import (
+	"reflect"
+
+	"gorm.io/gorm/clause"
+
+	"go.ipao.vip/gen/field"
+)
+
+// Upsert INSERT ... ON CONFLICT builder, created by Upsert of DO
+//
+//	u := query.User
+//	info, err := u.Upsert(users...).OnConflict(u.Email).
+//		DoUpdate(u.Name, u.UpdatedAt.Value(time.Now())).
+//		Where(u.Deleted.Is(false)).
+//		Returning(u.ID).
+//		Exec()
+type Upsert struct {
+	do       *DO
+	values   interface{}
+	conflict clause.OnConflict
+	columns  []clause.Column // RETURNING columns
+	err      error
+}
+
+// Upsert insert values, conflicting rows are handled as configured by OnConflict, DoUpdate and DoNothing
+func (d *DO) Upsert(values interface{}) *Upsert {
+	return &Upsert{do: d, values: values}
+}
+
+// OnConflict conflict target columns, a unique index or constraint must exist on them
+func (u *Upsert) OnConflict(columns ...field.Expr) *Upsert {
+	u.conflict.OnConstraint = ""
+	u.conflict.Columns = make([]clause.Column, len(columns))
+	for i, column := range columns {
+		u.conflict.Columns[i] = clause.Column{Name: column.ColumnName().String()}
+	}
+	return u
+}
+
+// OnConstraint conflict target by constraint name, i.e. ON CONFLICT ON CONSTRAINT name
+func (u *Upsert) OnConstraint(name string) *Upsert {
+	u.conflict.Columns = nil
+	u.conflict.OnConstraint = u.do.Quote(name)
+	return u
+}
+
+// TargetWhere predicate of partial unique index of conflict target
+func (u *Upsert) TargetWhere(conds ...Condition) *Upsert {
+	exprs, err := condToExpression(conds)
+	if err != nil {
+		u.err = err
+	}
+	u.conflict.TargetWhere = clause.Where{Exprs: exprs}
+	return u
+}
+
+// DoNothing skip conflicting rows
+func (u *Upsert) DoNothing() *Upsert {
+	u.conflict.DoNothing = true
+	u.conflict.DoUpdates = nil
+	return u
+}
+
+// DoUpdate update conflicting rows, a column is set to its EXCLUDED value,
+// assignments like u.UpdatedAt.Value(now) or u.Count.SetCol(u.Count.AddCol(field.Excluded(u.Count))) are set as given
+func (u *Upsert) DoUpdate(columns ...field.AssignExpr) *Upsert {
+	u.conflict.DoNothing = false
+	for _, column := range columns {
+		if column == nil {
+			continue
+		}
+		col := clause.Column{Name: column.ColumnName().String()}
+		switch e := column.AssignExpr().(type) {
+		case clause.Eq:
+			u.conflict.DoUpdates = append(u.conflict.DoUpdates, clause.Assignment{Column: col, Value: e.Value})
+		case clause.Set:
+			u.conflict.DoUpdates = append(u.conflict.DoUpdates, e...)
+		case clause.Expr:
+			u.conflict.DoUpdates = append(u.conflict.DoUpdates, clause.Assignment{Column: col, Value: e})
+		default: // column
+			u.conflict.DoUpdates = append(u.conflict.DoUpdates, clause.Assignment{Column: col, Value: clause.Column{Table: "excluded", Name: col.Name}})
+		}
+	}
+	return u
+}
+
+// Where condition of DO UPDATE, conflicting rows not matched are left unchanged
+func (u *Upsert) Where(conds ...Condition) *Upsert {
+	exprs, err := condToExpression(conds)
+	if err != nil {
+		u.err = err
+	}
+	u.conflict.Where = clause.Where{Exprs: append(u.conflict.Where.Exprs, exprs...)}
+	return u
+}
+
+// Returning columns scanned back into values, primary key and default values are returned without it
+func (u *Upsert) Returning(columns ...field.Expr) *Upsert {
+	for _, column := range columns {
+		u.columns = append(u.columns, clause.Column{Name: column.ColumnName().String()})
+	}
+	return u
+}
+
+// Exec execute upsert, DO NOTHING is used when neither DoUpdate nor DoNothing is set
+func (u *Upsert) Exec() (info ResultInfo, err error) {
+	if u.err != nil {
+		return ResultInfo{Error: u.err}, u.err
+	}
+	if v := reflect.ValueOf(u.values); u.values == nil || v.Kind() == reflect.Slice && v.Len() == 0 {
+		return info, nil
+	}
+	result := u.do.db.Clauses(u.clauses()...).Create(u.values)
+	return ResultInfo{RowsAffected: result.RowsAffected, Error: result.Error}, result.Error
+}
+
+func (u *Upsert) clauses() []clause.Expression {
+	conflict := u.conflict
+	if len(conflict.DoUpdates) == 0 {
+		conflict.DoNothing = true
+	}
+	conds := []clause.Expression{conflict}
+	if len(u.columns) > 0 {
+		conds = append(conds, clause.Returning{Columns: u.columns})
+	}
+	return conds
+}